@@ -0,0 +1,23 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/pkg/index"
+)
+
+func main() {
+	m := index.NewURLMigration()
+
+	startTime := time.Now()
+
+	if err := m.Run(); err != nil {
+		logger.Error("Failed to migrate profile URLs: ", err)
+		os.Exit(1)
+	}
+
+	duration := time.Since(startTime)
+	logger.Info("Profile URL migration run duration: " + duration.String())
+}
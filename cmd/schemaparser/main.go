@@ -1,6 +1,8 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"time"
 
@@ -8,7 +10,28 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/pkg/schemaparser"
 )
 
+const usage = `Usage: schemaparser [command]
+
+Without a command, the schemas are updated if the sources changed.
+
+Commands:
+  status             Print the state of the updates as JSON
+  clear-error        Clear the error stopping the updates
+  resync [version]   Update the schemas from the sources at the version,
+                     or at their current version, and clear the error
+  rollback           Update the schemas from the previous version and
+                     hold the updates until the error is cleared
+`
+
 func main() {
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			logger.Error("Failed to run SchemaParser command "+os.Args[1], err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	startTime := time.Now()
 
 	s := schemaparser.NewCronJob()
@@ -21,3 +44,43 @@ func main() {
 	duration := time.Since(startTime)
 	logger.Info("SchemaParser run duration: " + duration.String())
 }
+
+// runCommand runs the admin command with its arguments.
+func runCommand(command string, args []string) error {
+	var version string
+	switch {
+	case command == "resync" && len(args) <= 1:
+		if len(args) == 1 {
+			version = args[0]
+		}
+	case command == "status" || command == "clear-error" || command == "rollback":
+		if len(args) > 0 {
+			fmt.Print(usage)
+			return fmt.Errorf("unexpected arguments %v", args)
+		}
+	default:
+		fmt.Print(usage)
+		return fmt.Errorf("unknown command or arguments")
+	}
+
+	s := schemaparser.NewCronJob()
+	switch command {
+	case "status":
+		status, err := s.Status()
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	case "clear-error":
+		return s.ClearError()
+	case "resync":
+		return s.Resync(version)
+	default:
+		return s.Rollback()
+	}
+}
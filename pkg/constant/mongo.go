@@ -1,17 +1,27 @@
 package constant
 
 var MongoIndex = struct {
-	Node    string
-	Schema  string
-	Mapping string
-	Profile string
-	Update  string
-	Batch   string
+	Node           string
+	Schema         string
+	Field          string
+	Mapping        string
+	Profile        string
+	Update         string
+	Batch          string
+	Revalidation   string
+	SchemaRevision string
+	SchemaLoad     string
+	Vocabulary     string
 }{
-	Node:    "nodes",
-	Schema:  "schemas",
-	Mapping: "mappings",
-	Profile: "profiles",
-	Update:  "updates",
-	Batch:   "batches",
+	Node:           "nodes",
+	Schema:         "schemas",
+	Field:          "fields",
+	Mapping:        "mappings",
+	Profile:        "profiles",
+	Update:         "updates",
+	Batch:          "batches",
+	Revalidation:   "revalidations",
+	SchemaRevision: "schema_revisions",
+	SchemaLoad:     "schema_loads",
+	Vocabulary:     "vocabularies",
 }
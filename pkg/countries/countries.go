@@ -4,9 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
 )
 
 var ErrCountryCodeNotFound = errors.New("country code not found")
@@ -15,13 +15,14 @@ func FindAlpha2ByName(
 	countryURL string,
 	country interface{},
 ) (countryCode string, err error) {
-	res, err := http.Get(countryURL)
+	// The country map is served by the library, usually an internal service.
+	fetcher := httputil.NewFetcher(httputil.FetcherOptions{
+		AllowedHosts: httputil.Hostnames(countryURL),
+	})
+	countries, _, err := fetcher.FetchJSON(countryURL)
 	if err != nil {
-		fmt.Println("Get country map failed")
-		return "", err
+		return "", fmt.Errorf("failed to get country map: %w", err)
 	}
-	defer res.Body.Close()
-	countries, err := io.ReadAll(res.Body)
 
 	var countryNames map[string][]string
 
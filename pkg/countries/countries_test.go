@@ -13,6 +13,7 @@ func TestFindAlpha2ByName(t *testing.T) {
 	mockServer := httptest.NewServer(
 		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 			// Return a sample response
+			w.Header().Set("Content-Type", "application/json")
 			response := `{"TD": ["chad"]}`
 			_, err := w.Write([]byte(response))
 			require.NoError(t, err)
@@ -0,0 +1,251 @@
+package elastic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"go.uber.org/zap"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+)
+
+// Bulk actions reported in a BulkItemFailure.
+const (
+	BulkActionIndex  = "index"
+	BulkActionUpdate = "update"
+	BulkActionDelete = "delete"
+)
+
+// BulkOptions configures a BulkProcessor. Zero values fall back to the
+// defaults below.
+type BulkOptions struct {
+	// Name identifies the processor in logs.
+	Name string
+	// Workers is the number of concurrent bulk requests.
+	Workers int
+	// BulkActions flushes after this many queued actions.
+	BulkActions int
+	// BulkSize flushes after this many bytes of queued actions.
+	BulkSize int
+	// FlushInterval flushes queued actions at least this often.
+	FlushInterval time.Duration
+	// OnFailure is called once for every item Elasticsearch rejected.
+	OnFailure func(failure BulkItemFailure)
+}
+
+// BulkItemFailure describes a single action of a bulk request that failed.
+type BulkItemFailure struct {
+	Action string
+	Index  string
+	ID     string
+	Status int
+	Reason string
+}
+
+func (f BulkItemFailure) Error() string {
+	return fmt.Sprintf(
+		"bulk %s of %s/%s failed with status %d: %s",
+		f.Action,
+		f.Index,
+		f.ID,
+		f.Status,
+		f.Reason,
+	)
+}
+
+// BulkProcessor queues index, update and delete actions and sends them to
+// Elasticsearch in batches. Failures are reported per item through
+// BulkOptions.OnFailure.
+type BulkProcessor interface {
+	Index(index string, id string, doc interface{})
+	Update(index string, id string, update map[string]interface{})
+	Delete(index string, id string)
+	// Flush sends all queued actions and waits for them to complete.
+	Flush() error
+	// Close flushes the queue and stops the processor.
+	Close() error
+}
+
+const (
+	defaultBulkWorkers       = 1
+	defaultBulkActions       = 500
+	defaultBulkSize          = 5 << 20
+	defaultBulkFlushInterval = time.Second
+)
+
+func (o *BulkOptions) setDefaults() {
+	if o.Name == "" {
+		o.Name = "bulk-processor"
+	}
+	if o.Workers <= 0 {
+		o.Workers = defaultBulkWorkers
+	}
+	if o.BulkActions <= 0 {
+		o.BulkActions = defaultBulkActions
+	}
+	if o.BulkSize <= 0 {
+		o.BulkSize = defaultBulkSize
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = defaultBulkFlushInterval
+	}
+}
+
+type bulkProcessor struct {
+	processor *elastic.BulkProcessor
+}
+
+// NewBulkProcessor starts a BulkProcessor. Callers must Close it to send the
+// remaining queued actions.
+func (c *esClient) NewBulkProcessor(opts BulkOptions) (BulkProcessor, error) {
+	opts.setDefaults()
+
+	processor, err := c.client.BulkProcessor().
+		Name(opts.Name).
+		Workers(opts.Workers).
+		BulkActions(opts.BulkActions).
+		BulkSize(opts.BulkSize).
+		FlushInterval(opts.FlushInterval).
+		After(afterBulk(opts)).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error starting bulk processor: %w", err)
+	}
+
+	return &bulkProcessor{processor: processor}, nil
+}
+
+func (p *bulkProcessor) Index(index string, id string, doc interface{}) {
+	p.processor.Add(
+		elastic.NewBulkIndexRequest().Index(index).Id(id).Doc(doc),
+	)
+}
+
+func (p *bulkProcessor) Update(
+	index string,
+	id string,
+	update map[string]interface{},
+) {
+	p.processor.Add(
+		elastic.NewBulkUpdateRequest().Index(index).Id(id).Doc(update),
+	)
+}
+
+func (p *bulkProcessor) Delete(index string, id string) {
+	p.processor.Add(elastic.NewBulkDeleteRequest().Index(index).Id(id))
+}
+
+func (p *bulkProcessor) Flush() error {
+	return p.processor.Flush()
+}
+
+func (p *bulkProcessor) Close() error {
+	return p.processor.Close()
+}
+
+// afterBulk returns the callback invoked after every bulk request. It turns
+// the response into per-item failures for BulkOptions.OnFailure.
+func afterBulk(opts BulkOptions) elastic.BulkAfterFunc {
+	return func(
+		_ int64,
+		requests []elastic.BulkableRequest,
+		response *elastic.BulkResponse,
+		err error,
+	) {
+		failures := bulkFailures(requests, response, err)
+		for _, failure := range failures {
+			logger.Error(
+				fmt.Sprintf("Error in %s", opts.Name),
+				failure,
+				zap.String("index", failure.Index),
+				zap.String("id", failure.ID),
+			)
+			if opts.OnFailure != nil {
+				opts.OnFailure(failure)
+			}
+		}
+	}
+}
+
+// bulkFailures collects the failed items of a bulk request. When the request
+// as a whole failed, every queued action is reported as failed. Updates and
+// deletes of missing documents are not failures, matching Update and Delete.
+func bulkFailures(
+	requests []elastic.BulkableRequest,
+	response *elastic.BulkResponse,
+	err error,
+) []BulkItemFailure {
+	if err != nil {
+		failures := make([]BulkItemFailure, 0, len(requests))
+		status := 0
+		var esErr *elastic.Error
+		if errors.As(err, &esErr) {
+			status = esErr.Status
+		}
+		for _, request := range requests {
+			failure := requestFailure(request)
+			failure.Status = status
+			failure.Reason = err.Error()
+			failures = append(failures, failure)
+		}
+		return failures
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	var failures []BulkItemFailure
+	for _, item := range response.Items {
+		for action, result := range item {
+			if result == nil || (result.Status >= 200 && result.Status <= 299) {
+				continue
+			}
+			if result.Status == http.StatusNotFound &&
+				(action == BulkActionUpdate || action == BulkActionDelete) {
+				continue
+			}
+			failure := BulkItemFailure{
+				Action: action,
+				Index:  result.Index,
+				ID:     result.Id,
+				Status: result.Status,
+			}
+			if result.Error != nil {
+				failure.Reason = result.Error.Reason
+			}
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+// requestFailure reads the action and target document of a queued request
+// from its action line, e.g. {"index":{"_index":"nodes","_id":"..."}}.
+func requestFailure(request elastic.BulkableRequest) BulkItemFailure {
+	var failure BulkItemFailure
+
+	lines, err := request.Source()
+	if err != nil || len(lines) == 0 {
+		return failure
+	}
+
+	var meta map[string]struct {
+		Index string `json:"_index"`
+		ID    string `json:"_id"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &meta); err != nil {
+		return failure
+	}
+	for action, target := range meta {
+		failure.Action = action
+		failure.Index = target.Index
+		failure.ID = target.ID
+	}
+	return failure
+}
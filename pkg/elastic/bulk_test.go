@@ -0,0 +1,68 @@
+package elastic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkFailures(t *testing.T) {
+	response := &elastic.BulkResponse{
+		Items: []map[string]*elastic.BulkResponseItem{
+			{"index": {Index: "nodes", Id: "a", Status: 201}},
+			{"index": {
+				Index:  "nodes",
+				Id:     "b",
+				Status: 400,
+				Error:  &elastic.ErrorDetails{Reason: "mapper_parsing_exception"},
+			}},
+			{"update": {Index: "nodes", Id: "c", Status: 404}},
+			{"delete": {Index: "nodes", Id: "d", Status: 404}},
+			{"update": {Index: "nodes", Id: "e", Status: 429}},
+		},
+	}
+
+	failures := bulkFailures(nil, response, nil)
+
+	require.Equal(t, []BulkItemFailure{
+		{
+			Action: BulkActionIndex,
+			Index:  "nodes",
+			ID:     "b",
+			Status: 400,
+			Reason: "mapper_parsing_exception",
+		},
+		{
+			Action: BulkActionUpdate,
+			Index:  "nodes",
+			ID:     "e",
+			Status: 429,
+		},
+	}, failures)
+}
+
+func TestBulkFailuresRequestError(t *testing.T) {
+	requests := []elastic.BulkableRequest{
+		elastic.NewBulkIndexRequest().Index("nodes").Id("a").Doc(map[string]string{}),
+		elastic.NewBulkDeleteRequest().Index("nodes").Id("b"),
+	}
+
+	failures := bulkFailures(requests, nil, errors.New("connection refused"))
+
+	require.Equal(t, []BulkItemFailure{
+		{
+			Action: BulkActionIndex,
+			Index:  "nodes",
+			ID:     "a",
+			Reason: "connection refused",
+		},
+		{
+			Action: BulkActionDelete,
+			Index:  "nodes",
+			ID:     "b",
+			Reason: "connection refused",
+		},
+	}, failures)
+}
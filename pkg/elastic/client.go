@@ -2,6 +2,7 @@ package elastic
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/olivere/elastic/v7"
@@ -48,21 +49,51 @@ func (c *esClient) CreateMappings(indices []Index) error {
 		if err != nil {
 			return err
 		}
-		if !exists {
-			createIndex, err := c.client.CreateIndex(index.Name).
-				BodyString(index.Body).
-				Do(context.Background())
-			if err != nil {
-				return err
-			}
-			if !createIndex.Acknowledged {
-				return err
+		if exists {
+			// Map the fields added since the index was created. Documents
+			// indexed before are only mapped once they are reindexed.
+			if err := c.putProperties(index); err != nil {
+				logger.Error(
+					fmt.Sprintf("Failed to update the mappings of Index: %s", index.Name),
+					err,
+				)
 			}
+			continue
+		}
+		createIndex, err := c.client.CreateIndex(index.Name).
+			BodyString(index.Body).
+			Do(context.Background())
+		if err != nil {
+			return err
+		}
+		if !createIndex.Acknowledged {
+			return err
 		}
 	}
 	return nil
 }
 
+// putProperties adds the properties of the index body to the mapping of the
+// existing index.
+func (c *esClient) putProperties(index Index) error {
+	var body struct {
+		Mappings struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"mappings"`
+	}
+	if err := json.Unmarshal([]byte(index.Body), &body); err != nil {
+		return err
+	}
+	if len(body.Mappings.Properties) == 0 {
+		return nil
+	}
+	_, err := c.client.PutMapping().
+		Index(index.Name).
+		BodyJson(map[string]interface{}{"properties": body.Mappings.Properties}).
+		Do(context.Background())
+	return err
+}
+
 func (c *esClient) Index(
 	index string,
 	doc interface{},
@@ -191,6 +222,28 @@ func (c *esClient) UpdateMany(index string, q *Query, update map[string]interfac
 	return nil
 }
 
+// Reindex indexes the documents matching the query again, in the
+// background, so that they are mapped with the fields added to the mapping
+// since they were indexed.
+func (c *esClient) Reindex(index string, q *Query) error {
+	ctx := context.Background()
+	_, err := c.client.UpdateByQuery(index).
+		Query(q.Query).
+		ProceedOnVersionConflict().
+		DoAsync(ctx)
+	if err != nil {
+		logger.Error(
+			fmt.Sprintf(
+				"Error when trying to reindex documents in Index: %s",
+				index,
+			),
+			err,
+		)
+		return err
+	}
+	return nil
+}
+
 func (c *esClient) Delete(index string, id string) error {
 	ctx := context.Background()
 	_, err := c.client.Delete().
@@ -22,10 +22,12 @@ type esClientInterface interface {
 	Search(string, *Query) (*elastic.SearchResult, error)
 	Update(string, string, map[string]interface{}) error
 	UpdateMany(string, *Query, map[string]interface{}) error
+	Reindex(string, *Query) error
 	Delete(string, string) error
 	DeleteMany(string, *Query) error
 	Export(string, *Query, []interface{}) (*elastic.SearchResult, error)
 	GetNodes(string, *Query) (*elastic.SearchResult, error)
+	NewBulkProcessor(BulkOptions) (BulkProcessor, error)
 	Ping() error
 
 	GetClient() *elastic.Client
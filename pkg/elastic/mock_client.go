@@ -60,6 +60,10 @@ func (*mockClient) UpdateMany(
 	return nil
 }
 
+func (*mockClient) Reindex(_ string, _ *Query) error {
+	return nil
+}
+
 func (*mockClient) Delete(_ string, _ string) error {
 	return nil
 }
@@ -82,3 +86,31 @@ func (*mockClient) GetNodes(
 ) (*elastic.SearchResult, error) {
 	return nil, nil
 }
+
+func (*mockClient) NewBulkProcessor(_ BulkOptions) (BulkProcessor, error) {
+	return &mockBulkProcessor{}, nil
+}
+
+type mockBulkProcessor struct {
+}
+
+func (*mockBulkProcessor) Index(_ string, _ string, _ interface{}) {
+}
+
+func (*mockBulkProcessor) Update(
+	_ string,
+	_ string,
+	_ map[string]interface{},
+) {
+}
+
+func (*mockBulkProcessor) Delete(_ string, _ string) {
+}
+
+func (*mockBulkProcessor) Flush() error {
+	return nil
+}
+
+func (*mockBulkProcessor) Close() error {
+	return nil
+}
@@ -45,6 +45,31 @@ func NewWildcardQuery(name, wildcard string) *elastic.WildcardQuery {
 	return q
 }
 
+// NewTermsQuery matches the documents whose field is exactly one of the
+// values.
+func NewTermsQuery(name string, values ...string) *elastic.TermsQuery {
+	terms := make([]interface{}, len(values))
+	for i, value := range values {
+		terms[i] = value
+	}
+	return elastic.NewTermsQuery(name, terms...)
+}
+
 func NewExistQuery(name string) *elastic.ExistsQuery {
 	return elastic.NewExistsQuery(name)
 }
+
+// NewFieldValueScoreQuery multiplies the scores of the query by the log of
+// the value of a numeric field, so documents with higher values rank higher.
+// Documents without the field score 0.
+func NewFieldValueScoreQuery(query elastic.Query, field string) *elastic.FunctionScoreQuery {
+	return elastic.NewFunctionScoreQuery().
+		Query(query).
+		AddScoreFunc(
+			elastic.NewFieldValueFactorFunction().
+				Field(field).
+				Modifier("log1p").
+				Missing(0),
+		).
+		BoostMode("multiply")
+}
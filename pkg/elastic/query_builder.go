@@ -60,6 +60,14 @@ func (b *QueryBuilder) BuildRangeQueryLte(field string, value *int64) {
 	}
 }
 
+// BuildRangeFilter generates a range filter with the given field and minimum
+// value. Unlike a range query, it doesn't affect the scores.
+func (b *QueryBuilder) BuildRangeFilter(field string, value *int64) {
+	if value != nil {
+		b.AddFilter(NewRangeQuery(field).Gte(*value))
+	}
+}
+
 // BuildMatchQuery generates a match query with the given field.
 func (b *QueryBuilder) BuildMatchQuery(field string, value *string) {
 	if value != nil {
@@ -0,0 +1,535 @@
+package httputil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+)
+
+const (
+	// DefaultMaxBodySize is the default maximum size of a fetched document.
+	DefaultMaxBodySize = 2 << 20
+	// DefaultMaxJSONDepth is the default maximum nesting depth of a fetched
+	// JSON document.
+	DefaultMaxJSONDepth = 64
+	// defaultFetchTimeout is the default timeout of a fetch, including
+	// redirects and reading the body.
+	defaultFetchTimeout = 10 * time.Second
+)
+
+// blockedPrefixes lists the networks, besides the ones covered by the
+// netip.Addr methods, that user-submitted URLs must not reach.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// BlockedAddressError is returned when a URL, or a URL it redirects to,
+// points to a private, loopback, link-local or otherwise reserved address.
+type BlockedAddressError struct {
+	Host string
+}
+
+// Error conforms to go conventions.
+func (e BlockedAddressError) Error() string {
+	return fmt.Sprintf("host %s resolves to a blocked address", e.Host)
+}
+
+// BodyTooLargeError is returned when a response is larger than allowed.
+type BodyTooLargeError struct {
+	Limit int64
+}
+
+// Error conforms to go conventions.
+func (e BodyTooLargeError) Error() string {
+	return fmt.Sprintf("response body is larger than %d bytes", e.Limit)
+}
+
+// JSONDepthError is returned when a JSON document is nested deeper than
+// allowed.
+type JSONDepthError struct {
+	Limit int
+}
+
+// Error conforms to go conventions.
+func (e JSONDepthError) Error() string {
+	return fmt.Sprintf("JSON document is nested deeper than %d levels", e.Limit)
+}
+
+// ContentTypeError is returned when a response isn't declared as JSON.
+type ContentTypeError struct {
+	ContentType string
+}
+
+// Error conforms to go conventions.
+func (e ContentTypeError) Error() string {
+	return fmt.Sprintf("unexpected content type %q", e.ContentType)
+}
+
+// InvalidJSONError is returned when a response body isn't valid JSON.
+type InvalidJSONError struct {
+	Err error
+}
+
+// Error conforms to go conventions.
+func (e InvalidJSONError) Error() string {
+	return "invalid JSON: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e InvalidJSONError) Unwrap() error {
+	return e.Err
+}
+
+// FetcherOptions configures a Fetcher. Zero values use the defaults.
+type FetcherOptions struct {
+	// Timeout of a fetch, including redirects and reading the body.
+	Timeout time.Duration
+	// MaxBodySize is the maximum size of the response body in bytes.
+	MaxBodySize int64
+	// MaxJSONDepth is the maximum nesting depth of the JSON document.
+	MaxJSONDepth int
+	// AllowedHosts lists host names, such as internal services, that may be
+	// reached even though they resolve to private addresses.
+	AllowedHosts []string
+}
+
+// Fetcher fetches JSON documents from user-submitted URLs. It refuses to
+// connect to private and reserved addresses, checking the resolved address
+// of every connection, redirects included, and limits the size and depth of
+// the documents it reads.
+type Fetcher struct {
+	opts         FetcherOptions
+	allowedHosts map[string]bool
+	transport    *http.Transport
+}
+
+// NewFetcher creates a new Fetcher.
+func NewFetcher(opts FetcherOptions) *Fetcher {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultFetchTimeout
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = DefaultMaxBodySize
+	}
+	if opts.MaxJSONDepth <= 0 {
+		opts.MaxJSONDepth = DefaultMaxJSONDepth
+	}
+
+	f := &Fetcher{
+		opts:         opts,
+		allowedHosts: make(map[string]bool, len(opts.AllowedHosts)),
+	}
+	for _, host := range opts.AllowedHosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" {
+			f.allowedHosts[host] = true
+		}
+	}
+
+	f.transport = &http.Transport{
+		// Proxies from the environment would bypass the address checks.
+		Proxy:                 nil,
+		DialContext:           f.dialContext,
+		TLSHandshakeTimeout:   opts.Timeout,
+		ResponseHeaderTimeout: opts.Timeout,
+		// Fetched URLs rarely share a host, and short-lived fetchers must not
+		// leave idle connections behind.
+		DisableKeepAlives: true,
+	}
+	return f
+}
+
+// Hostnames returns the host names of the given URLs, skipping the ones that
+// can't be parsed. It is meant to build FetcherOptions.AllowedHosts from
+// configured service URLs.
+func Hostnames(rawURLs ...string) []string {
+	hosts := make([]string, 0, len(rawURLs))
+	for _, rawURL := range rawURLs {
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		hosts = append(hosts, u.Hostname())
+	}
+	return hosts
+}
+
+// FetchJSON fetches the JSON document at source and returns it compacted,
+// along with the redirects followed. Responses with an error status code
+// return a StatusError.
+func (f *Fetcher) FetchJSON(source string) ([]byte, *FetchInfo, error) {
+	return f.fetchJSON(source, nil)
+}
+
+// FetchJSONIfModified works like FetchJSON but makes a conditional request
+// with the validators of a previous response. If the document is unchanged,
+// no data is returned and info.NotModified is true.
+func (f *Fetcher) FetchJSONIfModified(
+	source string,
+	etag string,
+	lastModified string,
+) ([]byte, *FetchInfo, error) {
+	header := http.Header{}
+	if etag != "" {
+		header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		header.Set("If-Modified-Since", lastModified)
+	}
+	return f.fetchJSON(source, header)
+}
+
+func (f *Fetcher) fetchJSON(
+	source string,
+	header http.Header,
+) ([]byte, *FetchInfo, error) {
+	info := &FetchInfo{FinalURL: source}
+	client := f.newClient(info)
+
+	req, err := http.NewRequest(http.MethodGet, source, nil)
+	if err != nil {
+		return nil, info, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, info, err
+	}
+	defer resp.Body.Close()
+
+	info.StatusCode = resp.StatusCode
+	info.FinalURL = resp.Request.URL.String()
+	info.ETag = resp.Header.Get("ETag")
+	info.LastModified = resp.Header.Get("Last-Modified")
+
+	if resp.StatusCode == http.StatusNotModified {
+		info.NotModified = true
+		return nil, info, nil
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, info, StatusError{
+			URL:        source,
+			StatusCode: resp.StatusCode,
+			RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if !isJSONContentType(contentType) {
+		return nil, info, ContentTypeError{ContentType: contentType}
+	}
+
+	if resp.ContentLength > f.opts.MaxBodySize {
+		return nil, info, BodyTooLargeError{Limit: f.opts.MaxBodySize}
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.opts.MaxBodySize+1))
+	if err != nil {
+		return nil, info, err
+	}
+	if int64(len(data)) > f.opts.MaxBodySize {
+		return nil, info, BodyTooLargeError{Limit: f.opts.MaxBodySize}
+	}
+
+	if err := checkJSONDepth(data, f.opts.MaxJSONDepth); err != nil {
+		return nil, info, err
+	}
+
+	buffer := bytes.Buffer{}
+	if err := json.Compact(&buffer, data); err != nil {
+		return nil, info, InvalidJSONError{Err: err}
+	}
+	return buffer.Bytes(), info, nil
+}
+
+// CheckURL checks that the page at source, e.g. a website, responds with a
+// success status code, without reading it. It makes a HEAD request, then a
+// GET request if the server doesn't support HEAD. Responses with an error
+// status code return a StatusError.
+func (f *Fetcher) CheckURL(source string) (*FetchInfo, error) {
+	info, err := f.request(http.MethodHead, source)
+	if err == nil && (info.StatusCode == http.StatusMethodNotAllowed ||
+		info.StatusCode == http.StatusNotImplemented) {
+		info, err = f.request(http.MethodGet, source)
+	}
+	if err != nil {
+		return info, err
+	}
+	if info.StatusCode >= http.StatusBadRequest {
+		return info, StatusError{URL: source, StatusCode: info.StatusCode}
+	}
+	return info, nil
+}
+
+// request makes a request without reading the response body.
+func (f *Fetcher) request(method, source string) (*FetchInfo, error) {
+	info := &FetchInfo{FinalURL: source}
+
+	req, err := http.NewRequest(method, source, nil)
+	if err != nil {
+		return info, err
+	}
+	resp, err := f.newClient(info).Do(req)
+	if err != nil {
+		return info, err
+	}
+	resp.Body.Close()
+
+	info.StatusCode = resp.StatusCode
+	info.FinalURL = resp.Request.URL.String()
+	return info, nil
+}
+
+// newClient returns a client that follows redirects to http(s) URLs, up to
+// maxRedirects, and records them in info.
+func (f *Fetcher) newClient(info *FetchInfo) *http.Client {
+	return &http.Client{
+		Transport: f.transport,
+		Timeout:   f.opts.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+			}
+			info.Redirects = append(info.Redirects, Redirect{
+				URL:        via[len(via)-1].URL.String(),
+				StatusCode: req.Response.StatusCode,
+			})
+			return nil
+		},
+	}
+}
+
+// FetchJSONStr works like FetchJSON but returns the document as a string.
+func (f *Fetcher) FetchJSONStr(source string) (string, *FetchInfo, error) {
+	data, info, err := f.FetchJSON(source)
+	if err != nil {
+		return "", info, err
+	}
+	return string(data), info, nil
+}
+
+// FetchJSONStrIfModified works like FetchJSONIfModified but returns the
+// document as a string.
+func (f *Fetcher) FetchJSONStrIfModified(
+	source string,
+	etag string,
+	lastModified string,
+) (string, *FetchInfo, error) {
+	data, info, err := f.FetchJSONIfModified(source, etag, lastModified)
+	if err != nil {
+		return "", info, err
+	}
+	return string(data), info, nil
+}
+
+// dialContext connects to addr unless it resolves to a blocked address. The
+// connection is made to the address that was checked, so the host can't
+// resolve to another address in between.
+func (f *Fetcher) dialContext(
+	ctx context.Context,
+	network, addr string,
+) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: f.opts.Timeout}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if f.allowedHosts[strings.ToLower(host)] {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ip := range ips {
+		if isBlockedAddr(ip) {
+			return nil, BlockedAddressError{Host: host}
+		}
+	}
+
+	var lastErr error
+	for _, ip := range ips {
+		conn, err := dialer.DialContext(
+			ctx,
+			network,
+			net.JoinHostPort(ip.Unmap().String(), port),
+		)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// isBlockedAddr reports whether ip is an address user-submitted URLs must
+// not reach.
+func isBlockedAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() {
+		return true
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONContentType reports whether contentType is application/json or a
+// JSON-based media type such as application/ld+json.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" ||
+		strings.HasPrefix(mediaType, "application/") &&
+			strings.HasSuffix(mediaType, "+json")
+}
+
+// checkJSONDepth returns a JSONDepthError if the objects and arrays of data
+// are nested deeper than limit. It doesn't validate the document otherwise.
+func checkJSONDepth(data []byte, limit int) error {
+	depth := 0
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > limit {
+				return JSONDepthError{Limit: limit}
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return nil
+}
+
+// FetchErrorToJSONAPI describes an error returned by a Fetcher as a failure
+// reason for the given URL.
+func FetchErrorToJSONAPI(err error, source string) []jsonapi.Error {
+	var (
+		blockedErr     BlockedAddressError
+		tooLargeErr    BodyTooLargeError
+		depthErr       JSONDepthError
+		contentTypeErr ContentTypeError
+		invalidJSONErr InvalidJSONError
+		statusErr      StatusError
+		title, detail  string
+		status         int
+	)
+
+	switch {
+	case errors.As(err, &blockedErr):
+		title = "Profile URL Not Allowed"
+		detail = fmt.Sprintf(
+			"The profile_url points to a private or reserved network address: %s",
+			source,
+		)
+		status = http.StatusBadRequest
+	case errors.As(err, &tooLargeErr):
+		title = "Profile Too Large"
+		detail = fmt.Sprintf(
+			"The profile at %s is larger than the maximum of %d bytes.",
+			source,
+			tooLargeErr.Limit,
+		)
+		status = http.StatusBadRequest
+	case errors.As(err, &depthErr):
+		title = "Profile Too Deeply Nested"
+		detail = fmt.Sprintf(
+			"The profile at %s is nested deeper than the maximum of %d levels.",
+			source,
+			depthErr.Limit,
+		)
+		status = http.StatusBadRequest
+	case errors.As(err, &contentTypeErr):
+		title = "Invalid Content Type"
+		detail = fmt.Sprintf(
+			"The profile at %s must be served as application/json, but it was served as %q.",
+			source,
+			contentTypeErr.ContentType,
+		)
+		status = http.StatusBadRequest
+	case errors.As(err, &invalidJSONErr):
+		title = "Invalid JSON"
+		detail = fmt.Sprintf(
+			"The profile at %s is not valid JSON: %s",
+			source,
+			invalidJSONErr.Err,
+		)
+		status = http.StatusBadRequest
+	case errors.As(err, &statusErr) &&
+		statusErr.StatusCode >= http.StatusInternalServerError:
+		title = "Profile Unavailable"
+		detail = fmt.Sprintf(
+			"The profile_url %s responded with status code %d. Please try again later.",
+			source,
+			statusErr.StatusCode,
+		)
+		status = http.StatusBadGateway
+	case errors.As(err, &statusErr):
+		title = "Profile Not Found"
+		detail = fmt.Sprintf(
+			"The profile_url %s responded with status code %d.",
+			source,
+			statusErr.StatusCode,
+		)
+		status = http.StatusNotFound
+	default:
+		title = "Profile Not Found"
+		detail = fmt.Sprintf(
+			"Could not find or read from the profile_url: %s",
+			source,
+		)
+		status = http.StatusNotFound
+	}
+
+	return jsonapi.NewError(
+		[]string{title},
+		[]string{detail},
+		nil,
+		[]int{status},
+	)
+}
@@ -0,0 +1,304 @@
+package httputil_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
+)
+
+func newTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/profile.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{ "name": "test" }`))
+	})
+	mux.Handle("/moved", http.RedirectHandler("/moved-again", http.StatusMovedPermanently))
+	mux.Handle("/moved-again", http.RedirectHandler("/profile.json", http.StatusPermanentRedirect))
+	mux.Handle("/temporary", http.RedirectHandler("/moved", http.StatusFound))
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	mux.HandleFunc("/text", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`{ "name": "test" }`))
+	})
+	mux.HandleFunc("/ld.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/ld+json; charset=utf-8")
+		_, _ = w.Write([]byte(`{ "name": "test" }`))
+	})
+	mux.HandleFunc("/large.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{ "name": "` + strings.Repeat("a", 100) + `" }`))
+	})
+	mux.HandleFunc("/deep.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{ "a": [[[{ "b": "]]]]" }]]] }`))
+	})
+	mux.HandleFunc("/invalid.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{ "name": `))
+	})
+	mux.HandleFunc("/etag.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{ "name": "test" }`))
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html></html>`))
+	})
+	mux.HandleFunc("/busy", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	return httptest.NewServer(mux)
+}
+
+// newTestFetcher returns a fetcher that may reach the local test server.
+func newTestFetcher() *httputil.Fetcher {
+	return httputil.NewFetcher(httputil.FetcherOptions{
+		MaxBodySize:  64,
+		MaxJSONDepth: 4,
+		AllowedHosts: []string{"127.0.0.1"},
+	})
+}
+
+func TestFetchJSONStr(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	body, info, err := newTestFetcher().FetchJSONStr(server.URL + "/profile.json")
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"test"}`, body)
+	require.Equal(t, server.URL+"/profile.json", info.FinalURL)
+	require.Empty(t, info.Redirects)
+	require.False(t, info.IsPermanentlyMoved())
+}
+
+func TestFetchJSONStrPermanentRedirects(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	body, info, err := newTestFetcher().FetchJSONStr(server.URL + "/moved")
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"test"}`, body)
+	require.Equal(t, server.URL+"/profile.json", info.FinalURL)
+	require.Equal(t, []httputil.Redirect{
+		{URL: server.URL + "/moved", StatusCode: http.StatusMovedPermanently},
+		{URL: server.URL + "/moved-again", StatusCode: http.StatusPermanentRedirect},
+	}, info.Redirects)
+	require.True(t, info.IsPermanentlyMoved())
+}
+
+func TestFetchJSONStrTemporaryRedirect(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	_, info, err := newTestFetcher().FetchJSONStr(server.URL + "/temporary")
+	require.NoError(t, err)
+	require.Len(t, info.Redirects, 3)
+	require.False(t, info.IsPermanentlyMoved())
+}
+
+func TestFetchJSONStrGone(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	_, info, err := newTestFetcher().FetchJSONStr(server.URL + "/gone")
+	var statusErr httputil.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusGone, statusErr.StatusCode)
+	require.Equal(t, http.StatusGone, info.StatusCode)
+}
+
+func TestFetchJSONStrBlockedAddress(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	fetcher := httputil.NewFetcher(httputil.FetcherOptions{})
+	_, _, err := fetcher.FetchJSONStr(server.URL + "/profile.json")
+	var blockedErr httputil.BlockedAddressError
+	require.ErrorAs(t, err, &blockedErr)
+	require.Equal(t, "127.0.0.1", blockedErr.Host)
+}
+
+func TestFetchJSONStrBlockedRedirect(t *testing.T) {
+	server := httptest.NewServer(
+		http.RedirectHandler("http://169.254.169.254/latest/meta-data", http.StatusFound),
+	)
+	defer server.Close()
+
+	_, _, err := newTestFetcher().FetchJSONStr(server.URL)
+	var blockedErr httputil.BlockedAddressError
+	require.ErrorAs(t, err, &blockedErr)
+	require.Equal(t, "169.254.169.254", blockedErr.Host)
+}
+
+func TestFetchJSONStrErrors(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		path   string
+		target interface{}
+		title  string
+	}{
+		{
+			name:   "Content type",
+			path:   "/text",
+			target: &httputil.ContentTypeError{},
+			title:  "Invalid Content Type",
+		},
+		{
+			name:   "Body size",
+			path:   "/large.json",
+			target: &httputil.BodyTooLargeError{},
+			title:  "Profile Too Large",
+		},
+		{
+			name:   "JSON depth",
+			path:   "/deep.json",
+			target: &httputil.JSONDepthError{},
+			title:  "Profile Too Deeply Nested",
+		},
+		{
+			name:   "Invalid JSON",
+			path:   "/invalid.json",
+			target: &httputil.InvalidJSONError{},
+			title:  "Invalid JSON",
+		},
+		{
+			name:   "Status code",
+			path:   "/missing",
+			target: &httputil.StatusError{},
+			title:  "Profile Not Found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := newTestFetcher().FetchJSONStr(server.URL + tt.path)
+			require.ErrorAs(t, err, tt.target)
+
+			reasons := httputil.FetchErrorToJSONAPI(err, server.URL+tt.path)
+			require.Len(t, reasons, 1)
+			require.Equal(t, tt.title, reasons[0].Title)
+		})
+	}
+}
+
+func TestFetchJSONStrJSONMediaType(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	body, _, err := newTestFetcher().FetchJSONStr(server.URL + "/ld.json")
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"test"}`, body)
+}
+
+func TestFetchJSONStrIfModified(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	fetcher := newTestFetcher()
+
+	body, info, err := fetcher.FetchJSONStrIfModified(server.URL+"/etag.json", "", "")
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"test"}`, body)
+	require.Equal(t, `"v1"`, info.ETag)
+	require.False(t, info.NotModified)
+
+	body, info, err = fetcher.FetchJSONStrIfModified(server.URL+"/etag.json", `"v1"`, "")
+	require.NoError(t, err)
+	require.Empty(t, body)
+	require.True(t, info.NotModified)
+	require.Equal(t, http.StatusNotModified, info.StatusCode)
+}
+
+func TestFetchJSONStrRetryAfter(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	_, _, err := newTestFetcher().FetchJSONStr(server.URL + "/busy")
+	var statusErr httputil.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	require.Equal(t, 2*time.Minute, statusErr.RetryAfter)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "Empty", value: "", expected: 0},
+		{name: "Seconds", value: "30", expected: 30 * time.Second},
+		{name: "Negative seconds", value: "-5", expected: 0},
+		{
+			name:     "HTTP date",
+			value:    "Mon, 01 Jan 2024 12:01:30 GMT",
+			expected: 90 * time.Second,
+		},
+		{name: "Past date", value: "Mon, 01 Jan 2024 11:00:00 GMT", expected: 0},
+		{name: "Invalid", value: "soon", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, httputil.ParseRetryAfter(tt.value, now))
+		})
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	fetcher := newTestFetcher()
+
+	tests := []struct {
+		name       string
+		path       string
+		statusCode int
+		expectErr  bool
+	}{
+		{name: "success", path: "/text", statusCode: http.StatusOK},
+		{name: "redirect", path: "/temporary", statusCode: http.StatusOK},
+		{name: "HEAD not allowed", path: "/no-head", statusCode: http.StatusOK},
+		{name: "gone", path: "/gone", statusCode: http.StatusGone, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := fetcher.CheckURL(server.URL + tt.path)
+			if tt.expectErr {
+				var statusErr httputil.StatusError
+				require.ErrorAs(t, err, &statusErr)
+				require.Equal(t, tt.statusCode, statusErr.StatusCode)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.statusCode, info.StatusCode)
+		})
+	}
+
+	_, err := httputil.NewFetcher(httputil.FetcherOptions{}).CheckURL(server.URL + "/text")
+	var blockedErr httputil.BlockedAddressError
+	require.ErrorAs(t, err, &blockedErr)
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -125,3 +127,84 @@ func GetJSONStr(source string) (string, error) {
 	}
 	return buffer.String(), nil
 }
+
+// maxRedirects is the number of redirects followed before giving up, the
+// same limit as the default http.Client.
+const maxRedirects = 10
+
+// Redirect describes a redirect response followed while fetching a URL.
+type Redirect struct {
+	// URL is the URL that responded with the redirect.
+	URL string `json:"url"`
+	// StatusCode is the status code of the redirect response.
+	StatusCode int `json:"status_code"`
+}
+
+// FetchInfo describes how a URL was fetched.
+type FetchInfo struct {
+	// StatusCode is the status code of the final response.
+	StatusCode int
+	// FinalURL is the URL of the final response.
+	FinalURL string
+	// Redirects lists the redirects followed, in order.
+	Redirects []Redirect
+	// ETag is the entity tag of the final response, if any.
+	ETag string
+	// LastModified is the Last-Modified header of the final response, if any.
+	LastModified string
+	// NotModified is true if a conditional request found the resource
+	// unchanged.
+	NotModified bool
+}
+
+// IsPermanentlyMoved reports whether redirects were followed and all of them
+// were permanent (301 or 308), meaning the resource has a new URL.
+func (i *FetchInfo) IsPermanentlyMoved() bool {
+	if len(i.Redirects) == 0 {
+		return false
+	}
+	for _, redirect := range i.Redirects {
+		if redirect.StatusCode != http.StatusMovedPermanently &&
+			redirect.StatusCode != http.StatusPermanentRedirect {
+			return false
+		}
+	}
+	return true
+}
+
+// StatusError is returned when a URL responds with an error status code.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	// RetryAfter is the delay requested by the Retry-After header, if any.
+	RetryAfter time.Duration
+}
+
+// Error conforms to go conventions.
+func (e StatusError) Error() string {
+	return fmt.Sprintf(
+		"error the requested URL %s returned status code %d",
+		e.URL,
+		e.StatusCode,
+	)
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date, into a delay. It returns 0 if the value
+// is empty, invalid or in the past.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,28 @@
+package i18n
+
+// Codes of the validation messages. They are stable, so that messages stored
+// with their parameters can be rendered in any language later.
+const (
+	CodeInvalidType          = "invalid_type"
+	CodeNumberGTE            = "number_gte"
+	CodeNumberGT             = "number_gt"
+	CodeNumberLTE            = "number_lte"
+	CodeNumberLT             = "number_lt"
+	CodeRequired             = "required"
+	CodeDependentRequired    = "dependent_required"
+	CodeArrayMinItems        = "array_min_items"
+	CodeArrayMaxItems        = "array_max_items"
+	CodePattern              = "pattern"
+	CodeEnum                 = "enum"
+	CodeUnique               = "unique"
+	CodeStringLTE            = "string_lte"
+	CodeStringGTE            = "string_gte"
+	CodeFormat               = "format"
+	CodeAdditionalProperty   = "additional_property_not_allowed"
+	CodeAnyOf                = "any_of"
+	CodeInvalidValue         = "invalid_value"
+	CodeDeprecated           = "deprecated"
+	CodeRecommended          = "recommended"
+	CodeSchemaLoadFailed     = "schema_load_failed"
+	CodeDocumentNotValidated = "document_not_validated"
+)
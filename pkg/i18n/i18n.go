@@ -0,0 +1,94 @@
+// Package i18n renders coded messages, such as validation errors, in the
+// language requested by the client.
+package i18n
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+)
+
+// DefaultLanguage is the language messages are rendered in when the client
+// doesn't ask for a supported one.
+const DefaultLanguage = "en"
+
+// message is the template of a message. Parameters are written as {name}.
+type message struct {
+	Title  string
+	Detail string
+}
+
+// catalogs maps the supported languages to their messages by code.
+var catalogs = map[string]map[string]message{
+	"en": messagesEN,
+	"es": messagesES,
+	"fr": messagesFR,
+}
+
+// supported lists the supported languages, the default first.
+var supported = []language.Tag{
+	language.English,
+	language.Spanish,
+	language.French,
+}
+
+var matcher = language.NewMatcher(supported)
+
+// MatchLanguage returns the supported language that best matches the value
+// of an Accept-Language header, or DefaultLanguage.
+func MatchLanguage(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return DefaultLanguage
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return DefaultLanguage
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLanguage
+	}
+	base, _ := supported[index].Base()
+	return base.String()
+}
+
+// Render returns the title and detail of the message with the given code in
+// the given language, falling back to DefaultLanguage. Unknown codes are
+// returned as the title, with an empty detail.
+func Render(lang, code string, params map[string]string) (string, string) {
+	msg, ok := catalogs[lang][code]
+	if !ok {
+		msg, ok = catalogs[DefaultLanguage][code]
+	}
+	if !ok {
+		return code, ""
+	}
+
+	replacements := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+	replacer := strings.NewReplacer(replacements...)
+	return replacer.Replace(msg.Title), replacer.Replace(msg.Detail)
+}
+
+// Localize returns a copy of the errors with the titles and details of the
+// coded ones rendered in the given language. Errors without a code are kept
+// as they are.
+func Localize(errors []jsonapi.Error, lang string) []jsonapi.Error {
+	if errors == nil {
+		return nil
+	}
+	localized := make([]jsonapi.Error, len(errors))
+	for i, e := range errors {
+		if e.Code != "" {
+			if _, ok := catalogs[DefaultLanguage][e.Code]; ok {
+				e.Title, e.Detail = Render(lang, e.Code, e.Meta)
+			}
+		}
+		localized[i] = e
+	}
+	return localized
+}
@@ -0,0 +1,116 @@
+package i18n_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/i18n"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+)
+
+func TestMatchLanguage(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{name: "Empty header", acceptLanguage: "", expected: "en"},
+		{name: "Supported language", acceptLanguage: "es", expected: "es"},
+		{name: "Regional variant", acceptLanguage: "fr-CA", expected: "fr"},
+		{
+			name:           "Quality values",
+			acceptLanguage: "de;q=0.9, es;q=0.8, en;q=0.5",
+			expected:       "es",
+		},
+		{name: "Unsupported language", acceptLanguage: "ja", expected: "en"},
+		{name: "Invalid header", acceptLanguage: ";;;", expected: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, i18n.MatchLanguage(tt.acceptLanguage))
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	params := map[string]string{"property": "name", "schema": "test"}
+
+	tests := []struct {
+		name           string
+		lang           string
+		code           string
+		expectedTitle  string
+		expectedDetail string
+	}{
+		{
+			name:           "English",
+			lang:           "en",
+			code:           i18n.CodeRequired,
+			expectedTitle:  "Missing Required Property",
+			expectedDetail: "The `name` property is required - Schema: test",
+		},
+		{
+			name:           "Spanish",
+			lang:           "es",
+			code:           i18n.CodeRequired,
+			expectedTitle:  "Falta una propiedad obligatoria",
+			expectedDetail: "La propiedad `name` es obligatoria - Esquema: test",
+		},
+		{
+			name:           "Unsupported language",
+			lang:           "ja",
+			code:           i18n.CodeRequired,
+			expectedTitle:  "Missing Required Property",
+			expectedDetail: "The `name` property is required - Schema: test",
+		},
+		{
+			name:          "Unknown code",
+			lang:          "fr",
+			code:          "number_multiple_of",
+			expectedTitle: "number_multiple_of",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, detail := i18n.Render(tt.lang, tt.code, params)
+			require.Equal(t, tt.expectedTitle, title)
+			require.Equal(t, tt.expectedDetail, detail)
+		})
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	errors := []jsonapi.Error{
+		{
+			Title:  "Missing Required Property",
+			Detail: "The `name` property is required - Schema: test",
+			Status: 400,
+			Code:   i18n.CodeRequired,
+			Meta:   map[string]string{"property": "name", "schema": "test"},
+		},
+		{
+			Title:  "Invalid Profile URL",
+			Detail: "The profile URL is invalid.",
+			Status: 400,
+		},
+	}
+
+	localized := i18n.Localize(errors, "fr")
+	require.Len(t, localized, 2)
+	require.Equal(t, "Propriété obligatoire manquante", localized[0].Title)
+	require.Equal(
+		t,
+		"La propriété `name` est obligatoire - Schéma : test",
+		localized[0].Detail,
+	)
+	require.Equal(t, i18n.CodeRequired, localized[0].Code)
+	// Errors without a code are kept as they are.
+	require.Equal(t, errors[1], localized[1])
+	// The original errors are left untouched.
+	require.Equal(t, "Missing Required Property", errors[0].Title)
+
+	require.Nil(t, i18n.Localize(nil, "fr"))
+}
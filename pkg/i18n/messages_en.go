@@ -0,0 +1,92 @@
+package i18n
+
+var messagesEN = map[string]message{
+	CodeInvalidType: {
+		"Invalid Type",
+		"Expected: {expected} - Given: {given} - Schema: {schema}",
+	},
+	CodeNumberGTE: {
+		"Invalid Amount",
+		"Amount must be greater than or equal to {min} - Schema: {schema}",
+	},
+	CodeNumberGT: {
+		"Invalid Amount",
+		"Amount must be greater than {min} - Schema: {schema}",
+	},
+	CodeNumberLTE: {
+		"Invalid Amount",
+		"Amount must be less than or equal to {max} - Schema: {schema}",
+	},
+	CodeNumberLT: {
+		"Invalid Amount",
+		"Amount must be less than {max} - Schema: {schema}",
+	},
+	CodeRequired: {
+		"Missing Required Property",
+		"The `{property}` property is required - Schema: {schema}",
+	},
+	CodeDependentRequired: {
+		"Missing Required Property",
+		"The `{property}` property is required when `{dependency}` is present - Schema: {schema}",
+	},
+	CodeArrayMinItems: {
+		"Not Enough Items",
+		"There are not enough items in the array - Minimum is {min} - Schema: {schema}",
+	},
+	CodeArrayMaxItems: {
+		"Too Many Items",
+		"There are too many items in the array - Maximum is {max} - Schema: {schema}",
+	},
+	CodePattern: {
+		"Pattern Mismatch",
+		"The submitted data does not match the required pattern: '{pattern}' - Schema: {schema}",
+	},
+	CodeEnum: {
+		"Invalid Value",
+		"The submitted data is not a valid value from the list of allowed values - Schema: {schema}",
+	},
+	CodeUnique: {
+		"Duplicate Value",
+		"The submitted data contains a duplicate value - Schema: {schema}",
+	},
+	CodeStringLTE: {
+		"Invalid Length",
+		"Amount must be less than or equal to {max} - Schema: {schema}",
+	},
+	CodeStringGTE: {
+		"Invalid Length",
+		"Amount must be greater than or equal to {min} - Schema: {schema}",
+	},
+	CodeFormat: {
+		"Invalid Format",
+		"The submitted data does not match the `{format}` format - Schema: {schema}",
+	},
+	CodeAdditionalProperty: {
+		"Unexpected Property",
+		"The `{property}` property is not allowed - Schema: {schema}",
+	},
+	CodeAnyOf: {
+		"Invalid Value",
+		"The submitted data does not match any of the allowed alternatives - Schema: {schema}",
+	},
+	CodeInvalidValue: {
+		"Invalid Value",
+		"The submitted data is invalid: {message} - Schema: {schema}",
+	},
+	CodeDeprecated: {
+		"Deprecated Property",
+		"The `{property}` property is deprecated - Schema: {schema}",
+	},
+	CodeRecommended: {
+		"Missing Recommended Property",
+		"The `{property}` property is recommended - Schema: {schema}",
+	},
+	CodeSchemaLoadFailed: {
+		"Error loading schema",
+		"Error loading schema ({schema}): {error}",
+	},
+	CodeDocumentNotValidated: {
+		"Cannot Validate Document",
+		"Error validating document: {error}",
+	},
+}
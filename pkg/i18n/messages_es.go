@@ -0,0 +1,92 @@
+package i18n
+
+var messagesES = map[string]message{
+	CodeInvalidType: {
+		"Tipo no válido",
+		"Se esperaba: {expected} - Recibido: {given} - Esquema: {schema}",
+	},
+	CodeNumberGTE: {
+		"Cantidad no válida",
+		"La cantidad debe ser mayor o igual que {min} - Esquema: {schema}",
+	},
+	CodeNumberGT: {
+		"Cantidad no válida",
+		"La cantidad debe ser mayor que {min} - Esquema: {schema}",
+	},
+	CodeNumberLTE: {
+		"Cantidad no válida",
+		"La cantidad debe ser menor o igual que {max} - Esquema: {schema}",
+	},
+	CodeNumberLT: {
+		"Cantidad no válida",
+		"La cantidad debe ser menor que {max} - Esquema: {schema}",
+	},
+	CodeRequired: {
+		"Falta una propiedad obligatoria",
+		"La propiedad `{property}` es obligatoria - Esquema: {schema}",
+	},
+	CodeDependentRequired: {
+		"Falta una propiedad obligatoria",
+		"La propiedad `{property}` es obligatoria cuando `{dependency}` está presente - Esquema: {schema}",
+	},
+	CodeArrayMinItems: {
+		"Faltan elementos",
+		"La lista no tiene suficientes elementos - El mínimo es {min} - Esquema: {schema}",
+	},
+	CodeArrayMaxItems: {
+		"Demasiados elementos",
+		"La lista tiene demasiados elementos - El máximo es {max} - Esquema: {schema}",
+	},
+	CodePattern: {
+		"El patrón no coincide",
+		"Los datos enviados no coinciden con el patrón requerido: '{pattern}' - Esquema: {schema}",
+	},
+	CodeEnum: {
+		"Valor no válido",
+		"Los datos enviados no son uno de los valores permitidos - Esquema: {schema}",
+	},
+	CodeUnique: {
+		"Valor duplicado",
+		"Los datos enviados contienen un valor duplicado - Esquema: {schema}",
+	},
+	CodeStringLTE: {
+		"Longitud no válida",
+		"La longitud debe ser menor o igual que {max} - Esquema: {schema}",
+	},
+	CodeStringGTE: {
+		"Longitud no válida",
+		"La longitud debe ser mayor o igual que {min} - Esquema: {schema}",
+	},
+	CodeFormat: {
+		"Formato no válido",
+		"Los datos enviados no tienen el formato `{format}` - Esquema: {schema}",
+	},
+	CodeAdditionalProperty: {
+		"Propiedad inesperada",
+		"La propiedad `{property}` no está permitida - Esquema: {schema}",
+	},
+	CodeAnyOf: {
+		"Valor no válido",
+		"Los datos enviados no coinciden con ninguna de las alternativas permitidas - Esquema: {schema}",
+	},
+	CodeInvalidValue: {
+		"Valor no válido",
+		"Los datos enviados no son válidos: {message} - Esquema: {schema}",
+	},
+	CodeDeprecated: {
+		"Propiedad obsoleta",
+		"La propiedad `{property}` está obsoleta - Esquema: {schema}",
+	},
+	CodeRecommended: {
+		"Falta una propiedad recomendada",
+		"Se recomienda la propiedad `{property}` - Esquema: {schema}",
+	},
+	CodeSchemaLoadFailed: {
+		"Error al cargar el esquema",
+		"Error al cargar el esquema ({schema}): {error}",
+	},
+	CodeDocumentNotValidated: {
+		"No se puede validar el documento",
+		"Error al validar el documento: {error}",
+	},
+}
@@ -0,0 +1,92 @@
+package i18n
+
+var messagesFR = map[string]message{
+	CodeInvalidType: {
+		"Type non valide",
+		"Attendu : {expected} - Reçu : {given} - Schéma : {schema}",
+	},
+	CodeNumberGTE: {
+		"Montant non valide",
+		"Le montant doit être supérieur ou égal à {min} - Schéma : {schema}",
+	},
+	CodeNumberGT: {
+		"Montant non valide",
+		"Le montant doit être supérieur à {min} - Schéma : {schema}",
+	},
+	CodeNumberLTE: {
+		"Montant non valide",
+		"Le montant doit être inférieur ou égal à {max} - Schéma : {schema}",
+	},
+	CodeNumberLT: {
+		"Montant non valide",
+		"Le montant doit être inférieur à {max} - Schéma : {schema}",
+	},
+	CodeRequired: {
+		"Propriété obligatoire manquante",
+		"La propriété `{property}` est obligatoire - Schéma : {schema}",
+	},
+	CodeDependentRequired: {
+		"Propriété obligatoire manquante",
+		"La propriété `{property}` est obligatoire lorsque `{dependency}` est présente - Schéma : {schema}",
+	},
+	CodeArrayMinItems: {
+		"Éléments insuffisants",
+		"La liste ne contient pas assez d'éléments - Le minimum est {min} - Schéma : {schema}",
+	},
+	CodeArrayMaxItems: {
+		"Trop d'éléments",
+		"La liste contient trop d'éléments - Le maximum est {max} - Schéma : {schema}",
+	},
+	CodePattern: {
+		"Motif non respecté",
+		"Les données soumises ne correspondent pas au motif requis : '{pattern}' - Schéma : {schema}",
+	},
+	CodeEnum: {
+		"Valeur non valide",
+		"Les données soumises ne font pas partie des valeurs autorisées - Schéma : {schema}",
+	},
+	CodeUnique: {
+		"Valeur en double",
+		"Les données soumises contiennent une valeur en double - Schéma : {schema}",
+	},
+	CodeStringLTE: {
+		"Longueur non valide",
+		"La longueur doit être inférieure ou égale à {max} - Schéma : {schema}",
+	},
+	CodeStringGTE: {
+		"Longueur non valide",
+		"La longueur doit être supérieure ou égale à {min} - Schéma : {schema}",
+	},
+	CodeFormat: {
+		"Format non valide",
+		"Les données soumises ne respectent pas le format `{format}` - Schéma : {schema}",
+	},
+	CodeAdditionalProperty: {
+		"Propriété inattendue",
+		"La propriété `{property}` n'est pas autorisée - Schéma : {schema}",
+	},
+	CodeAnyOf: {
+		"Valeur non valide",
+		"Les données soumises ne correspondent à aucune des alternatives autorisées - Schéma : {schema}",
+	},
+	CodeInvalidValue: {
+		"Valeur non valide",
+		"Les données soumises ne sont pas valides : {message} - Schéma : {schema}",
+	},
+	CodeDeprecated: {
+		"Propriété obsolète",
+		"La propriété `{property}` est obsolète - Schéma : {schema}",
+	},
+	CodeRecommended: {
+		"Propriété recommandée manquante",
+		"La propriété `{property}` est recommandée - Schéma : {schema}",
+	},
+	CodeSchemaLoadFailed: {
+		"Erreur de chargement du schéma",
+		"Erreur lors du chargement du schéma ({schema}) : {error}",
+	},
+	CodeDocumentNotValidated: {
+		"Impossible de valider le document",
+		"Erreur lors de la validation du document : {error}",
+	},
+}
@@ -19,6 +19,11 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
 )
 
+// KvmCategory maps the IDs of the Karte von Morgen categories to the names
+// stored in the imported profiles. It isn't loaded from the kvm_categories
+// vocabulary of the library: the imported profiles keep these names until the
+// importer is migrated to the vocabulary's term IDs, which is out of the scope
+// of the vocabulary filters of the index.
 var KvmCategory = map[string]string{
 	"2cd00bebec0c48ba9db761da48678134": "#non-profit",
 	"77b3c33a92554bcf8e8c2c86cedd6f6f": "#commercial",
@@ -26,9 +31,19 @@ var KvmCategory = map[string]string{
 }
 
 type Node struct {
-	NodeID     string `json:"node_id,omitempty"`
-	ProfileURL string `json:"profile_url,omitempty"`
-	Status     string `json:"status,omitempty"`
+	NodeID          string           `json:"node_id,omitempty"`
+	ProfileURL      string           `json:"profile_url,omitempty"`
+	Status          string           `json:"status,omitempty"`
+	PrimaryURLCheck *PrimaryURLCheck `json:"primary_url_check,omitempty"`
+}
+
+// PrimaryURLCheck is the result of the latest liveness checks of a node's
+// primary URL, as reported by the index.
+type PrimaryURLCheck struct {
+	Status      string `json:"status"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	LastChecked int64  `json:"last_checked"`
+	Failures    int    `json:"failures"`
 }
 
 type NodeData struct {
@@ -247,6 +262,34 @@ func PostIndex(postNodeURL string, profileURL string) (string, error) {
 	return nodeData.Data.NodeID, nil
 }
 
+// GetIndex returns the node at getNodeURL of the index, or nil if the index
+// doesn't know it.
+func GetIndex(getNodeURL string) (*Node, error) {
+	res, err := http.Get(getNodeURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"Get failed with status code: " + strconv.Itoa(
+				res.StatusCode,
+			) + " at: " + getNodeURL,
+		)
+	}
+
+	var nodeData NodeData
+	if err := json.NewDecoder(res.Body).Decode(&nodeData); err != nil {
+		return nil, err
+	}
+
+	return &nodeData.Data, nil
+}
+
 func DeleteIndex(deleteNodeURL string, nodeID string) error {
 	req, err := http.NewRequest("DELETE", deleteNodeURL, nil)
 	if err != nil {
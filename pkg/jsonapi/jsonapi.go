@@ -21,6 +21,10 @@ type Error struct {
 	Source map[string]string `json:"source,omitempty"`
 	Title  string            `json:"title,omitempty"`
 	Detail string            `json:"detail,omitempty"`
+	// Code identifies the kind of error independently of the language of
+	// its title and detail, which can be rendered again from Meta.
+	Code string            `json:"code,omitempty"`
+	Meta map[string]string `json:"meta,omitempty"`
 }
 
 type Link struct {
@@ -39,6 +43,7 @@ type Meta struct {
 	TotalPages      int64         `json:"total_pages,omitempty"`
 	Sort            []interface{} `json:"sort,omitempty"`
 	BatchID         string        `json:"batch_id,omitempty"`
+	Warnings        []Error       `json:"warnings,omitempty"`
 }
 
 // JSON API Response Combination
@@ -272,6 +277,18 @@ func NewMeta(message string, nodeID string, profileURL string) *Meta {
 	}
 }
 
+// NewWarningsMeta returns the meta of a response about a profile that has
+// warnings, or nil if there are none.
+func NewWarningsMeta(message string, warnings []Error) *Meta {
+	if message == "" && len(warnings) == 0 {
+		return nil
+	}
+	return &Meta{
+		Message:  message,
+		Warnings: warnings,
+	}
+}
+
 func NewSearchMeta(
 	message string,
 	numberOfResults int64,
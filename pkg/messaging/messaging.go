@@ -1,6 +1,10 @@
 package messaging
 
-import "github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/quality"
+)
 
 type NodeCreatedData struct {
 	ProfileURL string `json:"profile_url"`
@@ -28,6 +32,41 @@ type NodeValidatedData struct {
 	// Expires is a string representing the expiration date of the node.
 	// It's optional and can be empty if the node doesn't have an expiration date.
 	Expires *int64 `json:"expires,omitempty"`
+
+	// FinalURL is the URL the profile was fetched from after following
+	// redirects. It is empty if no redirect was followed.
+	FinalURL string `json:"final_url,omitempty"`
+
+	// Redirects lists the redirects followed while fetching the profile.
+	Redirects []httputil.Redirect `json:"redirects,omitempty"`
+
+	// Moved is true if all redirects were permanent, meaning the node should
+	// be moved to FinalURL.
+	Moved bool `json:"moved,omitempty"`
+
+	// Warnings lists the issues found in the profile that don't make it
+	// invalid, e.g. deprecated properties.
+	Warnings []jsonapi.Error `json:"warnings,omitempty"`
+
+	// Quality holds the criteria the index scores the profile's quality on.
+	Quality *quality.Factors `json:"quality,omitempty"`
+
+	// PrimaryURL is the primary URL of the profile as published, before it
+	// was normalized, so the index can check that it stays reachable.
+	PrimaryURL string `json:"primary_url,omitempty"`
+
+	// SchemaRevisions lists the revisions of the schemas the profile was
+	// validated against.
+	SchemaRevisions []SchemaRevision `json:"schema_revisions,omitempty"`
+}
+
+// SchemaRevision identifies the revision of a schema of the library.
+type SchemaRevision struct {
+	// Name is the name of the schema, e.g. organizations_schema-v1.0.0.
+	Name string `json:"name"`
+
+	// Revision is the hash of the schema's content.
+	Revision string `json:"revision"`
 }
 
 type NodeValidationFailedData struct {
@@ -35,3 +74,37 @@ type NodeValidationFailedData struct {
 	FailureReasons *[]jsonapi.Error `json:"failure_reasons"`
 	Version        int32            `json:"version"`
 }
+
+// NodeGoneData represents a node whose profile was deleted by its owner.
+type NodeGoneData struct {
+	ProfileURL string `json:"profile_url"`
+	Version    int32  `json:"version"`
+}
+
+// NodeLinkCheckedData represents the result of checking a node's primary URL.
+type NodeLinkCheckedData struct {
+	// NodeID is the ID of the node.
+	NodeID string `json:"node_id"`
+
+	// PrimaryURL is the primary URL that was checked.
+	PrimaryURL string `json:"primary_url"`
+
+	// StatusCode is the status code of the response, or 0 if there was none.
+	StatusCode int `json:"status_code,omitempty"`
+
+	// Reachable is true if the primary URL responded with a success status
+	// code.
+	Reachable bool `json:"reachable"`
+
+	// CheckedAt is the Unix time the primary URL was checked.
+	CheckedAt int64 `json:"checked_at"`
+}
+
+// SchemasUpdatedData represents schemas updated in the library.
+type SchemasUpdatedData struct {
+	// Schemas lists the names of the schemas that were added or changed.
+	Schemas []string `json:"schemas"`
+
+	// Version identifies the update, e.g. the commit of the library.
+	Version string `json:"version"`
+}
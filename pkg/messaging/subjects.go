@@ -1,8 +1,8 @@
 package messaging
 
 // Constants for NATS subjects in the messaging system. All subjects should
-// follow the format "NODES.<event>", where <event> describes the specific
-// nature of the event.
+// follow the format "NODES.<event>" or "SCHEMAS.<event>", where <event>
+// describes the specific nature of the event.
 
 const (
 	// NodeCreated is the subject for an event where a node has been created.
@@ -15,4 +15,16 @@ const (
 	// NodeValidationFailed is the subject for an event where a node's validation
 	// has failed.
 	NodeValidationFailed = "NODES.validation_failed"
+
+	// NodeGone is the subject for an event where a node's profile URL
+	// responded with 410 Gone, meaning the profile was deleted on purpose.
+	NodeGone = "NODES.gone"
+
+	// NodeLinkChecked is the subject for an event where a node's primary URL
+	// has been checked for liveness.
+	NodeLinkChecked = "NODES.link_checked"
+
+	// SchemasUpdated is the subject for an event where the schemaparser has
+	// updated schemas in the library.
+	SchemasUpdated = "SCHEMAS.updated"
 )
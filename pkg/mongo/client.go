@@ -56,8 +56,14 @@ func (c *mongoClient) FindOneAndUpdate(
 		options.FindOneAndUpdate().SetReturnDocument(options.After),
 	)
 
-	// Automatically increment the document version.
-	update["$inc"] = bson.M{"__v": 1}
+	// Automatically increment the document version, along with the fields
+	// the update increments.
+	inc, _ := update["$inc"].(bson.M)
+	if inc == nil {
+		inc = bson.M{}
+	}
+	inc["__v"] = 1
+	update["$inc"] = inc
 
 	result := c.db.Collection(collection).
 		FindOneAndUpdate(context.Background(), filter, update, opts...)
@@ -1,3 +1,7 @@
 package natsclient
 
-const streamName = "NODES"
+// streams maps the names of the streams to the subjects they hold.
+var streams = map[string]string{
+	"NODES":   "NODES.>",
+	"SCHEMAS": "SCHEMAS.>",
+}
@@ -32,8 +32,11 @@ func Initialize(url string) error {
 		if err == nil {
 			instance.JsContext, err = instance.conn.JetStream()
 		}
-		if err == nil {
-			err = instance.ensureStreamExists()
+		for name, subject := range streams {
+			if err != nil {
+				break
+			}
+			err = instance.ensureStreamExists(name, subject)
 		}
 	})
 	return err
@@ -105,23 +108,23 @@ func connectToNATS(natsURL string) (*nats.Conn, error) {
 	return conn, nil
 }
 
-// ensureStreamExists ensures the required stream exists in NATS.
-func (c *NatsClient) ensureStreamExists() error {
-	_, err := c.JsContext.StreamInfo(streamName)
+// ensureStreamExists ensures the named stream exists in NATS.
+func (c *NatsClient) ensureStreamExists(name, subject string) error {
+	_, err := c.JsContext.StreamInfo(name)
 	if err == nil {
 		return nil
 	}
 	if err != nats.ErrStreamNotFound {
 		return fmt.Errorf("error checking stream existence: %v", err)
 	}
-	return c.createStream()
+	return c.createStream(name, subject)
 }
 
 // createStream creates a new stream in NATS JetStream.
-func (c *NatsClient) createStream() error {
+func (c *NatsClient) createStream(name, subject string) error {
 	streamConfig := &nats.StreamConfig{
-		Name:              streamName,
-		Subjects:          []string{"NODES.>"},
+		Name:              name,
+		Subjects:          []string{subject},
 		Retention:         nats.WorkQueuePolicy,
 		Discard:           nats.DiscardOld,
 		Storage:           nats.FileStorage,
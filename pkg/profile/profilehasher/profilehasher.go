@@ -3,10 +3,9 @@ package profilehasher
 import (
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/core"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonutil"
 )
 
@@ -34,6 +33,7 @@ type ProfileHash struct {
 	profile          map[string]interface{}
 	fieldsForHashing map[string]bool
 	profileStr       string
+	fetcher          *httputil.Fetcher
 }
 
 // New initializes a new ProfileHash instance.
@@ -47,6 +47,7 @@ func New(profileURL, libraryURL string) *ProfileHash {
 		profileURL:       profileURL,
 		libraryURL:       libraryURL,
 		fieldsForHashing: fieldsForHashing,
+		fetcher:          newFetcher(libraryURL),
 	}
 }
 
@@ -61,9 +62,18 @@ func NewFromString(profileStr, libraryURL string) *ProfileHash {
 		libraryURL:       libraryURL,
 		profileStr:       profileStr,
 		fieldsForHashing: fieldsForHashing,
+		fetcher:          newFetcher(libraryURL),
 	}
 }
 
+// newFetcher returns a fetcher that may reach the library, which is usually
+// an internal service, but no other private address.
+func newFetcher(libraryURL string) *httputil.Fetcher {
+	return httputil.NewFetcher(httputil.FetcherOptions{
+		AllowedHosts: httputil.Hostnames(libraryURL),
+	})
+}
+
 // Hash computes the hash for the profile.
 func (p *ProfileHash) Hash() (string, error) {
 	var err error
@@ -100,25 +110,15 @@ func (p *ProfileHash) Hash() (string, error) {
 }
 
 func (p *ProfileHash) fetchData(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+	data, _, err := p.fetcher.FetchJSON(url)
 	if err != nil {
 		return nil, fmt.Errorf(
-			"error while sending request to %s: %v",
+			"error while fetching %s: %w",
 			url,
 			err,
 		)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(
-			"request to %s failed with status code %d",
-			url,
-			resp.StatusCode,
-		)
-	}
-
-	return io.ReadAll(resp.Body)
+	return data, nil
 }
 
 func (p *ProfileHash) populateFieldsForHashing() error {
@@ -15,6 +15,7 @@ import (
 func createProfileServer(t *testing.T, response string) *httptest.Server {
 	return httptest.NewServer(
 		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
 			_, err := w.Write([]byte(response))
 			require.NoError(t, err, "Failed to write response")
 		}),
@@ -35,6 +36,7 @@ func createLibraryServer(
 			}
 			schemaName := path.Base(r.URL.Path)
 			if response, ok := responses[schemaName]; ok {
+				w.Header().Set("Content-Type", "application/json")
 				_, err := w.Write([]byte(response))
 				require.NoError(t, err, "Failed to write response")
 			} else {
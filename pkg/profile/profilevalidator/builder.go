@@ -29,6 +29,13 @@ func (b *Builder) WithURLSchemas(baseURL string, schemaReferences []string) *Bui
 	return b
 }
 
+// WithSchemaLoader replaces the loader used to fetch the schemas, e.g. with a
+// SchemaCache. It must be called after WithURLSchemas or WithJSONSchemas.
+func (b *Builder) WithSchemaLoader(loader Loader) *Builder {
+	b.profilevalidator.SchemaLoader = loader
+	return b
+}
+
 // WithJSONSchemas configures the ProfileValidator to use preloaded JSON schemas.
 // The schemaNames correspond to the names of the loaded JSON schemas.
 func (b *Builder) WithJSONSchemas(schemaNames []string, loadedSchemas []string) *Builder {
@@ -0,0 +1,133 @@
+package profilevalidator
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Custom formats that schemas can use for Murmurations concepts.
+const (
+	// CountryCodeFormat is an ISO 3166-1 alpha-2 country code, e.g. "DE".
+	CountryCodeFormat = "country-code"
+	// GeolocationFormat is a "latitude,longitude" string or an object with
+	// "lat" and "lon" numbers. Draft-07 schemas only check strings.
+	GeolocationFormat = "geolocation"
+	// TagFormat is a single tag: a non-empty line of at most MaxTagLength
+	// characters without surrounding whitespace.
+	TagFormat = "tag"
+)
+
+// MaxTagLength is the maximum number of characters of a tag.
+const MaxTagLength = 100
+
+// formats maps the custom formats to the functions checking them. Like the
+// standard formats, string formats ignore values of other types.
+var formats = map[string]func(interface{}) bool{
+	CountryCodeFormat: isCountryCode,
+	GeolocationFormat: isGeolocation,
+	TagFormat:         isTag,
+}
+
+// countryCodes lists the ISO 3166-1 alpha-2 codes known to the library.
+var countryCodes = map[string]bool{}
+
+func init() {
+	for _, code := range []string{
+		"AD", "AE", "AF", "AG", "AI", "AL", "AM", "AO", "AQ", "AR", "AS", "AT",
+		"AU", "AW", "AX", "AZ", "BA", "BB", "BD", "BE", "BF", "BG", "BH", "BI",
+		"BJ", "BL", "BM", "BN", "BO", "BQ", "BR", "BS", "BT", "BV", "BW", "BY",
+		"BZ", "CA", "CC", "CD", "CF", "CG", "CH", "CI", "CK", "CL", "CM", "CN",
+		"CO", "CR", "CU", "CV", "CW", "CX", "CY", "CZ", "DE", "DJ", "DK", "DM",
+		"DO", "DZ", "EC", "EE", "EG", "EH", "ER", "ES", "ET", "FI", "FJ", "FK",
+		"FM", "FO", "FR", "GA", "GB", "GD", "GE", "GF", "GG", "GH", "GI", "GL",
+		"GM", "GN", "GP", "GQ", "GR", "GS", "GT", "GU", "GW", "GY", "HK", "HM",
+		"HN", "HR", "HT", "HU", "ID", "IE", "IL", "IM", "IN", "IO", "IQ", "IR",
+		"IS", "IT", "JE", "JM", "JO", "JP", "KE", "KG", "KH", "KI", "KM", "KN",
+		"KP", "KR", "KW", "KY", "KZ", "LA", "LB", "LC", "LI", "LK", "LR", "LS",
+		"LT", "LU", "LV", "LY", "MA", "MC", "MD", "ME", "MF", "MG", "MH", "MK",
+		"ML", "MM", "MN", "MO", "MP", "MQ", "MR", "MS", "MT", "MU", "MV", "MW",
+		"MX", "MY", "MZ", "NA", "NC", "NE", "NF", "NG", "NI", "NL", "NO", "NP",
+		"NR", "NU", "NZ", "OM", "PA", "PE", "PF", "PG", "PH", "PK", "PL", "PM",
+		"PN", "PR", "PS", "PT", "PW", "PY", "QA", "RE", "RO", "RS", "RU", "RW",
+		"SA", "SB", "SC", "SD", "SE", "SG", "SH", "SI", "SJ", "SK", "SL", "SM",
+		"SN", "SO", "SR", "SS", "ST", "SV", "SX", "SY", "SZ", "TC", "TD", "TF",
+		"TG", "TH", "TJ", "TK", "TL", "TM", "TN", "TO", "TR", "TT", "TV", "TW",
+		"TZ", "UA", "UG", "UM", "US", "UY", "UZ", "VA", "VC", "VE", "VG", "VI",
+		"VN", "VU", "WF", "WS", "XK", "YE", "YT", "ZA", "ZM", "ZW",
+	} {
+		countryCodes[code] = true
+	}
+}
+
+func isCountryCode(input interface{}) bool {
+	s, ok := input.(string)
+	if !ok {
+		return true
+	}
+	return countryCodes[s]
+}
+
+func isGeolocation(input interface{}) bool {
+	switch v := input.(type) {
+	case string:
+		parts := strings.Split(v, ",")
+		if len(parts) != 2 {
+			return false
+		}
+		lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		if err != nil {
+			return false
+		}
+		lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			return false
+		}
+		return isLatLon(lat, lon)
+	case map[string]interface{}:
+		lat, ok := toFloat(v["lat"])
+		if !ok {
+			return false
+		}
+		lon, ok := toFloat(v["lon"])
+		if !ok {
+			return false
+		}
+		return isLatLon(lat, lon)
+	default:
+		return true
+	}
+}
+
+func isLatLon(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// toFloat converts a decoded JSON number to a float64.
+func toFloat(input interface{}) (float64, bool) {
+	switch v := input.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func isTag(input interface{}) bool {
+	s, ok := input.(string)
+	if !ok {
+		return true
+	}
+	if s == "" || s != strings.TrimSpace(s) {
+		return false
+	}
+	if utf8.RuneCountInString(s) > MaxTagLength {
+		return false
+	}
+	return strings.IndexFunc(s, unicode.IsControl) == -1
+}
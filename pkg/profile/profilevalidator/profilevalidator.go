@@ -2,17 +2,22 @@ package profilevalidator
 
 import (
 	"fmt"
+	"io"
 	"math/big"
 	"net/http"
+	"path"
 	"strings"
 
 	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/i18n"
 )
 
 // Loader is the interface that wraps the Load method.
 type Loader interface {
-	// Load fetches the JSON schema from a source and returns it.
-	Load(string) (*gojsonschema.Schema, error)
+	// Load fetches the JSON schema from a source and returns it compiled.
+	Load(string) (Schema, error)
 }
 
 // URLSchemaLoader is a schema loader that loads schema from a URL.
@@ -21,22 +26,61 @@ type URLSchemaLoader struct {
 	BaseURL string
 }
 
-// Load implements the Loader interface.
+// Load implements the Loader interface. The revision of the schema is read
+// from the ETag header, and its name from the Content-Location header when
+// the library served another version than the linked one.
 func (ul *URLSchemaLoader) Load(
 	linkedSchema string,
-) (*gojsonschema.Schema, error) {
+) (Schema, error) {
 	schemaURL := getSchemaURL(ul.BaseURL, linkedSchema)
-	return gojsonschema.NewSchema(gojsonschema.NewReferenceLoader(schemaURL))
+	resp, err := httputil.Get(schemaURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"could not read schema from HTTP, response status is %s",
+			resp.Status,
+		)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := gojsonschema.NewBytesLoader(body).LoadJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	revision := SchemaRevision{
+		Name:     linkedSchema,
+		Revision: strings.Trim(resp.Header.Get("ETag"), `"`),
+	}
+	if location := resp.Header.Get("Content-Location"); location != "" {
+		revision.Name = path.Base(location)
+	}
+	return compileSchema(schemaURL, doc, revision, nil)
 }
 
-// StrSchemaLoader is a schema loader that loads schema from a string.
+// StrSchemaLoader is a schema loader that loads schema from a string. As
+// such schemas are submitted by users, the documents they reference are
+// fetched with an httputil.Fetcher, which refuses private addresses.
 type StrSchemaLoader struct{}
 
+// strSchemaLocation is the location of schemas loaded from strings.
+const strSchemaLocation = "urn:murmurations:schema"
+
 // Load implements the Loader interface.
 func (sl *StrSchemaLoader) Load(
 	source string,
-) (*gojsonschema.Schema, error) {
-	return gojsonschema.NewSchema(gojsonschema.NewStringLoader(source))
+) (Schema, error) {
+	doc, err := gojsonschema.NewStringLoader(source).LoadJSON()
+	if err != nil {
+		return nil, err
+	}
+	refs := &refLoader{fetcher: httputil.NewFetcher(httputil.FetcherOptions{})}
+	return compileSchema(strSchemaLocation, doc, SchemaRevision{}, refs)
 }
 
 // ProfileLoader is the interface that wraps the Load method.
@@ -104,40 +148,36 @@ func (v *ProfileValidator) Validate() *ValidationResult {
 		// Load the schema using the SchemaLoader.
 		loadedSchema, err := v.SchemaLoader.Load(schema)
 		if err != nil {
-			finalResult.AppendError(
-				"Error loading schema",
-				fmt.Sprintf("Error loading schema (%s): %v", v.SchemaNames[i], err),
-				[]string{"pointer", "/linked_schemas"},
-				http.StatusNotFound,
-			)
+			finalResult.AppendIssues([]Issue{{
+				Code: i18n.CodeSchemaLoadFailed,
+				Params: map[string]string{
+					"schema": v.SchemaNames[i],
+					"error":  err.Error(),
+				},
+				Source: []string{"pointer", "/linked_schemas"},
+			}}, http.StatusNotFound)
 			continue
 		}
 
 		// Validate the profile JSON against the loaded schema.
-		validationResult, err := loadedSchema.Validate(v.ProfileLoader.Load())
+		issues, err := loadedSchema.Validate(v.SchemaNames[i], v.ProfileLoader)
 		if err != nil {
-			finalResult.AppendError(
-				"Cannot Validate Document",
-				fmt.Sprintf("Error validating document: %s", err.Error()),
-				nil,
-				http.StatusBadRequest,
-			)
+			finalResult.AppendIssues([]Issue{{
+				Code:   i18n.CodeDocumentNotValidated,
+				Params: map[string]string{"error": err.Error()},
+			}}, http.StatusBadRequest)
 			continue
 		}
 
-		// If validation fails, collect and append the errors.
-		if !validationResult.Valid() {
-			titles, details, sources := parseValidateError(v.SchemaNames[i], validationResult.Errors())
-
-			// Assign the same status code for each validation error.
-			statusCodes := make([]int, len(titles))
-			for i := range statusCodes {
-				statusCodes[i] = http.StatusBadRequest
-			}
-
-			// Append all validation errors to the final result.
-			finalResult.AppendErrors(titles, details, sources, statusCodes)
+		// Warnings are reported whether or not the profile is valid.
+		finalResult.AppendWarnings(loadedSchema.Warnings(v.SchemaNames[i], v.ProfileJSON))
+		finalResult.RecommendedProperties += loadedSchema.Recommended(v.ProfileJSON)
+		if revision := loadedSchema.Revision(); revision.Revision != "" {
+			finalResult.SchemaRevisions = append(finalResult.SchemaRevisions, revision)
 		}
+
+		// If validation fails, append the errors.
+		finalResult.AppendIssues(issues, http.StatusBadRequest)
 	}
 
 	return finalResult
@@ -151,17 +191,14 @@ func getSchemaURL(libraryURL string, linkedSchema string) string {
 func parseValidateError(
 	schemaName string,
 	resultErrors []gojsonschema.ResultError,
-) ([]string, []string, [][]string) {
-	failedTitles := make([]string, 0, len(resultErrors))
-	failedDetails := make([]string, 0, len(resultErrors))
-	failedSources := make([][]string, 0, len(resultErrors))
+) []Issue {
+	issues := make([]Issue, 0, len(resultErrors))
 
 	for _, desc := range resultErrors {
-		// title
-		failedType := desc.Type()
+		code := desc.Type()
 
-		// details
-		var expected, given, minValue, maxValue, property, pattern, failedDetail, failedField string
+		// parameters
+		var expected, given, minValue, maxValue, property, pattern, format, failedField string
 		for index, value := range desc.Details() {
 			switch index {
 			case "expected":
@@ -186,58 +223,43 @@ func parseValidateError(
 				property = value.(string)
 			case "pattern":
 				pattern = fmt.Sprint(value)
+			case "format":
+				format = fmt.Sprint(value)
 			}
 		}
 
-		switch failedType {
-		case "invalid_type":
-			failedType = "Invalid Type"
-			failedDetail = "Expected: " + expected + " - Given: " + given + " - Schema: " + schemaName
-		case "number_gte":
-			failedType = "Invalid Amount"
-			failedDetail = "Amount must be greater than or equal to " + minValue + " - Schema: " + schemaName
-		case "number_lte":
-			failedType = "Invalid Amount"
-			failedDetail = "Amount must be less than or equal to " + maxValue + " - Schema: " + schemaName
-		case "required":
-			failedType = "Missing Required Property"
+		params := map[string]string{"schema": schemaName}
+		switch code {
+		case i18n.CodeInvalidType:
+			params["expected"] = expected
+			params["given"] = given
+		case i18n.CodeNumberGTE, i18n.CodeNumberGT, i18n.CodeArrayMinItems, i18n.CodeStringGTE:
+			params["min"] = minValue
+		case i18n.CodeNumberLTE, i18n.CodeNumberLT, i18n.CodeArrayMaxItems, i18n.CodeStringLTE:
+			params["max"] = maxValue
+		case i18n.CodeRequired, i18n.CodeAdditionalProperty:
 			if desc.Field() == "(root)" {
-				failedDetail = "The `" + property + "` property is required - Schema: " + schemaName
+				params["property"] = property
 			} else {
-				failedDetail = "The `" + desc.Field() + "/" + property + "` property is required - Schema: " + schemaName
+				params["property"] = desc.Field() + "/" + property
 			}
-		case "array_min_items":
-			failedType = "Not Enough Items"
-			failedDetail = "There are not enough items in the array - Minimum is " + minValue + " - Schema: " + schemaName
-		case "array_max_items":
-			failedType = "Too Many Items"
-			failedDetail = "There are too many items in the array - Maximum is " + maxValue + " - Schema: " + schemaName
-		case "pattern":
-			failedType = "Pattern Mismatch"
-			failedDetail = "The submitted data does not match the required pattern: '" + pattern + "' - Schema: " + schemaName
-		case "enum":
-			failedType = "Invalid Value"
-			failedDetail = "The submitted data is not a valid value from the list of allowed values - Schema: " + schemaName
-		case "unique":
-			failedType = "Duplicate Value"
-			failedDetail = "The submitted data contains a duplicate value - Schema: " + schemaName
-		case "string_lte":
-			failedType = "Invalid Length"
-			failedDetail = "Amount must be less than or equal to " + maxValue + " - Schema: " + schemaName
-		case "string_gte":
-			failedType = "Invalid Length"
-			failedDetail = "Amount must be greater than or equal to " + minValue + " - Schema: " + schemaName
+		case i18n.CodePattern:
+			params["pattern"] = pattern
+		case i18n.CodeFormat:
+			params["format"] = format
+		case i18n.CodeEnum, i18n.CodeUnique:
+		case "number_any_of", "number_one_of":
+			code = i18n.CodeAnyOf
 		// condition_else and condition_then are not errors, they are conditions - no need to report them
 		case "condition_else":
 			continue
 		case "condition_then":
 			continue
+		default:
+			// Other errors are reported with their type as the title.
+			params = nil
 		}
 
-		// append title and detail
-		failedTitles = append(failedTitles, failedType)
-		failedDetails = append(failedDetails, failedDetail)
-
 		// sources
 		if desc.Field() == "(root)" && property != "" {
 			failedField = "/" + property
@@ -246,8 +268,13 @@ func parseValidateError(
 		} else {
 			failedField = "/" + strings.Replace(desc.Field(), ".", "/", -1)
 		}
-		failedSources = append(failedSources, []string{"pointer", failedField})
+
+		issues = append(issues, Issue{
+			Code:   code,
+			Params: params,
+			Source: []string{"pointer", failedField},
+		})
 	}
 
-	return failedTitles, failedDetails, failedSources
+	return issues
 }
@@ -0,0 +1,80 @@
+package profilevalidator_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilevalidator"
+)
+
+func TestURLSchemaLoaderRevisions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/schemas/test_schema-v1":
+				w.Header().Set("Content-Location", "/v2/schemas/test_schema-v1.2.0")
+				w.Header().Set("ETag", `"abc123"`)
+			case "/v2/schemas/other_schema-v1.0.0":
+			default:
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(`{"type": "object", "required": ["name"]}`))
+		},
+	))
+	defer ts.Close()
+
+	validator, err := profilevalidator.NewBuilder().
+		WithStrProfile(`{"name": "A"}`).
+		WithURLSchemas(ts.URL, []string{"test_schema-v1", "other_schema-v1.0.0"}).
+		Build()
+	require.NoError(t, err)
+	result := validator.Validate()
+	require.True(t, result.Valid)
+	// Schemas served without an ETag have no revision to record.
+	require.Equal(t, []profilevalidator.SchemaRevision{
+		{Name: "test_schema-v1.2.0", Revision: "abc123"},
+	}, result.SchemaRevisions)
+
+	validator, err = profilevalidator.NewBuilder().
+		WithStrProfile(`{"name": "A"}`).
+		WithURLSchemas(ts.URL, []string{"unknown_schema-v1.0.0"}).
+		Build()
+	require.NoError(t, err)
+	result = validator.Validate()
+	require.False(t, result.Valid)
+	require.Contains(t, result.Details[0], "404")
+}
+
+func TestURLSchemaLoaderRelativeRefs(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/schemas/test_schema-v1.0.0":
+				_, _ = w.Write([]byte(`{
+					"type": "object",
+					"properties": {"name": {"$ref": "../fields/name"}},
+					"required": ["name"]
+				}`))
+			case "/v2/fields/name":
+				_, _ = w.Write([]byte(`{"type": "string", "minLength": 2}`))
+			default:
+				http.NotFound(w, r)
+			}
+		},
+	))
+	defer ts.Close()
+
+	validator, err := profilevalidator.NewBuilder().
+		WithStrProfile(`{"name": "A"}`).
+		WithURLSchemas(ts.URL, []string{"test_schema-v1.0.0"}).
+		Build()
+	require.NoError(t, err)
+	result := validator.Validate()
+	require.False(t, result.Valid, result.Details)
+	require.Equal(t, []string{"Invalid Length"}, result.ErrorMessages)
+	require.Equal(t, [][]string{{"pointer", "/name"}}, result.Sources)
+}
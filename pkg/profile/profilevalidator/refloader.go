@@ -0,0 +1,62 @@
+package profilevalidator
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/xeipuuv/gojsonreference"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
+)
+
+// refLoader loads the documents referenced by a schema with a Fetcher, so
+// that the $refs of schemas submitted by users can't reach private addresses
+// or local files. It is both a jsonschema.URLLoader and a
+// gojsonschema.JSONLoaderFactory.
+type refLoader struct {
+	fetcher *httputil.Fetcher
+}
+
+// Load implements the jsonschema.URLLoader interface.
+func (l refLoader) Load(url string) (any, error) {
+	data, _, err := l.fetcher.FetchJSON(url)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(data))
+}
+
+// New implements the gojsonschema.JSONLoaderFactory interface.
+func (l refLoader) New(source string) gojsonschema.JSONLoader {
+	return &refJSONLoader{refLoader: l, source: source}
+}
+
+// refJSONLoader is the gojsonschema.JSONLoader of a referenced document.
+type refJSONLoader struct {
+	refLoader
+	source string
+}
+
+// JsonSource implements the gojsonschema.JSONLoader interface.
+func (l *refJSONLoader) JsonSource() interface{} {
+	return l.source
+}
+
+// LoadJSON implements the gojsonschema.JSONLoader interface. The fragment of
+// the reference is resolved by gojsonschema.
+func (l *refJSONLoader) LoadJSON() (interface{}, error) {
+	url, _, _ := strings.Cut(l.source, "#")
+	return l.Load(url)
+}
+
+// JsonReference implements the gojsonschema.JSONLoader interface.
+func (l *refJSONLoader) JsonReference() (gojsonreference.JsonReference, error) {
+	return gojsonreference.NewJsonReference(l.source)
+}
+
+// LoaderFactory implements the gojsonschema.JSONLoader interface.
+func (l *refJSONLoader) LoaderFactory() gojsonschema.JSONLoaderFactory {
+	return l.refLoader
+}
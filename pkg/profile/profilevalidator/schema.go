@@ -0,0 +1,307 @@
+package profilevalidator
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"github.com/xeipuuv/gojsonschema"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/i18n"
+)
+
+// Schema is a compiled schema that profiles are validated against.
+type Schema interface {
+	// Validate validates the profile and returns the validation errors. The
+	// schema name is one of their parameters. It returns an error if the
+	// profile couldn't be validated.
+	Validate(schemaName string, profile ProfileLoader) ([]Issue, error)
+	// Warnings returns the warnings about the profile, which don't make it
+	// invalid.
+	Warnings(schemaName string, profile map[string]interface{}) []Issue
+	// Recommended returns the number of properties of the profile the schema
+	// recommends, whether they are present or not.
+	Recommended(profile map[string]interface{}) int
+	// Revision returns the revision of the schema the library served, which
+	// is empty for schemas that weren't loaded from the library.
+	Revision() SchemaRevision
+}
+
+// SchemaRevision identifies the revision of a schema a profile was validated
+// against.
+type SchemaRevision struct {
+	// Name of the schema, which is the version the library served for a
+	// major version range.
+	Name string
+	// Revision is the hash of the schema's content given by the library.
+	Revision string
+}
+
+// draft07Schema is a schema of draft-07 or earlier, validated with
+// gojsonschema.
+type draft07Schema struct {
+	schemaDoc
+	schema *gojsonschema.Schema
+}
+
+// Validate implements the Schema interface.
+func (s *draft07Schema) Validate(
+	schemaName string,
+	profile ProfileLoader,
+) ([]Issue, error) {
+	result, err := s.schema.Validate(profile.Load())
+	if err != nil {
+		return nil, err
+	}
+	if result.Valid() {
+		return nil, nil
+	}
+	return parseValidateError(schemaName, result.Errors()), nil
+}
+
+// draft2020Schema is a schema of draft 2019-09 or 2020-12, validated with
+// santhosh-tekuri/jsonschema.
+type draft2020Schema struct {
+	schemaDoc
+	schema *jsonschema.Schema
+}
+
+// Validate implements the Schema interface.
+func (s *draft2020Schema) Validate(
+	schemaName string,
+	profile ProfileLoader,
+) ([]Issue, error) {
+	data, err := profile.Load().LoadJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.schema.Validate(data)
+	if err == nil {
+		return nil, nil
+	}
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return nil, err
+	}
+	return parseDraft2020Error(schemaName, validationErr), nil
+}
+
+// draft2020Dialects lists the $schema values of the drafts that gojsonschema
+// doesn't support.
+var draft2020Dialects = []string{
+	"https://json-schema.org/draft/2019-09/schema",
+	"https://json-schema.org/draft/2020-12/schema",
+}
+
+// compileSchema compiles the decoded schema located at the given URL with
+// the engine supporting the dialect it declares in $schema. Schemas without
+// $schema are treated as draft-07. The documents the schema references are
+// loaded with refs, or with the default loaders of the engines if it is nil.
+func compileSchema(
+	location string,
+	doc interface{},
+	revision SchemaRevision,
+	refs *refLoader,
+) (Schema, error) {
+	if !isDraft2020(doc) {
+		// The schema is added under its location so that relative $refs
+		// resolve against it.
+		loader := gojsonschema.NewSchemaLoader()
+		if err := loader.AddSchema(location, gojsonschema.NewGoLoader(doc)); err != nil {
+			return nil, err
+		}
+		root := gojsonschema.NewReferenceLoader(location)
+		if refs != nil {
+			root = refs.New(location)
+		}
+		schema, err := loader.Compile(root)
+		if err != nil {
+			return nil, err
+		}
+		return &draft07Schema{schemaDoc: schemaDoc{doc: doc, revision: revision}, schema: schema}, nil
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+	if refs != nil {
+		compiler.UseLoader(refs)
+	}
+	for name, check := range formats {
+		compiler.RegisterFormat(&jsonschema.Format{
+			Name:     name,
+			Validate: formatValidator(name, check),
+		})
+	}
+	if err := compiler.AddResource(location, doc); err != nil {
+		return nil, err
+	}
+	schema, err := compiler.Compile(location)
+	if err != nil {
+		return nil, err
+	}
+	return &draft2020Schema{schemaDoc: schemaDoc{doc: doc, revision: revision}, schema: schema}, nil
+}
+
+// isDraft2020 reports whether the schema declares draft 2019-09 or 2020-12.
+func isDraft2020(doc interface{}) bool {
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	dialect, _ := obj["$schema"].(string)
+	dialect = strings.TrimSuffix(dialect, "#")
+	for _, d := range draft2020Dialects {
+		if dialect == d {
+			return true
+		}
+	}
+	return false
+}
+
+// formatValidator adapts a format check to santhosh-tekuri/jsonschema.
+func formatValidator(name string, check func(interface{}) bool) func(any) error {
+	return func(v any) error {
+		if !check(v) {
+			return fmt.Errorf("not a valid %s", name)
+		}
+		return nil
+	}
+}
+
+// gojsonschemaFormatChecker adapts a format check to gojsonschema.
+type gojsonschemaFormatChecker func(interface{}) bool
+
+// IsFormat implements gojsonschema.FormatChecker.
+func (c gojsonschemaFormatChecker) IsFormat(input interface{}) bool {
+	return c(input)
+}
+
+func init() {
+	for name, check := range formats {
+		gojsonschema.FormatCheckers.Add(name, gojsonschemaFormatChecker(check))
+	}
+}
+
+// printer formats the messages of errors without a Murmurations title.
+var printer = message.NewPrinter(language.English)
+
+// parseDraft2020Error converts the leaves of a validation error tree into the
+// issues used for draft-07 errors.
+func parseDraft2020Error(
+	schemaName string,
+	validationErr *jsonschema.ValidationError,
+) []Issue {
+	var issues []Issue
+
+	add := func(code string, params map[string]string, field string) {
+		if params == nil {
+			params = map[string]string{}
+		}
+		params["schema"] = schemaName
+		issues = append(issues, Issue{
+			Code:   code,
+			Params: params,
+			Source: []string{"pointer", field},
+		})
+	}
+
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		field := "/" + strings.Join(e.InstanceLocation, "/")
+
+		switch k := e.ErrorKind.(type) {
+		case *kind.AnyOf, *kind.OneOf:
+			// The causes are the errors of every alternative; report the
+			// value itself instead.
+			add(i18n.CodeAnyOf, nil, field)
+			return
+		case *kind.Type:
+			add(i18n.CodeInvalidType, map[string]string{
+				"expected": strings.Join(k.Want, ", "),
+				"given":    k.Got,
+			}, field)
+		case *kind.Required:
+			for _, property := range k.Missing {
+				add(i18n.CodeRequired, map[string]string{
+					"property": strings.TrimPrefix(joinField(field, property), "/"),
+				}, joinField(field, property))
+			}
+		case *kind.DependentRequired:
+			for _, property := range k.Missing {
+				add(i18n.CodeDependentRequired, map[string]string{
+					"property":   strings.TrimPrefix(joinField(field, property), "/"),
+					"dependency": k.Prop,
+				}, joinField(field, property))
+			}
+		case *kind.Minimum:
+			add(i18n.CodeNumberGTE, map[string]string{"min": ratString(k.Want)}, field)
+		case *kind.ExclusiveMinimum:
+			add(i18n.CodeNumberGT, map[string]string{"min": ratString(k.Want)}, field)
+		case *kind.Maximum:
+			add(i18n.CodeNumberLTE, map[string]string{"max": ratString(k.Want)}, field)
+		case *kind.ExclusiveMaximum:
+			add(i18n.CodeNumberLT, map[string]string{"max": ratString(k.Want)}, field)
+		case *kind.MinItems:
+			add(i18n.CodeArrayMinItems, map[string]string{"min": strconv.Itoa(k.Want)}, field)
+		case *kind.MaxItems:
+			add(i18n.CodeArrayMaxItems, map[string]string{"max": strconv.Itoa(k.Want)}, field)
+		case *kind.Pattern:
+			add(i18n.CodePattern, map[string]string{"pattern": k.Want}, field)
+		case *kind.Enum, *kind.Const:
+			add(i18n.CodeEnum, nil, field)
+		case *kind.UniqueItems:
+			add(i18n.CodeUnique, nil, field)
+		case *kind.MinLength:
+			add(i18n.CodeStringGTE, map[string]string{"min": strconv.Itoa(k.Want)}, field)
+		case *kind.MaxLength:
+			add(i18n.CodeStringLTE, map[string]string{"max": strconv.Itoa(k.Want)}, field)
+		case *kind.Format:
+			add(i18n.CodeFormat, map[string]string{"format": k.Want}, field)
+		case *kind.AdditionalProperties:
+			for _, property := range k.Properties {
+				add(i18n.CodeAdditionalProperty, map[string]string{
+					"property": strings.TrimPrefix(joinField(field, property), "/"),
+				}, joinField(field, property))
+			}
+		case *kind.FalseSchema:
+			// Raised for each property or item rejected by a false schema,
+			// e.g. by unevaluatedProperties.
+			add(i18n.CodeAdditionalProperty, map[string]string{
+				"property": strings.TrimPrefix(field, "/"),
+			}, field)
+		default:
+			if len(e.Causes) == 0 {
+				add(i18n.CodeInvalidValue, map[string]string{
+					"message": e.ErrorKind.LocalizedString(printer),
+				}, field)
+			}
+		}
+
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(validationErr)
+
+	return issues
+}
+
+// joinField appends a property to a JSON pointer.
+func joinField(field, property string) string {
+	return strings.TrimSuffix(field, "/") + "/" + property
+}
+
+// ratString formats a number of a schema keyword.
+func ratString(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	f, _ := r.Float64()
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,333 @@
+package profilevalidator_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilevalidator"
+)
+
+func validate(t *testing.T, schema, profile string) *profilevalidator.ValidationResult {
+	validator, err := profilevalidator.NewBuilder().
+		WithStrProfile(profile).
+		WithJSONSchemas([]string{"test"}, []string{schema}).
+		Build()
+	require.NoError(t, err)
+	return validator.Validate()
+}
+
+func TestValidateDraft2020(t *testing.T) {
+	schema := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"$defs": {
+			"name": {"type": "string", "minLength": 2}
+		},
+		"properties": {
+			"name": {"$ref": "#/$defs/name"},
+			"email": {"type": "string"},
+			"phone": {"type": "string"}
+		},
+		"required": ["name"],
+		"dependentRequired": {"phone": ["email"]},
+		"unevaluatedProperties": false
+	}`
+
+	tests := []struct {
+		name       string
+		profile    string
+		expTitles  []string
+		expSources [][]string
+	}{
+		{
+			name:    "valid profile",
+			profile: `{"name": "Murmurations", "phone": "1", "email": "a@b.c"}`,
+		},
+		{
+			name:       "missing required property",
+			profile:    `{}`,
+			expTitles:  []string{"Missing Required Property"},
+			expSources: [][]string{{"pointer", "/name"}},
+		},
+		{
+			name:       "$defs constraint",
+			profile:    `{"name": "M"}`,
+			expTitles:  []string{"Invalid Length"},
+			expSources: [][]string{{"pointer", "/name"}},
+		},
+		{
+			name:       "dependentRequired",
+			profile:    `{"name": "Murmurations", "phone": "1"}`,
+			expTitles:  []string{"Missing Required Property"},
+			expSources: [][]string{{"pointer", "/email"}},
+		},
+		{
+			name:       "unevaluatedProperties",
+			profile:    `{"name": "Murmurations", "extra": true}`,
+			expTitles:  []string{"Unexpected Property"},
+			expSources: [][]string{{"pointer", "/extra"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validate(t, schema, tt.profile)
+			if len(tt.expTitles) == 0 {
+				require.True(t, result.Valid, result.Details)
+				return
+			}
+			require.False(t, result.Valid)
+			require.Equal(t, tt.expTitles, result.ErrorMessages)
+			require.Equal(t, tt.expSources, result.Sources)
+			for _, detail := range result.Details {
+				require.Contains(t, detail, "- Schema: test")
+			}
+		})
+	}
+}
+
+func TestValidateCustomFormats(t *testing.T) {
+	properties := `"properties": {
+		"country": {"type": "string", "format": "country-code"},
+		"geolocation": {"format": "geolocation"},
+		"tags": {"type": "array", "items": {"type": "string", "format": "tag"}}
+	}`
+	schemas := map[string]string{
+		"draft-07": `{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"type": "object",
+			` + properties + `
+		}`,
+		"2020-12": `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			` + properties + `
+		}`,
+	}
+
+	tests := []struct {
+		name      string
+		profile   string
+		expSource string
+		// draft2020Only is set for checks of non-string values, which
+		// gojsonschema doesn't pass to format checkers.
+		draft2020Only bool
+	}{
+		{
+			name:    "valid values",
+			profile: `{"country": "DE", "geolocation": "52.52,13.40", "tags": ["co-op"]}`,
+		},
+		{
+			name:    "geolocation object",
+			profile: `{"geolocation": {"lat": -33.9, "lon": 18.4}}`,
+		},
+		{
+			name:      "unknown country code",
+			profile:   `{"country": "XX"}`,
+			expSource: "/country",
+		},
+		{
+			name:      "lowercase country code",
+			profile:   `{"country": "de"}`,
+			expSource: "/country",
+		},
+		{
+			name:      "latitude out of range",
+			profile:   `{"geolocation": "91,13.40"}`,
+			expSource: "/geolocation",
+		},
+		{
+			name:          "geolocation object without lon",
+			profile:       `{"geolocation": {"lat": 52.52}}`,
+			expSource:     "/geolocation",
+			draft2020Only: true,
+		},
+		{
+			name:      "tag with surrounding whitespace",
+			profile:   `{"tags": [" co-op"]}`,
+			expSource: "/tags/0",
+		},
+		{
+			name:      "empty tag",
+			profile:   `{"tags": ["co-op", ""]}`,
+			expSource: "/tags/1",
+		},
+	}
+
+	for draft, schema := range schemas {
+		for _, tt := range tests {
+			if tt.draft2020Only && draft == "draft-07" {
+				continue
+			}
+			t.Run(draft+"/"+tt.name, func(t *testing.T) {
+				result := validate(t, schema, tt.profile)
+				if tt.expSource == "" {
+					require.True(t, result.Valid, result.Details)
+					return
+				}
+				require.False(t, result.Valid)
+				require.Equal(t, []string{"Invalid Format"}, result.ErrorMessages)
+				require.Equal(t, [][]string{{"pointer", tt.expSource}}, result.Sources)
+			})
+		}
+	}
+}
+
+func TestValidateWarnings(t *testing.T) {
+	definitions := `
+		"properties": {
+			"name": {"type": "string"},
+			"fax": {"type": "string", "deprecated": true},
+			"contact": {"$ref": "#/$defs/contact"},
+			"offers": {"type": "array", "items": {"$ref": "#/$defs/offer"}}
+		},
+		"recommended": ["name", "description"],
+		"$defs": {
+			"contact": {
+				"type": "object",
+				"properties": {"telex": {"type": "string", "deprecated": true}}
+			},
+			"offer": {
+				"type": "object",
+				"required": ["title"],
+				"recommended": ["price"]
+			}
+		}`
+	schemas := map[string]string{
+		"draft-07": `{"type": "object", ` + definitions + `}`,
+		"2020-12": `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			` + definitions + `
+		}`,
+	}
+
+	tests := []struct {
+		name       string
+		profile    string
+		valid      bool
+		expTitles  []string
+		expSources [][]string
+		// Recommended properties, and how many of them are missing.
+		expRecommended, expMissing int
+	}{
+		{
+			name:           "no warnings",
+			profile:        `{"name": "A", "description": "B"}`,
+			valid:          true,
+			expRecommended: 2,
+		},
+		{
+			name:    "deprecated and recommended properties",
+			profile: `{"name": "A", "fax": "1", "contact": {"telex": "2"}, "offers": [{"title": "C"}]}`,
+			valid:   true,
+			expTitles: []string{
+				"Deprecated Property",
+				"Deprecated Property",
+				"Missing Recommended Property",
+				"Missing Recommended Property",
+			},
+			expSources: [][]string{
+				{"pointer", "/contact/telex"},
+				{"pointer", "/fax"},
+				{"pointer", "/offers/0/price"},
+				{"pointer", "/description"},
+			},
+			expRecommended: 3,
+			expMissing:     2,
+		},
+		{
+			name:           "warnings of an invalid profile",
+			profile:        `{"name": "A", "description": "B", "offers": [{}]}`,
+			valid:          false,
+			expTitles:      []string{"Missing Recommended Property"},
+			expSources:     [][]string{{"pointer", "/offers/0/price"}},
+			expRecommended: 3,
+			expMissing:     1,
+		},
+	}
+
+	for draft, schema := range schemas {
+		for _, tt := range tests {
+			t.Run(draft+"/"+tt.name, func(t *testing.T) {
+				result := validate(t, schema, tt.profile)
+				require.Equal(t, tt.valid, result.Valid, result.Details)
+				require.Equal(t, tt.expTitles, result.WarningMessages)
+				require.Equal(t, tt.expSources, result.WarningSources)
+				require.Equal(t, tt.expRecommended, result.RecommendedProperties)
+				require.Equal(t, tt.expMissing, result.MissingRecommended())
+				for _, detail := range result.WarningDetails {
+					require.Contains(t, detail, "- Schema: test")
+				}
+			})
+		}
+	}
+}
+
+func TestValidateErrorCodes(t *testing.T) {
+	properties := `
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"age": {"type": "integer", "minimum": 0}
+		},
+		"required": ["name", "email"]`
+	schemas := map[string]string{
+		"draft-07": `{` + properties + `}`,
+		"2020-12": `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			` + properties + `
+		}`,
+	}
+
+	for dialect, schema := range schemas {
+		t.Run(dialect, func(t *testing.T) {
+			result := validate(t, schema, `{"name": "A", "age": -1}`)
+			require.False(t, result.Valid)
+
+			errors := result.Errors()
+			codes := make(map[string]map[string]string, len(errors))
+			for _, e := range errors {
+				codes[e.Code] = e.Meta
+			}
+			require.Equal(t, map[string]map[string]string{
+				"required":   {"property": "email", "schema": "test"},
+				"number_gte": {"min": "0", "schema": "test"},
+			}, codes)
+		})
+	}
+}
+
+func TestValidatePrivateRefs(t *testing.T) {
+	requested := false
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requested = true
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"type": "string"}`))
+		},
+	))
+	defer ts.Close()
+
+	properties := `"type": "object",
+		"properties": {"name": {"$ref": "` + ts.URL + `/name.json"}}`
+	schemas := map[string]string{
+		"draft-07": `{` + properties + `}`,
+		"2020-12": `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			` + properties + `
+		}`,
+	}
+
+	for dialect, schema := range schemas {
+		t.Run(dialect, func(t *testing.T) {
+			result := validate(t, schema, `{"name": "A"}`)
+			require.False(t, result.Valid)
+			require.Contains(t, result.Details[0], "blocked address")
+			require.False(t, requested)
+		})
+	}
+}
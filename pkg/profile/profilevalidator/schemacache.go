@@ -0,0 +1,50 @@
+package profilevalidator
+
+import "sync"
+
+// SchemaCache is a schema loader that keeps the schemas loaded by another
+// loader in memory, so that each schema is fetched and compiled only once.
+// It is safe for concurrent use.
+type SchemaCache struct {
+	loader Loader
+
+	mu      sync.RWMutex
+	schemas map[string]Schema
+}
+
+// NewSchemaCache creates a new SchemaCache that loads schemas with the given
+// loader.
+func NewSchemaCache(loader Loader) *SchemaCache {
+	return &SchemaCache{
+		loader:  loader,
+		schemas: make(map[string]Schema),
+	}
+}
+
+// Load implements the Loader interface. Schemas that fail to load aren't
+// cached, so they are loaded again next time.
+func (c *SchemaCache) Load(source string) (Schema, error) {
+	c.mu.RLock()
+	schema, ok := c.schemas[source]
+	c.mu.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	schema, err := c.loader.Load(source)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.schemas[source] = schema
+	c.mu.Unlock()
+	return schema, nil
+}
+
+// Reset drops all cached schemas.
+func (c *SchemaCache) Reset() {
+	c.mu.Lock()
+	c.schemas = make(map[string]Schema)
+	c.mu.Unlock()
+}
@@ -0,0 +1,69 @@
+package profilevalidator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilevalidator"
+)
+
+// countingLoader loads schemas from strings and counts the loads.
+type countingLoader struct {
+	loads int
+}
+
+func (l *countingLoader) Load(source string) (profilevalidator.Schema, error) {
+	l.loads++
+	if source == "" {
+		return nil, errors.New("empty schema")
+	}
+	return (&profilevalidator.StrSchemaLoader{}).Load(source)
+}
+
+func TestSchemaCache(t *testing.T) {
+	loader := &countingLoader{}
+	cache := profilevalidator.NewSchemaCache(loader)
+	schema := `{"type": "object", "required": ["name"]}`
+
+	first, err := cache.Load(schema)
+	require.NoError(t, err)
+	second, err := cache.Load(schema)
+	require.NoError(t, err)
+	require.Same(t, first, second)
+	require.Equal(t, 1, loader.loads)
+
+	// Failed loads aren't cached.
+	_, err = cache.Load("")
+	require.Error(t, err)
+	_, err = cache.Load("")
+	require.Error(t, err)
+	require.Equal(t, 3, loader.loads)
+
+	cache.Reset()
+	_, err = cache.Load(schema)
+	require.NoError(t, err)
+	require.Equal(t, 4, loader.loads)
+}
+
+func TestSchemaCacheValidate(t *testing.T) {
+	cache := profilevalidator.NewSchemaCache(&profilevalidator.StrSchemaLoader{})
+	schema := `{"type": "object", "required": ["name"]}`
+
+	for _, tc := range []struct {
+		profile string
+		valid   bool
+	}{
+		{profile: `{"name": "A"}`, valid: true},
+		{profile: `{}`, valid: false},
+	} {
+		validator, err := profilevalidator.NewBuilder().
+			WithStrProfile(tc.profile).
+			WithJSONSchemas([]string{"test"}, []string{schema}).
+			WithSchemaLoader(cache).
+			Build()
+		require.NoError(t, err)
+		require.Equal(t, tc.valid, validator.Validate().Valid)
+	}
+}
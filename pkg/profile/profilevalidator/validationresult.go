@@ -1,5 +1,20 @@
 package profilevalidator
 
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/i18n"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+)
+
+// Issue is an error or a warning about a piece of a profile. It is identified
+// by a message code of the i18n package, along with the parameters to render
+// the message with, so that it can be rendered in any language.
+type Issue struct {
+	Code   string
+	Params map[string]string
+	// Source indicates the piece of data, e.g. {"pointer", "/name"}.
+	Source []string
+}
+
 // ValidationResult is the results from a schema validation operation.
 type ValidationResult struct {
 	// Whether the validation passed.
@@ -12,6 +27,26 @@ type ValidationResult struct {
 	Sources [][]string
 	// HTTP status codes associated with each error.
 	ErrorStatus []int
+	// Message codes of the errors, empty for errors appended without one.
+	ErrorCodes []string
+	// Parameters to render the messages of the errors with.
+	ErrorParams []map[string]string
+	// Titles of the warnings, which don't make the profile invalid.
+	WarningMessages []string
+	// Detailed descriptions of the warnings.
+	WarningDetails []string
+	// WarningSources indicates the pieces of data the warnings are about.
+	WarningSources [][]string
+	// Message codes of the warnings.
+	WarningCodes []string
+	// Parameters to render the messages of the warnings with.
+	WarningParams []map[string]string
+	// Number of properties the schemas recommend, whether they are present
+	// or missing. Missing ones are reported as warnings.
+	RecommendedProperties int
+	// Revisions of the schemas the profile was validated against, for the
+	// schemas loaded from the library.
+	SchemaRevisions []SchemaRevision
 }
 
 // NewValidationResult initializes a new ValidationResult object with default values.
@@ -86,18 +121,125 @@ func (vr *ValidationResult) AppendErrors(
 	}
 }
 
+// AppendIssues adds errors rendered in the default language, keeping their
+// codes and parameters.
+func (vr *ValidationResult) AppendIssues(issues []Issue, status int) {
+	for _, issue := range issues {
+		title, detail := i18n.Render(i18n.DefaultLanguage, issue.Code, issue.Params)
+		vr.AppendError(title, detail, issue.Source, status)
+		vr.setCode(len(vr.ErrorMessages)-1, issue.Code, issue.Params)
+	}
+}
+
+// setCode sets the code and parameters of the error at the given index. The
+// errors appended without a code before it get an empty one.
+func (vr *ValidationResult) setCode(
+	index int,
+	code string,
+	params map[string]string,
+) {
+	for len(vr.ErrorCodes) < index {
+		vr.ErrorCodes = append(vr.ErrorCodes, "")
+		vr.ErrorParams = append(vr.ErrorParams, nil)
+	}
+	vr.ErrorCodes = append(vr.ErrorCodes, code)
+	vr.ErrorParams = append(vr.ErrorParams, params)
+}
+
+// AppendWarnings adds warnings rendered in the default language to the
+// ValidationResult without affecting its validity.
+func (vr *ValidationResult) AppendWarnings(issues []Issue) {
+	if vr == nil {
+		return
+	}
+	for _, issue := range issues {
+		title, detail := i18n.Render(i18n.DefaultLanguage, issue.Code, issue.Params)
+		vr.WarningMessages = append(vr.WarningMessages, title)
+		vr.WarningDetails = append(vr.WarningDetails, detail)
+		vr.WarningSources = append(vr.WarningSources, issue.Source)
+		vr.WarningCodes = append(vr.WarningCodes, issue.Code)
+		vr.WarningParams = append(vr.WarningParams, issue.Params)
+	}
+}
+
+// MissingRecommended returns the number of recommended properties missing
+// from the profile.
+func (vr *ValidationResult) MissingRecommended() int {
+	missing := 0
+	for _, code := range vr.WarningCodes {
+		if code == i18n.CodeRecommended {
+			missing++
+		}
+	}
+	return missing
+}
+
+// Errors returns the errors in JSON:API format, along with their codes.
+func (vr *ValidationResult) Errors() []jsonapi.Error {
+	errors := jsonapi.NewError(
+		vr.ErrorMessages,
+		vr.Details,
+		vr.Sources,
+		vr.ErrorStatus,
+	)
+	return withCodes(errors, vr.ErrorCodes, vr.ErrorParams)
+}
+
+// Warnings returns the warnings in JSON:API format, along with their codes.
+func (vr *ValidationResult) Warnings() []jsonapi.Error {
+	warnings := jsonapi.NewError(
+		vr.WarningMessages,
+		vr.WarningDetails,
+		vr.WarningSources,
+		nil,
+	)
+	return withCodes(warnings, vr.WarningCodes, vr.WarningParams)
+}
+
+// withCodes sets the codes and parameters of the errors.
+func withCodes(
+	errors []jsonapi.Error,
+	codes []string,
+	params []map[string]string,
+) []jsonapi.Error {
+	for i := range errors {
+		if i < len(codes) && codes[i] != "" {
+			errors[i].Code = codes[i]
+			errors[i].Meta = params[i]
+		}
+	}
+	return errors
+}
+
 // Merge combines another ValidationResult into the current one.
 func (vr *ValidationResult) Merge(other *ValidationResult) *ValidationResult {
-	if vr == nil || other == nil || other.Valid {
+	if vr == nil || other == nil {
+		return vr
+	}
+
+	vr.WarningMessages = append(vr.WarningMessages, other.WarningMessages...)
+	vr.WarningDetails = append(vr.WarningDetails, other.WarningDetails...)
+	vr.WarningSources = append(vr.WarningSources, other.WarningSources...)
+	vr.WarningCodes = append(vr.WarningCodes, other.WarningCodes...)
+	vr.WarningParams = append(vr.WarningParams, other.WarningParams...)
+	vr.RecommendedProperties += other.RecommendedProperties
+	vr.SchemaRevisions = append(vr.SchemaRevisions, other.SchemaRevisions...)
+	if other.Valid {
 		return vr
 	}
 
+	offset := len(vr.ErrorMessages)
 	vr.AppendErrors(
 		other.ErrorMessages,
 		other.Details,
 		other.Sources,
 		other.ErrorStatus,
 	)
+	for i, code := range other.ErrorCodes {
+		if code != "" {
+			vr.setCode(offset+i, code, other.ErrorParams[i])
+		}
+	}
 
 	return vr
 }
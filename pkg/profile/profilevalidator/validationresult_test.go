@@ -146,3 +146,71 @@ func TestAppendErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeWarnings(t *testing.T) {
+	vr := profilevalidator.NewValidationResult()
+	vr.AppendWarnings([]profilevalidator.Issue{{
+		Code:   "deprecated",
+		Params: map[string]string{"property": "fax", "schema": "test"},
+		Source: []string{"pointer", "/fax"},
+	}})
+
+	other := profilevalidator.NewValidationResult()
+	other.AppendWarnings([]profilevalidator.Issue{{
+		Code:   "recommended",
+		Params: map[string]string{"property": "description", "schema": "test"},
+		Source: []string{"pointer", "/description"},
+	}})
+
+	// Warnings of a valid result are merged without making it invalid.
+	vr.Merge(other)
+	require.True(t, vr.Valid)
+	require.Equal(
+		t,
+		[]string{"Deprecated Property", "Missing Recommended Property"},
+		vr.WarningMessages,
+	)
+	require.Equal(
+		t,
+		[]string{
+			"The `fax` property is deprecated - Schema: test",
+			"The `description` property is recommended - Schema: test",
+		},
+		vr.WarningDetails,
+	)
+	require.Equal(
+		t,
+		[][]string{{"pointer", "/fax"}, {"pointer", "/description"}},
+		vr.WarningSources,
+	)
+	require.Equal(t, []string{"deprecated", "recommended"}, vr.WarningCodes)
+}
+
+func TestMergeErrorCodes(t *testing.T) {
+	vr := profilevalidator.NewValidationResult()
+	vr.AppendError("Error 1", "Detail 1", nil, 400)
+
+	other := profilevalidator.NewValidationResult()
+	other.AppendIssues([]profilevalidator.Issue{{
+		Code:   "required",
+		Params: map[string]string{"property": "name", "schema": "test"},
+		Source: []string{"pointer", "/name"},
+	}}, 400)
+
+	vr.Merge(other)
+	errors := vr.Errors()
+	require.Len(t, errors, 2)
+	require.Empty(t, errors[0].Code)
+	require.Equal(t, "required", errors[1].Code)
+	require.Equal(t, "Missing Required Property", errors[1].Title)
+	require.Equal(
+		t,
+		"The `name` property is required - Schema: test",
+		errors[1].Detail,
+	)
+	require.Equal(
+		t,
+		map[string]string{"property": "name", "schema": "test"},
+		errors[1].Meta,
+	)
+}
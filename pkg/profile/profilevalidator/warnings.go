@@ -0,0 +1,176 @@
+package profilevalidator
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/i18n"
+)
+
+// maxRefDepth limits how many $refs are followed in a row, in case a schema
+// refers to itself.
+const maxRefDepth = 32
+
+// schemaDoc is the decoded document of a schema. Warnings are derived from
+// its annotations, which validation ignores:
+//
+//   - "deprecated": true on a property's schema warns when the property is
+//     present.
+//   - "recommended": ["name", ...] on an object's schema warns when one of
+//     the listed properties is missing, like "required" does for errors.
+type schemaDoc struct {
+	doc      interface{}
+	revision SchemaRevision
+}
+
+// Revision returns the revision of the schema the library served.
+func (s schemaDoc) Revision() SchemaRevision {
+	return s.revision
+}
+
+// Warnings returns the warnings about the profile. The schema name is one of
+// their parameters.
+func (s schemaDoc) Warnings(
+	schemaName string,
+	profile map[string]interface{},
+) []Issue {
+	w := &warningCollector{root: s.doc, schemaName: schemaName}
+	w.walk(s.doc, profile, "", 0)
+	return w.issues
+}
+
+// Recommended returns the number of properties of the profile the schema
+// recommends, whether they are present or not.
+func (s schemaDoc) Recommended(profile map[string]interface{}) int {
+	w := &warningCollector{root: s.doc}
+	w.walk(s.doc, profile, "", 0)
+	return w.recommended
+}
+
+// warningCollector walks a schema along with the profile and collects the
+// warnings.
+type warningCollector struct {
+	root       interface{}
+	schemaName string
+
+	issues []Issue
+	// Number of recommended properties checked.
+	recommended int
+}
+
+func (w *warningCollector) add(code, field string) {
+	w.issues = append(w.issues, Issue{
+		Code: code,
+		Params: map[string]string{
+			"property": strings.TrimPrefix(field, "/"),
+			"schema":   w.schemaName,
+		},
+		Source: []string{"pointer", field},
+	})
+}
+
+func (w *warningCollector) walk(
+	schema interface{},
+	data interface{},
+	field string,
+	refDepth int,
+) {
+	obj, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if ref, ok := obj["$ref"].(string); ok && refDepth < maxRefDepth {
+		w.walk(w.resolveRef(ref), data, field, refDepth+1)
+	}
+	if allOf, ok := obj["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			w.walk(sub, data, field, refDepth)
+		}
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		properties, _ := obj["properties"].(map[string]interface{})
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			value := v[name]
+			sub, ok := properties[name]
+			if !ok {
+				continue
+			}
+			subField := field + "/" + name
+			if w.isDeprecated(sub) {
+				w.add(i18n.CodeDeprecated, subField)
+			}
+			w.walk(sub, value, subField, 0)
+		}
+
+		recommended, _ := obj["recommended"].([]interface{})
+		for _, r := range recommended {
+			name, ok := r.(string)
+			if !ok {
+				continue
+			}
+			w.recommended++
+			if _, ok := v[name]; !ok {
+				subField := field + "/" + name
+				w.add(i18n.CodeRecommended, subField)
+			}
+		}
+	case []interface{}:
+		items, ok := obj["items"]
+		if !ok {
+			return
+		}
+		for i, item := range v {
+			w.walk(items, item, field+"/"+strconv.Itoa(i), 0)
+		}
+	}
+}
+
+// isDeprecated reports whether the schema, or the one it refers to, is
+// annotated as deprecated.
+func (w *warningCollector) isDeprecated(schema interface{}) bool {
+	for depth := 0; depth < maxRefDepth; depth++ {
+		obj, ok := schema.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if deprecated, ok := obj["deprecated"].(bool); ok && deprecated {
+			return true
+		}
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return false
+		}
+		schema = w.resolveRef(ref)
+	}
+	return false
+}
+
+// resolveRef resolves a reference within the schema document, e.g.
+// "#/$defs/name". Other references can't be resolved and return nil.
+func (w *warningCollector) resolveRef(ref string) interface{} {
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+	current := w.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = obj[token]
+	}
+	return current
+}
@@ -0,0 +1,92 @@
+// Package quality scores how complete and well maintained a profile is, so
+// that rich profiles can be ranked above bare ones.
+package quality
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// MaxScore is the score of a profile meeting every criterion.
+	MaxScore = 100
+
+	// fullTags is the number of tags that earns the full tags score.
+	fullTags = 5
+	// freshAge is the age under which a profile is considered fresh.
+	freshAge = 30 * 24 * time.Hour
+	// staleAge is the age from which a profile earns no freshness score.
+	staleAge = 365 * 24 * time.Hour
+)
+
+// Weights of the criteria, adding up to 1.
+const (
+	recommendedWeight = 0.3
+	geolocationWeight = 0.15
+	primaryURLWeight  = 0.2
+	tagsWeight        = 0.15
+	freshnessWeight   = 0.2
+)
+
+// Factors are the criteria a profile is scored on. The validation service
+// sets them from the profile, and the index adds LastUpdated.
+type Factors struct {
+	// Recommended is the number of properties the schemas recommend.
+	Recommended int `bson:"recommended" json:"recommended"`
+	// MissingRecommended is the number of recommended properties missing.
+	MissingRecommended int `bson:"missing_recommended" json:"missing_recommended"`
+	// Geolocation is true if the profile has a geolocation.
+	Geolocation bool `bson:"geolocation" json:"geolocation"`
+	// PrimaryURLReachable is true if the profile has a primary_url that
+	// responded successfully.
+	PrimaryURLReachable bool `bson:"primary_url_reachable" json:"primary_url_reachable"`
+	// Tags is the number of tags of the profile.
+	Tags int `bson:"tags" json:"tags"`
+	// LastUpdated is the Unix time the profile last changed, or 0 if unknown.
+	LastUpdated int64 `bson:"last_updated,omitempty" json:"last_updated,omitempty"`
+}
+
+// Score returns the quality score of the profile at the given time, from 0 to
+// MaxScore.
+func (f Factors) Score(now time.Time) int {
+	score := recommendedWeight*f.recommendedScore() +
+		tagsWeight*math.Min(float64(f.Tags), fullTags)/fullTags +
+		freshnessWeight*f.freshnessScore(now)
+	if f.Geolocation {
+		score += geolocationWeight
+	}
+	if f.PrimaryURLReachable {
+		score += primaryURLWeight
+	}
+	return int(math.Round(score * MaxScore))
+}
+
+// recommendedScore returns the share of recommended properties present. A
+// profile whose schemas recommend nothing gets the full score.
+func (f Factors) recommendedScore() float64 {
+	if f.Recommended <= 0 {
+		return 1
+	}
+	present := f.Recommended - f.MissingRecommended
+	if present < 0 {
+		present = 0
+	}
+	return float64(present) / float64(f.Recommended)
+}
+
+// freshnessScore decreases linearly from 1 for profiles updated within
+// freshAge to 0 for profiles not updated for staleAge.
+func (f Factors) freshnessScore(now time.Time) float64 {
+	if f.LastUpdated <= 0 {
+		return 0
+	}
+	age := now.Sub(time.Unix(f.LastUpdated, 0))
+	switch {
+	case age <= freshAge:
+		return 1
+	case age >= staleAge:
+		return 0
+	default:
+		return float64(staleAge-age) / float64(staleAge-freshAge)
+	}
+}
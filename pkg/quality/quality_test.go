@@ -0,0 +1,63 @@
+package quality_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/quality"
+)
+
+func TestScore(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := int64(24 * 60 * 60)
+
+	tests := []struct {
+		name     string
+		factors  quality.Factors
+		expected int
+	}{
+		{
+			name:     "bare profile",
+			factors:  quality.Factors{Recommended: 4, MissingRecommended: 4},
+			expected: 0,
+		},
+		{
+			name: "complete and fresh profile",
+			factors: quality.Factors{
+				Recommended:         4,
+				Geolocation:         true,
+				PrimaryURLReachable: true,
+				Tags:                8,
+				LastUpdated:         now.Unix() - day,
+			},
+			expected: 100,
+		},
+		{
+			name: "nothing recommended",
+			factors: quality.Factors{
+				LastUpdated: now.Unix() - 400*day,
+			},
+			expected: 30,
+		},
+		{
+			name: "partial profile",
+			factors: quality.Factors{
+				Recommended:        4,
+				MissingRecommended: 2,
+				Geolocation:        true,
+				Tags:               2,
+				// Halfway between fresh and stale.
+				LastUpdated: now.Unix() - 197*day - day/2,
+			},
+			expected: 15 + 15 + 6 + 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.factors.Score(now))
+		})
+	}
+}
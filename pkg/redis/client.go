@@ -34,3 +34,7 @@ func (r *redisImpl) Get(key string) (string, error) {
 	}
 	return get.Val(), nil
 }
+
+func (r *redisImpl) Del(keys ...string) error {
+	return r.client.Del(context.Background(), keys...).Err()
+}
@@ -0,0 +1,6 @@
+package redis
+
+// SchemasVersionKey holds the version of the schemas last published by the
+// schemaparser. Services caching schemas or validation results drop them when
+// it changes.
+const SchemasVersionKey = "schemas:version"
@@ -22,3 +22,7 @@ func (*redismock) Set(
 func (*redismock) Get(_ string) (string, error) {
 	return "", nil
 }
+
+func (*redismock) Del(_ ...string) error {
+	return nil
+}
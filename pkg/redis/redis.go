@@ -11,6 +11,7 @@ type Redis interface {
 	Ping() error
 	Set(key string, value interface{}, expiration time.Duration) error
 	Get(key string) (string, error)
+	Del(keys ...string) error
 }
 
 func NewClient(url string) Redis {
@@ -0,0 +1,131 @@
+package schemacodegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// goPackage is the package of the generated Go file.
+const goPackage = "murmurations"
+
+// goGenerator generates a struct for the schema, along with the structs of
+// its object properties.
+type goGenerator struct {
+	buf   bytes.Buffer
+	names typeNames
+	queue []declaration
+}
+
+// generateGo generates a Go file with the struct of the profiles of the
+// schema. Optional properties are omitted when empty, and optional numbers,
+// booleans and objects are pointers so that zero values can be told from
+// missing ones.
+func generateGo(schemaName string, schema *object) ([]byte, error) {
+	g := &goGenerator{names: typeNames{}}
+	fmt.Fprintf(
+		&g.buf,
+		"// Code generated by the Murmurations library from %s. DO NOT EDIT.\n\n",
+		schemaName,
+	)
+	fmt.Fprintf(&g.buf, "package %s\n", goPackage)
+
+	g.declare(typeName(schemaName), "", schema)
+	for len(g.queue) > 0 {
+		d := g.queue[0]
+		g.queue = g.queue[1:]
+		comment := fmt.Sprintf("%s is a profile of the %s schema.", d.name, schemaName)
+		if d.path != "" {
+			comment = fmt.Sprintf("%s is the value of %s.", d.name, d.path)
+		}
+		g.writeStruct(d, comment)
+	}
+
+	return format.Source(g.buf.Bytes())
+}
+
+// declare queues the struct of the object schema and returns its name.
+func (g *goGenerator) declare(name, path string, schema *object) string {
+	name = g.names.unique(name)
+	g.queue = append(g.queue, declaration{name: name, path: path, schema: schema})
+	return name
+}
+
+func (g *goGenerator) writeStruct(d declaration, comment string) {
+	g.buf.WriteString("\n")
+	if comment != "" {
+		fmt.Fprintf(&g.buf, "// %s\n", comment)
+	}
+	fmt.Fprintf(&g.buf, "type %s struct {\n", d.name)
+
+	fields := typeNames{}
+	for _, prop := range properties(d.schema) {
+		if comment := doc(prop.schema); comment != "" {
+			fmt.Fprintf(&g.buf, "// %s\n", comment)
+		}
+		if values := stringEnum(prop.schema); len(values) > 0 {
+			fmt.Fprintf(&g.buf, "// One of: %s.\n", strings.Join(values, ", "))
+		}
+
+		goType := g.goType(d.name+exportedName(prop.name), join(d.path, prop.name), prop.schema)
+		tag := prop.name
+		if !prop.required {
+			tag += ",omitempty"
+			if isStruct(prop.schema) || isScalar(prop.schema) && kind(prop.schema) != "string" {
+				goType = "*" + goType
+			}
+		} else if nullable(prop.schema) && (isStruct(prop.schema) || isScalar(prop.schema)) {
+			goType = "*" + goType
+		}
+		fmt.Fprintf(
+			&g.buf,
+			"%s %s `json:%s`\n",
+			fields.unique(exportedName(prop.name)),
+			goType,
+			strconv.Quote(tag),
+		)
+	}
+	g.buf.WriteString("}\n")
+}
+
+// goType returns the Go type of the values of the schema of the property at
+// the path. The name is given to the struct declared for an object schema.
+func (g *goGenerator) goType(name, path string, schema *object) string {
+	switch kind(schema) {
+	case "string":
+		return "string"
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		items := schema.object("items")
+		if items == nil {
+			return "[]interface{}"
+		}
+		return "[]" + g.goType(name+"Item", path+"[]", items)
+	case "object":
+		if isStruct(schema) {
+			return g.declare(name, path, schema)
+		}
+		if additional := schema.object("additionalProperties"); additional != nil {
+			return "map[string]" + g.goType(name+"Value", path+".*", additional)
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// isScalar reports whether the schema only allows strings, numbers or
+// booleans, besides null.
+func isScalar(schema *object) bool {
+	switch kind(schema) {
+	case "string", "integer", "number", "boolean":
+		return true
+	}
+	return false
+}
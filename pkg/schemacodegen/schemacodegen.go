@@ -0,0 +1,311 @@
+// Package schemacodegen generates code and templates from the full schemas
+// of the library, with their $refs resolved: Go structs, TypeScript
+// interfaces, an empty profile to fill in, and the CSV header the dataproxy
+// batch importer reads profiles from. Properties are generated in the order
+// of the schema.
+package schemacodegen
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaname"
+)
+
+// Targets of the generated artifacts.
+const (
+	TargetGo         = "go"
+	TargetTypeScript = "typescript"
+	TargetTemplate   = "template"
+	TargetCSV        = "csv"
+)
+
+// Targets lists the targets in the order they are documented.
+var Targets = []string{TargetGo, TargetTypeScript, TargetTemplate, TargetCSV}
+
+// IsTarget reports whether artifacts can be generated for the target.
+func IsTarget(target string) bool {
+	for _, t := range Targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Generate generates the artifact of the target from the full schema with
+// the given name.
+func Generate(target string, schemaName string, schema bson.D) ([]byte, error) {
+	root := toObject(schema)
+	switch target {
+	case TargetGo:
+		return generateGo(schemaName, root)
+	case TargetTypeScript:
+		return generateTypeScript(schemaName, root), nil
+	case TargetTemplate:
+		return generateTemplate(schemaName, root)
+	case TargetCSV:
+		return generateCSV(root)
+	}
+	return nil, fmt.Errorf("unknown target %q", target)
+}
+
+// object is a JSON object that keeps the order of its keys.
+type object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// toObject converts a BSON document, as parsed or as read from MongoDB.
+func toObject(d bson.D) *object {
+	o := &object{values: make(map[string]interface{}, len(d))}
+	for _, e := range d {
+		o.keys = append(o.keys, e.Key)
+		o.values[e.Key] = normalize(e.Value)
+	}
+	return o
+}
+
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.D:
+		return toObject(v)
+	case bson.M:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		d := make(bson.D, 0, len(v))
+		for _, key := range keys {
+			d = append(d, bson.E{Key: key, Value: v[key]})
+		}
+		return toObject(d)
+	case primitive.A:
+		return normalize([]interface{}(v))
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, value := range v {
+			values[i] = normalize(value)
+		}
+		return values
+	}
+	return value
+}
+
+func (o *object) get(key string) interface{} {
+	if o == nil {
+		return nil
+	}
+	return o.values[key]
+}
+
+func (o *object) object(key string) *object {
+	child, _ := o.get(key).(*object)
+	return child
+}
+
+func (o *object) str(key string) string {
+	s, _ := o.get(key).(string)
+	return s
+}
+
+func (o *object) has(key string) bool {
+	if o == nil {
+		return false
+	}
+	_, ok := o.values[key]
+	return ok
+}
+
+// property is a property of an object schema.
+type property struct {
+	name     string
+	schema   *object
+	required bool
+}
+
+// properties returns the properties of the object schema.
+func properties(schema *object) []property {
+	required := map[string]bool{}
+	values, _ := schema.get("required").([]interface{})
+	for _, v := range values {
+		if name, ok := v.(string); ok {
+			required[name] = true
+		}
+	}
+
+	props := schema.object("properties")
+	if props == nil {
+		return nil
+	}
+	result := make([]property, 0, len(props.keys))
+	for _, name := range props.keys {
+		result = append(result, property{
+			name:     name,
+			schema:   props.object(name),
+			required: required[name],
+		})
+	}
+	return result
+}
+
+// kind returns the type of the values the schema allows besides null, or an
+// empty string if it allows several types or doesn't tell.
+func kind(schema *object) string {
+	var types []string
+	switch t := schema.get("type").(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+
+	var nonNull []string
+	for _, t := range types {
+		if t != "null" {
+			nonNull = append(nonNull, t)
+		}
+	}
+	switch {
+	case len(nonNull) == 1:
+		return nonNull[0]
+	case len(nonNull) > 1:
+		return ""
+	case schema.has("properties"):
+		return "object"
+	case schema.has("items"):
+		return "array"
+	}
+	return ""
+}
+
+// nullable reports whether the schema allows null.
+func nullable(schema *object) bool {
+	types, _ := schema.get("type").([]interface{})
+	for _, t := range types {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// isStruct reports whether the schema is an object schema with properties,
+// which a type is declared for.
+func isStruct(schema *object) bool {
+	return kind(schema) == "object" && len(properties(schema)) > 0
+}
+
+// stringEnum returns the allowed values of a schema only allowing strings.
+func stringEnum(schema *object) []string {
+	values, _ := schema.get("enum").([]interface{})
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return nil
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// doc returns the description of the schema on a single line, or its title.
+func doc(schema *object) string {
+	text := schema.str("description")
+	if text == "" {
+		text = schema.str("title")
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// typeName returns the name of the type generated for the schema, e.g.
+// OrganizationsSchema for organizations_schema-v1.0.0.
+func typeName(schemaName string) string {
+	base := schemaName
+	if name, ok := schemaname.Parse(schemaName); ok {
+		base = name.Base
+	}
+	return exportedName(base)
+}
+
+// initialisms are the words written in capitals in Go identifiers.
+var initialisms = map[string]bool{
+	"API": true, "CSS": true, "CSV": true, "DNS": true, "HTML": true,
+	"HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"OID": true, "RSS": true, "SQL": true, "URI": true, "URL": true,
+	"UUID": true, "XML": true,
+}
+
+var wordSeparator = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// exportedName converts a name to an exported identifier, e.g. primary_url
+// to PrimaryURL.
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, word := range wordSeparator.Split(name, -1) {
+		if word == "" {
+			continue
+		}
+		upper := strings.ToUpper(word)
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		// Plurals too, e.g. urls to URLs.
+		if plural := strings.TrimSuffix(upper, "S"); plural != upper && initialisms[plural] {
+			b.WriteString(plural + "s")
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	if b.Len() == 0 {
+		return "Value"
+	}
+	identifier := b.String()
+	if unicode.IsDigit(rune(identifier[0])) {
+		return "X" + identifier
+	}
+	return identifier
+}
+
+// typeNames hands out unique type names.
+type typeNames map[string]bool
+
+func (names typeNames) unique(name string) string {
+	unique := name
+	for i := 2; names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	names[unique] = true
+	return unique
+}
+
+// declaration is an object schema a type is generated for.
+type declaration struct {
+	name string
+	// path is the path of the property the schema is for, e.g. "urls[]",
+	// or empty for the root of the schema.
+	path   string
+	schema *object
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
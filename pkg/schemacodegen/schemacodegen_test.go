@@ -0,0 +1,192 @@
+package schemacodegen_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemacodegen"
+)
+
+const testSchema = `{
+	"title": "Test Schema",
+	"type": "object",
+	"properties": {
+		"linked_schemas": {"title": "Linked Schemas", "type": "array", "items": {"type": "string"}},
+		"name": {"title": "Name", "description": "The name of the\n entity", "type": "string"},
+		"primary_url": {"title": "Primary URL", "type": "string"},
+		"status": {"type": "string", "enum": ["active", "inactive"]},
+		"count": {"type": "integer"},
+		"score": {"type": ["number", "null"]},
+		"geolocation": {
+			"title": "Geolocation",
+			"type": "object",
+			"properties": {"lat": {"type": "number"}, "lon": {"type": "number"}},
+			"required": ["lat", "lon"]
+		},
+		"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}},
+		"urls": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "url": {"type": "string"}},
+				"required": ["url"]
+			}
+		},
+		"extra": {"type": "object"},
+		"country": {"type": "string", "default": "CA"},
+		"any": {}
+	},
+	"required": ["linked_schemas", "name"]
+}`
+
+const expGo = "// Code generated by the Murmurations library from test_schema-v1.0.0. DO NOT EDIT.\n" + `
+package murmurations
+
+// TestSchema is a profile of the test_schema-v1.0.0 schema.
+type TestSchema struct {
+	// Linked Schemas
+	LinkedSchemas []string ` + "`json:\"linked_schemas\"`" + `
+	// The name of the entity
+	Name string ` + "`json:\"name\"`" + `
+	// Primary URL
+	PrimaryURL string ` + "`json:\"primary_url,omitempty\"`" + `
+	// One of: active, inactive.
+	Status string   ` + "`json:\"status,omitempty\"`" + `
+	Count  *int64   ` + "`json:\"count,omitempty\"`" + `
+	Score  *float64 ` + "`json:\"score,omitempty\"`" + `
+	// Geolocation
+	Geolocation *TestSchemaGeolocation ` + "`json:\"geolocation,omitempty\"`" + `
+	Tags        []string               ` + "`json:\"tags,omitempty\"`" + `
+	URLs        []TestSchemaURLsItem   ` + "`json:\"urls,omitempty\"`" + `
+	Extra       map[string]interface{} ` + "`json:\"extra,omitempty\"`" + `
+	Country     string                 ` + "`json:\"country,omitempty\"`" + `
+	Any         interface{}            ` + "`json:\"any,omitempty\"`" + `
+}
+
+// TestSchemaGeolocation is the value of geolocation.
+type TestSchemaGeolocation struct {
+	Lat float64 ` + "`json:\"lat\"`" + `
+	Lon float64 ` + "`json:\"lon\"`" + `
+}
+
+// TestSchemaURLsItem is the value of urls[].
+type TestSchemaURLsItem struct {
+	Name string ` + "`json:\"name,omitempty\"`" + `
+	URL  string ` + "`json:\"url\"`" + `
+}
+`
+
+const expTypeScript = `// Generated by the Murmurations library from test_schema-v1.0.0.
+
+/** A profile of the test_schema-v1.0.0 schema. */
+export interface TestSchema {
+  /** Linked Schemas */
+  linked_schemas: string[];
+  /** The name of the entity */
+  name: string;
+  /** Primary URL */
+  primary_url?: string;
+  status?: "active" | "inactive";
+  count?: number;
+  score?: number | null;
+  /** Geolocation */
+  geolocation?: TestSchemaGeolocation;
+  tags?: ("a" | "b")[];
+  urls?: TestSchemaURLsItem[];
+  extra?: Record<string, unknown>;
+  country?: string;
+  any?: unknown;
+}
+
+/** The value of geolocation. */
+export interface TestSchemaGeolocation {
+  lat: number;
+  lon: number;
+}
+
+/** The value of urls[]. */
+export interface TestSchemaURLsItem {
+  name?: string;
+  url: string;
+}
+`
+
+const expTemplate = `{
+  "linked_schemas": [
+    "test_schema-v1.0.0"
+  ],
+  "name": "",
+  "primary_url": "",
+  "status": "",
+  "count": 0,
+  "score": 0,
+  "geolocation": {
+    "lat": 0,
+    "lon": 0
+  },
+  "tags": [],
+  "urls": [
+    {
+      "name": "",
+      "url": ""
+    }
+  ],
+  "extra": {},
+  "country": "CA",
+  "any": null
+}`
+
+const expCSV = "oid,name,primary_url,status,count,score,geolocation.lat,geolocation.lon," +
+	"tags(list-0),urls[0].name,urls[0].url,extra,country,any\n"
+
+func TestGenerate(t *testing.T) {
+	var schema bson.D
+	require.NoError(t, bson.UnmarshalExtJSON([]byte(testSchema), false, &schema))
+
+	tests := []struct {
+		target string
+		exp    string
+	}{
+		{target: schemacodegen.TargetGo, exp: expGo},
+		{target: schemacodegen.TargetTypeScript, exp: expTypeScript},
+		{target: schemacodegen.TargetTemplate, exp: expTemplate},
+		{target: schemacodegen.TargetCSV, exp: expCSV},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			require.True(t, schemacodegen.IsTarget(tt.target))
+			out, err := schemacodegen.Generate(tt.target, "test_schema-v1.0.0", schema)
+			require.NoError(t, err)
+			require.Equal(t, tt.exp, string(out))
+		})
+	}
+
+	require.False(t, schemacodegen.IsTarget("python"))
+	_, err := schemacodegen.Generate("python", "test_schema-v1.0.0", schema)
+	require.Error(t, err)
+}
+
+func TestGenerateNames(t *testing.T) {
+	schema := bson.D{
+		{Key: "type", Value: "object"},
+		{Key: "properties", Value: bson.D{
+			{Key: "url", Value: bson.D{{Key: "type", Value: "string"}}},
+			{Key: "URL", Value: bson.D{{Key: "type", Value: "string"}}},
+			{Key: "2nd-name", Value: bson.D{{Key: "type", Value: "string"}}},
+		}},
+	}
+
+	out, err := schemacodegen.Generate(schemacodegen.TargetGo, "fork:test_schema-v1.0.0", schema)
+	require.NoError(t, err)
+	require.Contains(t, string(out), "type ForkTestSchema struct")
+	require.Contains(t, string(out), "URL ")
+	require.Contains(t, string(out), "URL2 ")
+	require.Contains(t, string(out), "X2ndName ")
+
+	out, err = schemacodegen.Generate(schemacodegen.TargetTypeScript, "test_schema-v1.0.0", schema)
+	require.NoError(t, err)
+	require.Contains(t, string(out), `  "2nd-name"?: string;`)
+}
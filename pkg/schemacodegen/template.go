@@ -0,0 +1,120 @@
+package schemacodegen
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+// generateTemplate generates an empty profile of the schema, with every
+// property set to its const or default value if it has one, and to an empty
+// value of its type otherwise. linked_schemas lists the schema, and arrays
+// of objects hold one empty object to show their properties.
+func generateTemplate(schemaName string, schema *object) ([]byte, error) {
+	profile := templateObject(schema)
+	if _, ok := profile.Get("linked_schemas"); ok {
+		profile.Set("linked_schemas", []string{schemaName})
+	}
+	return json.MarshalIndent(profile, "", "  ")
+}
+
+func templateObject(schema *object) *orderedmap.OrderedMap {
+	result := orderedmap.New()
+	for _, prop := range properties(schema) {
+		result.Set(prop.name, templateValue(prop.schema))
+	}
+	return result
+}
+
+func templateValue(schema *object) interface{} {
+	for _, key := range []string{"const", "default"} {
+		if schema.has(key) {
+			return plain(schema.get(key))
+		}
+	}
+	switch kind(schema) {
+	case "string":
+		return ""
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		if items := schema.object("items"); isStruct(items) {
+			return []interface{}{templateObject(items)}
+		}
+		return []interface{}{}
+	case "object":
+		return templateObject(schema)
+	}
+	return nil
+}
+
+// plain converts a normalized value back to one that marshals to JSON.
+func plain(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *object:
+		result := orderedmap.New()
+		for _, key := range v.keys {
+			result.Set(key, plain(v.values[key]))
+		}
+		return result
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, value := range v {
+			values[i] = plain(value)
+		}
+		return values
+	}
+	return value
+}
+
+// generateCSV generates the header of a CSV file the dataproxy batch
+// importer reads profiles of the schema from. The oid column identifies the
+// profiles, and linked_schemas is left out as the importer sets it. Nested
+// properties are joined with dots, arrays of objects have the columns of
+// their first item, e.g. urls[0].url, and arrays of other values are lists
+// of comma-separated values, e.g. tags(list-0).
+func generateCSV(schema *object) ([]byte, error) {
+	columns := []string{"oid"}
+	for _, prop := range properties(schema) {
+		if prop.name == "linked_schemas" || prop.name == "oid" {
+			continue
+		}
+		columns = csvColumns(columns, prop.name, prop.schema)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// csvColumns appends the columns of the property at the path.
+func csvColumns(columns []string, path string, schema *object) []string {
+	switch kind(schema) {
+	case "object":
+		if !isStruct(schema) {
+			break
+		}
+		for _, prop := range properties(schema) {
+			columns = csvColumns(columns, path+"."+prop.name, prop.schema)
+		}
+		return columns
+	case "array":
+		items := schema.object("items")
+		if !isStruct(items) {
+			return append(columns, path+"(list-0)")
+		}
+		for _, prop := range properties(items) {
+			columns = csvColumns(columns, path+"[0]."+prop.name, prop.schema)
+		}
+		return columns
+	}
+	return append(columns, path)
+}
@@ -0,0 +1,133 @@
+package schemacodegen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// tsGenerator generates an interface for the schema, along with the
+// interfaces of its object properties.
+type tsGenerator struct {
+	buf   bytes.Buffer
+	names typeNames
+	queue []declaration
+}
+
+// generateTypeScript generates a TypeScript module exporting the interface of
+// the profiles of the schema. Optional properties are marked with ?, and
+// string enums are unions of their values.
+func generateTypeScript(schemaName string, schema *object) []byte {
+	g := &tsGenerator{names: typeNames{}}
+	fmt.Fprintf(&g.buf, "// Generated by the Murmurations library from %s.\n", schemaName)
+
+	g.declare(typeName(schemaName), "", schema)
+	for len(g.queue) > 0 {
+		d := g.queue[0]
+		g.queue = g.queue[1:]
+		comment := fmt.Sprintf("A profile of the %s schema.", schemaName)
+		if d.path != "" {
+			comment = fmt.Sprintf("The value of %s.", d.path)
+		}
+		g.writeInterface(d, comment)
+	}
+	return g.buf.Bytes()
+}
+
+// declare queues the interface of the object schema and returns its name.
+func (g *tsGenerator) declare(name, path string, schema *object) string {
+	name = g.names.unique(name)
+	g.queue = append(g.queue, declaration{name: name, path: path, schema: schema})
+	return name
+}
+
+func (g *tsGenerator) writeInterface(d declaration, comment string) {
+	g.buf.WriteString("\n")
+	if comment != "" {
+		fmt.Fprintf(&g.buf, "/** %s */\n", escapeComment(comment))
+	}
+	fmt.Fprintf(&g.buf, "export interface %s {\n", d.name)
+	for _, prop := range properties(d.schema) {
+		if comment := doc(prop.schema); comment != "" {
+			fmt.Fprintf(&g.buf, "  /** %s */\n", escapeComment(comment))
+		}
+		optional := ""
+		if !prop.required {
+			optional = "?"
+		}
+		fmt.Fprintf(
+			&g.buf,
+			"  %s%s: %s;\n",
+			tsKey(prop.name),
+			optional,
+			g.tsType(d.name+exportedName(prop.name), join(d.path, prop.name), prop.schema),
+		)
+	}
+	g.buf.WriteString("}\n")
+}
+
+// tsType returns the TypeScript type of the values of the schema of the
+// property at the path. The name is given to the interface declared for an
+// object schema.
+func (g *tsGenerator) tsType(name, path string, schema *object) string {
+	var t string
+	switch kind(schema) {
+	case "string":
+		t = "string"
+		if values := stringEnum(schema); len(values) > 0 {
+			quoted := make([]string, len(values))
+			for i, value := range values {
+				quoted[i] = strconv.Quote(value)
+			}
+			t = strings.Join(quoted, " | ")
+		}
+	case "integer", "number":
+		t = "number"
+	case "boolean":
+		t = "boolean"
+	case "array":
+		items := schema.object("items")
+		if items == nil {
+			t = "unknown[]"
+			break
+		}
+		t = g.tsType(name+"Item", path+"[]", items)
+		if strings.Contains(t, " ") {
+			t = "(" + t + ")"
+		}
+		t += "[]"
+	case "object":
+		switch additional := schema.object("additionalProperties"); {
+		case isStruct(schema):
+			t = g.declare(name, path, schema)
+		case additional != nil:
+			t = "Record<string, " + g.tsType(name+"Value", path+".*", additional) + ">"
+		default:
+			t = "Record<string, unknown>"
+		}
+	default:
+		t = "unknown"
+	}
+	if nullable(schema) && t != "unknown" {
+		t += " | null"
+	}
+	return t
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsKey returns the property name as an interface key, quoted if it isn't an
+// identifier.
+func tsKey(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// escapeComment keeps the text from closing the comment it is written in.
+func escapeComment(text string) string {
+	return strings.ReplaceAll(text, "*/", "*\\/")
+}
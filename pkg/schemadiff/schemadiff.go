@@ -0,0 +1,332 @@
+// Package schemadiff computes the structural differences between two versions
+// of a schema and classifies them as breaking or not. A change is breaking if
+// a profile valid against the old version may not be valid against the new
+// one.
+package schemadiff
+
+import (
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kinds of changes.
+const (
+	RequiredAdded   = "required_added"
+	RequiredRemoved = "required_removed"
+	PropertyAdded   = "property_added"
+	PropertyRemoved = "property_removed"
+	TypeChanged     = "type_changed"
+	EnumNarrowed    = "enum_narrowed"
+	EnumWidened     = "enum_widened"
+	PatternChanged  = "pattern_changed"
+	RefChanged      = "ref_changed"
+)
+
+// Change is a difference between two versions of a schema.
+type Change struct {
+	// Path is the path of the property, e.g. "member_of[].url", or empty for
+	// the root of the schema.
+	Path     string      `json:"path"           bson:"path"`
+	Kind     string      `json:"kind"           bson:"kind"`
+	Breaking bool        `json:"breaking"       bson:"breaking"`
+	From     interface{} `json:"from,omitempty" bson:"from,omitempty"`
+	To       interface{} `json:"to,omitempty"   bson:"to,omitempty"`
+}
+
+// Report lists the changes from one version of a schema to another.
+type Report struct {
+	From     string   `json:"from"     bson:"from"`
+	To       string   `json:"to"       bson:"to"`
+	Breaking bool     `json:"breaking" bson:"breaking"`
+	Changes  []Change `json:"changes"  bson:"changes"`
+}
+
+// Compare returns the report of the changes from the schema named from to the
+// schema named to. The schemas are full schemas, with their $refs resolved.
+func Compare(from string, fromSchema bson.D, to string, toSchema bson.D) *Report {
+	changes := compareObjects("", toMap(fromSchema), toMap(toSchema))
+	report := &Report{From: from, To: to, Changes: changes}
+	for _, change := range changes {
+		if change.Breaking {
+			report.Breaking = true
+			break
+		}
+	}
+	return report
+}
+
+// compareObjects compares two schemas of the property at the path.
+func compareObjects(path string, from, to map[string]interface{}) []Change {
+	changes := []Change{}
+
+	if fromRef, toRef := fieldRef(from), fieldRef(to); fromRef != toRef &&
+		fromRef != "" && toRef != "" {
+		changes = append(changes, Change{
+			Path: path, Kind: RefChanged, From: fromRef, To: toRef,
+		})
+	}
+
+	fromTypes, toTypes := types(from), types(to)
+	if !equalSets(fromTypes, toTypes) && len(fromTypes) > 0 {
+		changes = append(changes, Change{
+			Path:     path,
+			Kind:     TypeChanged,
+			Breaking: len(toTypes) > 0 && !typesWidened(fromTypes, toTypes),
+			From:     typeValue(fromTypes),
+			To:       typeValue(toTypes),
+		})
+	}
+
+	changes = append(changes, compareEnums(path, from, to)...)
+
+	fromPattern, _ := from["pattern"].(string)
+	toPattern, _ := to["pattern"].(string)
+	if fromPattern != toPattern && toPattern != "" {
+		changes = append(changes, Change{
+			Path:     path,
+			Kind:     PatternChanged,
+			Breaking: true,
+			From:     fromPattern,
+			To:       toPattern,
+		})
+	}
+
+	changes = append(changes, compareRequired(path, from, to)...)
+	changes = append(changes, compareProperties(path, from, to)...)
+
+	fromItems, fromOK := from["items"].(map[string]interface{})
+	toItems, toOK := to["items"].(map[string]interface{})
+	if fromOK && toOK {
+		changes = append(changes, compareObjects(path+"[]", fromItems, toItems)...)
+	}
+
+	return changes
+}
+
+// compareProperties compares the properties of two object schemas.
+func compareProperties(path string, from, to map[string]interface{}) []Change {
+	fromProps, _ := from["properties"].(map[string]interface{})
+	toProps, _ := to["properties"].(map[string]interface{})
+	// Removing a property only breaks profiles if no additional properties
+	// are allowed.
+	closed := to["additionalProperties"] == false
+
+	var changes []Change
+	for _, name := range sortedKeys(fromProps) {
+		toProp, ok := toProps[name].(map[string]interface{})
+		if !ok {
+			changes = append(changes, Change{
+				Path:     join(path, name),
+				Kind:     PropertyRemoved,
+				Breaking: closed,
+			})
+			continue
+		}
+		fromProp, _ := fromProps[name].(map[string]interface{})
+		changes = append(changes, compareObjects(join(path, name), fromProp, toProp)...)
+	}
+	for _, name := range sortedKeys(toProps) {
+		if _, ok := fromProps[name]; !ok {
+			changes = append(changes, Change{
+				Path: join(path, name),
+				Kind: PropertyAdded,
+			})
+		}
+	}
+	return changes
+}
+
+// compareRequired compares the required properties of two object schemas.
+func compareRequired(path string, from, to map[string]interface{}) []Change {
+	fromRequired, toRequired := stringSet(from["required"]), stringSet(to["required"])
+
+	var changes []Change
+	for _, name := range sortedKeys(toRequired) {
+		if !fromRequired[name] {
+			changes = append(changes, Change{
+				Path:     join(path, name),
+				Kind:     RequiredAdded,
+				Breaking: true,
+			})
+		}
+	}
+	for _, name := range sortedKeys(fromRequired) {
+		if !toRequired[name] {
+			changes = append(changes, Change{
+				Path: join(path, name),
+				Kind: RequiredRemoved,
+			})
+		}
+	}
+	return changes
+}
+
+// compareEnums compares the allowed values of two schemas. A schema without
+// enum allows any value.
+func compareEnums(path string, from, to map[string]interface{}) []Change {
+	fromEnum, fromOK := from["enum"].([]interface{})
+	toEnum, toOK := to["enum"].([]interface{})
+	if !fromOK && !toOK {
+		return nil
+	}
+
+	fromValues, toValues := valueSet(fromEnum), valueSet(toEnum)
+	if fromOK && toOK && equalSets(fromValues, toValues) {
+		return nil
+	}
+
+	narrowed := toOK && (!fromOK || !subset(fromValues, toValues))
+	change := Change{
+		Path:     path,
+		Kind:     EnumWidened,
+		Breaking: narrowed,
+	}
+	if narrowed {
+		change.Kind = EnumNarrowed
+	}
+	if fromOK {
+		change.From = fromEnum
+	}
+	if toOK {
+		change.To = toEnum
+	}
+	return []Change{change}
+}
+
+// fieldRef returns the name and version of the library field the schema is
+// defined by, e.g. "name@1.0.0", or an empty string.
+func fieldRef(schema map[string]interface{}) string {
+	metadata, _ := schema["metadata"].(map[string]interface{})
+	field, _ := metadata["field"].(map[string]interface{})
+	name, _ := field["name"].(string)
+	if name == "" {
+		return ""
+	}
+	if version, ok := field["version"]; ok {
+		return fmt.Sprintf("%s@%v", name, version)
+	}
+	return name
+}
+
+// types returns the types the schema allows.
+func types(schema map[string]interface{}) map[string]bool {
+	switch t := schema["type"].(type) {
+	case string:
+		return map[string]bool{t: true}
+	case []interface{}:
+		return valueSet(t)
+	}
+	return map[string]bool{}
+}
+
+// typesWidened reports whether every value of the old types is allowed by the
+// new types. Integers are numbers.
+func typesWidened(from, to map[string]bool) bool {
+	for t := range from {
+		if !to[t] && !(t == "integer" && to["number"]) {
+			return false
+		}
+	}
+	return true
+}
+
+func typeValue(types map[string]bool) interface{} {
+	keys := sortedKeys(types)
+	switch len(keys) {
+	case 0:
+		return nil
+	case 1:
+		return keys[0]
+	}
+	return keys
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func stringSet(value interface{}) map[string]bool {
+	values, _ := value.([]interface{})
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+// valueSet returns the set of the values, compared by their string form.
+func valueSet(values []interface{}) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[fmt.Sprint(v)] = true
+	}
+	return set
+}
+
+func subset(a, b map[string]bool) bool {
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalSets(a, b map[string]bool) bool {
+	return len(a) == len(b) && subset(a, b)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toMap converts a BSON document, as parsed or as read from MongoDB, to a
+// map with []interface{} arrays.
+func toMap(d bson.D) map[string]interface{} {
+	m := make(map[string]interface{}, len(d))
+	for _, e := range d {
+		m[e.Key] = normalize(e.Value)
+	}
+	return m
+}
+
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.D:
+		return toMap(v)
+	case bson.M:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = normalize(value)
+		}
+		return m
+	case primitive.A:
+		return normalize([]interface{}(v))
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, value := range v {
+			values[i] = normalize(value)
+		}
+		return values
+	case []string:
+		values := make([]interface{}, len(v))
+		for i, value := range v {
+			values[i] = value
+		}
+		return values
+	}
+	return value
+}
@@ -0,0 +1,155 @@
+package schemadiff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemadiff"
+)
+
+func nameField(version string) bson.D {
+	return bson.D{
+		{Key: "title", Value: "Name"},
+		{Key: "type", Value: "string"},
+		{Key: "metadata", Value: bson.D{
+			{Key: "field", Value: bson.D{
+				{Key: "name", Value: "name"},
+				{Key: "version", Value: version},
+			}},
+		}},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := bson.D{
+		{Key: "type", Value: "object"},
+		{Key: "properties", Value: bson.D{
+			{Key: "name", Value: nameField("1.0.0")},
+			{Key: "status", Value: bson.D{
+				{Key: "type", Value: "string"},
+				{Key: "enum", Value: primitive.A{"active", "inactive"}},
+			}},
+			{Key: "tags", Value: bson.D{
+				{Key: "type", Value: "array"},
+				{Key: "items", Value: bson.D{{Key: "type", Value: "string"}}},
+			}},
+			{Key: "count", Value: bson.D{{Key: "type", Value: "integer"}}},
+		}},
+		{Key: "required", Value: primitive.A{"name"}},
+	}
+
+	tests := []struct {
+		name        string
+		to          bson.D
+		expBreaking bool
+		expChanges  []schemadiff.Change
+	}{
+		{
+			name:       "Unchanged",
+			to:         base,
+			expChanges: []schemadiff.Change{},
+		},
+		{
+			name: "Non-breaking changes",
+			to: bson.D{
+				{Key: "type", Value: "object"},
+				{Key: "properties", Value: bson.D{
+					{Key: "name", Value: nameField("1.1.0")},
+					{Key: "status", Value: bson.D{
+						{Key: "type", Value: "string"},
+						{Key: "enum", Value: primitive.A{"active", "inactive", "closed"}},
+					}},
+					{Key: "tags", Value: bson.D{
+						{Key: "type", Value: "array"},
+						{Key: "items", Value: bson.D{{Key: "type", Value: "string"}}},
+					}},
+					{Key: "count", Value: bson.D{{Key: "type", Value: "number"}}},
+					{Key: "url", Value: bson.D{{Key: "type", Value: "string"}}},
+				}},
+			},
+			expChanges: []schemadiff.Change{
+				{Path: "name", Kind: schemadiff.RequiredRemoved},
+				{Path: "count", Kind: schemadiff.TypeChanged, From: "integer", To: "number"},
+				{Path: "name", Kind: schemadiff.RefChanged, From: "name@1.0.0", To: "name@1.1.0"},
+				{
+					Path: "status",
+					Kind: schemadiff.EnumWidened,
+					From: []interface{}{"active", "inactive"},
+					To:   []interface{}{"active", "inactive", "closed"},
+				},
+				{Path: "url", Kind: schemadiff.PropertyAdded},
+			},
+		},
+		{
+			name: "Breaking changes",
+			to: bson.D{
+				{Key: "type", Value: "object"},
+				{Key: "properties", Value: bson.D{
+					{Key: "name", Value: nameField("1.0.0")},
+					{Key: "status", Value: bson.D{
+						{Key: "type", Value: "string"},
+						{Key: "enum", Value: primitive.A{"active"}},
+					}},
+					{Key: "tags", Value: bson.D{
+						{Key: "type", Value: "array"},
+						{Key: "items", Value: bson.D{
+							{Key: "type", Value: "string"},
+							{Key: "pattern", Value: "^[a-z]+$"},
+						}},
+					}},
+					{Key: "count", Value: bson.D{{Key: "type", Value: "string"}}},
+				}},
+				{Key: "required", Value: primitive.A{"name", "status"}},
+			},
+			expBreaking: true,
+			expChanges: []schemadiff.Change{
+				{Path: "status", Kind: schemadiff.RequiredAdded, Breaking: true},
+				{Path: "count", Kind: schemadiff.TypeChanged, Breaking: true, From: "integer", To: "string"},
+				{
+					Path:     "status",
+					Kind:     schemadiff.EnumNarrowed,
+					Breaking: true,
+					From:     []interface{}{"active", "inactive"},
+					To:       []interface{}{"active"},
+				},
+				{Path: "tags[]", Kind: schemadiff.PatternChanged, Breaking: true, From: "", To: "^[a-z]+$"},
+			},
+		},
+		{
+			name: "Property removed from a closed object",
+			to: bson.D{
+				{Key: "type", Value: "object"},
+				{Key: "additionalProperties", Value: false},
+				{Key: "properties", Value: bson.D{
+					{Key: "name", Value: nameField("1.0.0")},
+					{Key: "status", Value: bson.D{
+						{Key: "type", Value: "string"},
+						{Key: "enum", Value: primitive.A{"inactive", "active"}},
+					}},
+					{Key: "tags", Value: bson.D{
+						{Key: "type", Value: "array"},
+						{Key: "items", Value: bson.D{{Key: "type", Value: "string"}}},
+					}},
+				}},
+				{Key: "required", Value: primitive.A{"name"}},
+			},
+			expBreaking: true,
+			expChanges: []schemadiff.Change{
+				{Path: "count", Kind: schemadiff.PropertyRemoved, Breaking: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := schemadiff.Compare("test_schema-v1.0.0", base, "test_schema-v1.1.0", tt.to)
+			require.Equal(t, "test_schema-v1.0.0", report.From)
+			require.Equal(t, "test_schema-v1.1.0", report.To)
+			require.Equal(t, tt.expBreaking, report.Breaking)
+			require.Equal(t, tt.expChanges, report.Changes)
+		})
+	}
+}
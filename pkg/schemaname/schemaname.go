@@ -0,0 +1,233 @@
+// Package schemaname parses schema names of the form <name>-v<semver>, e.g.
+// organizations_schema-v1.0.0, and major version ranges of the form
+// <name>-v<major>, e.g. organizations_schema-v1.
+package schemaname
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Name is a parsed schema name.
+type Name struct {
+	// Base is the name without the version, e.g. "organizations_schema".
+	Base string
+	// Major, Minor and Patch are the numbers of the version.
+	Major, Minor, Patch int
+	// Prerelease is the pre-release part of the version, e.g. "beta.1", or
+	// empty for releases.
+	Prerelease string
+	// IsRange is true if the name only gives the major version, meaning any
+	// version compatible with it.
+	IsRange bool
+}
+
+// Parse parses a schema name. It returns false if the name doesn't end with
+// a version.
+func Parse(name string) (Name, bool) {
+	for i := strings.Index(name, "-v"); i > 0; {
+		if n, ok := parseVersion(name[i+2:]); ok {
+			n.Base = name[:i]
+			return n, true
+		}
+		next := strings.Index(name[i+2:], "-v")
+		if next < 0 {
+			break
+		}
+		i += 2 + next
+	}
+	return Name{}, false
+}
+
+// parseVersion parses "1", "1.2.3" or "1.2.3-beta.1".
+func parseVersion(version string) (Name, bool) {
+	var n Name
+	core, prerelease, hasPrerelease := strings.Cut(version, "-")
+	if hasPrerelease {
+		if !validPrerelease(prerelease) {
+			return Name{}, false
+		}
+		n.Prerelease = prerelease
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) == 1 && !hasPrerelease {
+		n.IsRange = true
+	} else if len(parts) != 3 {
+		return Name{}, false
+	}
+	numbers := []*int{&n.Major, &n.Minor, &n.Patch}
+	for i, part := range parts {
+		number, ok := parseNumber(part)
+		// Leading zeros aren't allowed, so that names parse to themselves.
+		if !ok || len(part) > 1 && part[0] == '0' {
+			return Name{}, false
+		}
+		*numbers[i] = number
+	}
+	return n, true
+}
+
+// parseNumber parses a non-negative decimal number.
+func parseNumber(s string) (int, bool) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, false
+	}
+	number, err := strconv.Atoi(s)
+	return number, err == nil
+}
+
+// validPrerelease reports whether s is made of dot-separated, non-empty
+// alphanumeric identifiers.
+func validPrerelease(s string) bool {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' ||
+				r >= 'A' && r <= 'Z' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Version returns the version part of the name, e.g. "1.0.0" or "1" for a
+// range.
+func (n Name) Version() string {
+	if n.IsRange {
+		return strconv.Itoa(n.Major)
+	}
+	version := strconv.Itoa(n.Major) + "." + strconv.Itoa(n.Minor) + "." +
+		strconv.Itoa(n.Patch)
+	if n.Prerelease != "" {
+		version += "-" + n.Prerelease
+	}
+	return version
+}
+
+// String returns the schema name.
+func (n Name) String() string {
+	return n.Base + "-v" + n.Version()
+}
+
+// Compare returns -1, 0 or 1 if the version of n precedes, equals or follows
+// the version of o, following semantic versioning precedence.
+func (n Name) Compare(o Name) int {
+	for _, d := range []int{n.Major - o.Major, n.Minor - o.Minor, n.Patch - o.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	return comparePrerelease(n.Prerelease, o.Prerelease)
+}
+
+// comparePrerelease compares pre-release versions. A release follows all
+// of its pre-releases.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aNumeric := parseNumber(as[i])
+		bn, bNumeric := parseNumber(bs[i])
+		switch {
+		case aNumeric && bNumeric:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aNumeric:
+			return -1
+		case bNumeric:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+func sign(d int) int {
+	switch {
+	case d < 0:
+		return -1
+	case d > 0:
+		return 1
+	}
+	return 0
+}
+
+// Matches reports whether the name is one of the versions the reference
+// designates. The reference is either a base name, which designates all its
+// versions, or a major version range.
+func (n Name) Matches(ref string) bool {
+	if n.IsRange {
+		return false
+	}
+	if n.Base == ref {
+		return true
+	}
+	r, ok := Parse(ref)
+	return ok && r.IsRange && r.Base == n.Base && r.Major == n.Major
+}
+
+// Versions returns the versions among the schema names that the reference
+// designates, newest first. The reference is either a base name or a major
+// version range.
+func Versions(names []string, ref string) []Name {
+	var versions []Name
+	for _, name := range names {
+		if n, ok := Parse(name); ok && n.Matches(ref) {
+			versions = append(versions, n)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Compare(versions[j]) > 0
+	})
+	return versions
+}
+
+// Latest returns the newest version among the schema names that the
+// reference designates. Pre-releases are only returned if there is no
+// release. It returns false if the reference designates none of the names.
+func Latest(names []string, ref string) (Name, bool) {
+	versions := Versions(names, ref)
+	if len(versions) == 0 {
+		return Name{}, false
+	}
+	for _, version := range versions {
+		if version.Prerelease == "" {
+			return version, true
+		}
+	}
+	return versions[0], true
+}
+
+// IsRange reports whether the name is a major version range, e.g.
+// organizations_schema-v1.
+func IsRange(name string) bool {
+	n, ok := Parse(name)
+	return ok && n.IsRange
+}
+
+// Refs returns the names a profile may link the named schema by: the name
+// itself and, for a version, its major version range.
+func Refs(name string) []string {
+	n, ok := Parse(name)
+	if !ok || n.IsRange {
+		return []string{name}
+	}
+	return []string{name, Name{Base: n.Base, Major: n.Major, IsRange: true}.String()}
+}
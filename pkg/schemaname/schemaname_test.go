@@ -0,0 +1,169 @@
+package schemaname_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaname"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected schemaname.Name
+		expOK    bool
+	}{
+		{
+			name:     "Version",
+			input:    "organizations_schema-v1.2.3",
+			expected: schemaname.Name{Base: "organizations_schema", Major: 1, Minor: 2, Patch: 3},
+			expOK:    true,
+		},
+		{
+			name:     "Major range",
+			input:    "organizations_schema-v1",
+			expected: schemaname.Name{Base: "organizations_schema", Major: 1, IsRange: true},
+			expOK:    true,
+		},
+		{
+			name:  "Pre-release",
+			input: "test_schema-v2.0.0-beta.1",
+			expected: schemaname.Name{
+				Base:       "test_schema",
+				Major:      2,
+				Prerelease: "beta.1",
+			},
+			expOK: true,
+		},
+		{
+			name:     "Base containing -v",
+			input:    "my-vocab-v1.0.0",
+			expected: schemaname.Name{Base: "my-vocab", Major: 1},
+			expOK:    true,
+		},
+		{name: "No version", input: "organizations_schema", expOK: false},
+		{name: "Minor range", input: "organizations_schema-v1.2", expOK: false},
+		{name: "Invalid version", input: "organizations_schema-vX", expOK: false},
+		{name: "Leading zero", input: "organizations_schema-v1.02.0", expOK: false},
+		{name: "No base", input: "-v1.0.0", expOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, ok := schemaname.Parse(tt.input)
+			require.Equal(t, tt.expOK, ok)
+			require.Equal(t, tt.expected, name)
+			if ok {
+				require.Equal(t, tt.input, name.String())
+			}
+		})
+	}
+}
+
+func TestVersions(t *testing.T) {
+	names := []string{
+		"organizations_schema-v1.0.0",
+		"organizations_schema-v1.10.0",
+		"organizations_schema-v1.2.0",
+		"organizations_schema-v2.0.0-beta",
+		"organizations_schema-v10.0.0",
+		"organizations_schema-v1.10.0-rc.1",
+		"people_schema-v1.0.0",
+		"unversioned",
+	}
+
+	tests := []struct {
+		name        string
+		ref         string
+		expVersions []string
+		expLatest   string
+	}{
+		{
+			name: "Base name",
+			ref:  "organizations_schema",
+			expVersions: []string{
+				"organizations_schema-v10.0.0",
+				"organizations_schema-v2.0.0-beta",
+				"organizations_schema-v1.10.0",
+				"organizations_schema-v1.10.0-rc.1",
+				"organizations_schema-v1.2.0",
+				"organizations_schema-v1.0.0",
+			},
+			expLatest: "organizations_schema-v10.0.0",
+		},
+		{
+			name: "Major range",
+			ref:  "organizations_schema-v1",
+			expVersions: []string{
+				"organizations_schema-v1.10.0",
+				"organizations_schema-v1.10.0-rc.1",
+				"organizations_schema-v1.2.0",
+				"organizations_schema-v1.0.0",
+			},
+			expLatest: "organizations_schema-v1.10.0",
+		},
+		{
+			name:        "Only pre-releases",
+			ref:         "organizations_schema-v2",
+			expVersions: []string{"organizations_schema-v2.0.0-beta"},
+			expLatest:   "organizations_schema-v2.0.0-beta",
+		},
+		{
+			name: "Unknown",
+			ref:  "organizations_schema-v3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var versions []string
+			for _, version := range schemaname.Versions(names, tt.ref) {
+				versions = append(versions, version.String())
+			}
+			require.Equal(t, tt.expVersions, versions)
+
+			latest, ok := schemaname.Latest(names, tt.ref)
+			require.Equal(t, tt.expLatest != "", ok)
+			if ok {
+				require.Equal(t, tt.expLatest, latest.String())
+			}
+		})
+	}
+}
+
+func TestRefs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "Version",
+			input:    "organizations_schema-v1.2.0",
+			expected: []string{"organizations_schema-v1.2.0", "organizations_schema-v1"},
+		},
+		{
+			name:     "Pre-release",
+			input:    "test_schema-v2.0.0-beta.1",
+			expected: []string{"test_schema-v2.0.0-beta.1", "test_schema-v2"},
+		},
+		{
+			name:     "Major range",
+			input:    "organizations_schema-v1",
+			expected: []string{"organizations_schema-v1"},
+		},
+		{
+			name:     "Unversioned",
+			input:    "default-v",
+			expected: []string{"default-v"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, schemaname.Refs(tt.input))
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Package urlutil provides helpers for working with URLs that identify
+// resources, such as profile URLs.
+package urlutil
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// ErrInvalidURL is returned when a URL can't be canonicalized.
+var ErrInvalidURL = errors.New("invalid URL")
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Canonicalize returns the canonical form of an absolute http(s) URL, so
+// that URL variants pointing to the same resource compare equal:
+//
+//   - the scheme and host are lowercased and IDN hosts are converted to
+//     their ASCII (punycode) form
+//   - default ports and the trailing dot of the host are removed
+//   - repeated slashes and "." and ".." segments are removed from the path,
+//     and an empty path becomes "/"
+//   - percent-encodings are uppercased and unreserved characters decoded
+//   - an empty query ("?") and the fragment are removed
+func Canonicalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if _, ok := defaultPorts[scheme]; !ok {
+		return "", ErrInvalidURL
+	}
+
+	host, err := canonicalHost(scheme, u)
+	if err != nil {
+		return "", err
+	}
+
+	canonical := &url.URL{
+		Scheme:   scheme,
+		User:     u.User,
+		Host:     host,
+		RawQuery: normalizeEscapes(u.RawQuery),
+	}
+
+	escapedPath := canonicalPath(normalizeEscapes(u.EscapedPath()))
+	canonical.Path, err = url.PathUnescape(escapedPath)
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+	canonical.RawPath = escapedPath
+
+	return canonical.String(), nil
+}
+
+// LinkURL returns the URL to request for a link, such as a primary URL,
+// which profiles may publish without a scheme. HTTPS is assumed.
+func LinkURL(link string) string {
+	if strings.HasPrefix(link, "http://") ||
+		strings.HasPrefix(link, "https://") {
+		return link
+	}
+	return "https://" + link
+}
+
+// canonicalHost returns the lowercased ASCII host of u, including the port
+// unless it is the default port of the scheme.
+func canonicalHost(scheme string, u *url.URL) (string, error) {
+	hostname := strings.TrimSuffix(u.Hostname(), ".")
+	if hostname == "" {
+		return "", ErrInvalidURL
+	}
+
+	// IPv6 literals are kept as they are, apart from the case.
+	if strings.Contains(hostname, ":") {
+		hostname = "[" + strings.ToLower(hostname) + "]"
+	} else {
+		ascii, err := idna.Lookup.ToASCII(hostname)
+		if err != nil {
+			return "", ErrInvalidURL
+		}
+		hostname = strings.ToLower(ascii)
+	}
+
+	port := u.Port()
+	if port == "" || port == defaultPorts[scheme] {
+		return hostname, nil
+	}
+	return hostname + ":" + port, nil
+}
+
+// canonicalPath collapses repeated slashes and removes dot segments from an
+// escaped path, keeping a trailing slash.
+func canonicalPath(escapedPath string) string {
+	if escapedPath == "" {
+		return "/"
+	}
+
+	trailingSlash := strings.HasSuffix(escapedPath, "/") ||
+		strings.HasSuffix(escapedPath, "/.") ||
+		strings.HasSuffix(escapedPath, "/..")
+
+	cleaned := path.Clean("/" + escapedPath)
+	if trailingSlash && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// normalizeEscapes uppercases the hex digits of percent-encodings and decodes
+// the ones that encode unreserved characters (RFC 3986, section 6.2.2).
+func normalizeEscapes(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			b.WriteByte(s[i])
+			continue
+		}
+		c := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+		}
+		i += 2
+	}
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' ||
+		'0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~'
+}
@@ -0,0 +1,149 @@
+package urlutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/urlutil"
+)
+
+func TestCanonicalize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+		err      error
+	}{
+		{
+			name:     "Canonical URL",
+			input:    "https://example.org/p.json",
+			expected: "https://example.org/p.json",
+		},
+		{
+			name:     "Scheme and host case",
+			input:    "HTTPS://Example.ORG/P.json",
+			expected: "https://example.org/P.json",
+		},
+		{
+			name:     "Default port",
+			input:    "https://example.org:443/p.json",
+			expected: "https://example.org/p.json",
+		},
+		{
+			name:     "Default HTTP port",
+			input:    "http://example.org:80/p.json",
+			expected: "http://example.org/p.json",
+		},
+		{
+			name:     "Non-default port",
+			input:    "https://example.org:8443/p.json",
+			expected: "https://example.org:8443/p.json",
+		},
+		{
+			name:     "Repeated slashes",
+			input:    "https://example.org//profiles///p.json",
+			expected: "https://example.org/profiles/p.json",
+		},
+		{
+			name:     "Dot segments",
+			input:    "https://example.org/a/./b/../p.json",
+			expected: "https://example.org/a/p.json",
+		},
+		{
+			name:     "Trailing slash is kept",
+			input:    "https://example.org/profiles/",
+			expected: "https://example.org/profiles/",
+		},
+		{
+			name:     "Empty path",
+			input:    "https://example.org",
+			expected: "https://example.org/",
+		},
+		{
+			name:     "Empty query and fragment",
+			input:    "https://example.org//p.json?#",
+			expected: "https://example.org/p.json",
+		},
+		{
+			name:     "Fragment",
+			input:    "https://example.org/p.json#section",
+			expected: "https://example.org/p.json",
+		},
+		{
+			name:     "Query is kept",
+			input:    "https://example.org/p.json?id=1&lang=en",
+			expected: "https://example.org/p.json?id=1&lang=en",
+		},
+		{
+			name:     "Percent-encoding",
+			input:    "https://example.org/%7euser/a%2fb%20c.json",
+			expected: "https://example.org/~user/a%2Fb%20c.json",
+		},
+		{
+			name:     "IDN host",
+			input:    "https://Bücher.example/p.json",
+			expected: "https://xn--bcher-kva.example/p.json",
+		},
+		{
+			name:     "Trailing dot in host",
+			input:    "https://example.org./p.json",
+			expected: "https://example.org/p.json",
+		},
+		{
+			name:     "Surrounding whitespace",
+			input:    "  https://example.org/p.json ",
+			expected: "https://example.org/p.json",
+		},
+		{
+			name:     "IPv6 host",
+			input:    "http://[::1]:8080/p.json",
+			expected: "http://[::1]:8080/p.json",
+		},
+		{
+			name:  "Unsupported scheme",
+			input: "ftp://example.org/p.json",
+			err:   urlutil.ErrInvalidURL,
+		},
+		{
+			name:  "Relative URL",
+			input: "/p.json",
+			err:   urlutil.ErrInvalidURL,
+		},
+		{
+			name:  "Missing host",
+			input: "https:///p.json",
+			err:   urlutil.ErrInvalidURL,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			canonical, err := urlutil.Canonicalize(tc.input)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, canonical)
+		})
+	}
+}
+
+func TestLinkURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     string
+		expected string
+	}{
+		{name: "HTTPS", link: "https://a.org", expected: "https://a.org"},
+		{name: "HTTP", link: "http://a.org/x", expected: "http://a.org/x"},
+		{name: "No scheme", link: "a.org/x", expected: "https://a.org/x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, urlutil.LinkURL(tt.link))
+		})
+	}
+}
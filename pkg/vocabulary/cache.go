@@ -0,0 +1,54 @@
+package vocabulary
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache keeps the vocabularies fetched from the library in memory for a
+// while, so that they aren't fetched on every request. It is safe for
+// concurrent use.
+type Cache struct {
+	libraryURL string
+	ttl        time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	vocabulary *Vocabulary
+	fetchedAt  time.Time
+}
+
+// NewCache creates a new Cache fetching the vocabularies from the library at
+// libraryURL and keeping them for ttl, after which changes to them, e.g. a
+// newer version of a major version range, are picked up.
+func NewCache(libraryURL string, ttl time.Duration) *Cache {
+	return &Cache{
+		libraryURL: libraryURL,
+		ttl:        ttl,
+		entries:    make(map[string]cacheEntry),
+	}
+}
+
+// Get returns the named vocabulary, as Fetch does. Vocabularies that fail to
+// be fetched aren't cached, so they are fetched again next time.
+func (c *Cache) Get(name string) (*Vocabulary, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[name]
+	c.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < c.ttl {
+		return entry.vocabulary, nil
+	}
+
+	vocabulary, err := Fetch(c.libraryURL, name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[name] = cacheEntry{vocabulary: vocabulary, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return vocabulary, nil
+}
@@ -0,0 +1,50 @@
+package vocabulary
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	requests := 0
+	mockServer := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Path != "/v2/vocabularies/categories-v1" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, err := w.Write([]byte(`{"data": {"name": "categories-v1.0.0"}}`))
+			require.NoError(t, err)
+		}),
+	)
+	defer mockServer.Close()
+
+	cache := NewCache(mockServer.URL, time.Hour)
+	for i := 0; i < 2; i++ {
+		vocabulary, err := cache.Get("categories-v1")
+		require.NoError(t, err)
+		require.Equal(t, "categories-v1.0.0", vocabulary.Name)
+	}
+	require.Equal(t, 1, requests)
+
+	// Missing vocabularies aren't cached.
+	for i := 0; i < 2; i++ {
+		_, err := cache.Get("topics-v1")
+		require.ErrorIs(t, err, ErrVocabularyNotFound)
+	}
+	require.Equal(t, 3, requests)
+
+	// Expired vocabularies are fetched again.
+	expired := NewCache(mockServer.URL, 0)
+	for i := 0; i < 2; i++ {
+		_, err := expired.Get("categories-v1")
+		require.NoError(t, err)
+	}
+	require.Equal(t, 5, requests)
+}
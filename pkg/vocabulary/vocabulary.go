@@ -0,0 +1,90 @@
+// Package vocabulary reads the controlled vocabularies served by the library
+// and expands their terms to their narrower terms, so that filtering by a
+// term also matches the more specific ones.
+package vocabulary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
+)
+
+var ErrVocabularyNotFound = errors.New("vocabulary not found")
+
+// Vocabulary is a controlled vocabulary of the library.
+type Vocabulary struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Terms       []Term `json:"terms"`
+}
+
+// Term is a term of a vocabulary, along with the IDs of its broader and
+// narrower terms.
+type Term struct {
+	ID string `json:"id"`
+	// Labels maps language tags, e.g. "en", to the labels of the term.
+	Labels   map[string]string `json:"labels,omitempty"`
+	Broader  []string          `json:"broader,omitempty"`
+	Narrower []string          `json:"narrower,omitempty"`
+}
+
+// Fetch reads the named vocabulary, or the latest version of a major version
+// range, e.g. kvm_categories-v1, from the library at libraryURL.
+func Fetch(libraryURL string, name string) (*Vocabulary, error) {
+	vocabularyURL := strings.TrimSuffix(libraryURL, "/") +
+		"/v2/vocabularies/" + url.PathEscape(name)
+
+	// The vocabularies are served by the library, usually an internal
+	// service.
+	fetcher := httputil.NewFetcher(httputil.FetcherOptions{
+		AllowedHosts: httputil.Hostnames(vocabularyURL),
+	})
+	data, _, err := fetcher.FetchJSON(vocabularyURL)
+	if err != nil {
+		var statusErr httputil.StatusError
+		if errors.As(err, &statusErr) &&
+			statusErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", ErrVocabularyNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to get vocabulary %s: %w", name, err)
+	}
+
+	var resp struct {
+		Data Vocabulary `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode vocabulary %s: %w", name, err)
+	}
+	return &resp.Data, nil
+}
+
+// Expand returns the term along with its narrower terms at any depth, the
+// term first and then the narrower terms level by level. It returns false if
+// the term isn't in the vocabulary.
+func (v *Vocabulary) Expand(id string) ([]string, bool) {
+	narrower := make(map[string][]string, len(v.Terms))
+	for _, term := range v.Terms {
+		narrower[term.ID] = term.Narrower
+	}
+	if _, ok := narrower[id]; !ok {
+		return nil, false
+	}
+
+	expanded := []string{id}
+	seen := map[string]bool{id: true}
+	for i := 0; i < len(expanded); i++ {
+		for _, n := range narrower[expanded[i]] {
+			if !seen[n] {
+				seen[n] = true
+				expanded = append(expanded, n)
+			}
+		}
+	}
+	return expanded, true
+}
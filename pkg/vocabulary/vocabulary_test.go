@@ -0,0 +1,84 @@
+package vocabulary
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetch(t *testing.T) {
+	mockServer := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v2/vocabularies/categories-v1" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			response := `{"data": {
+				"name": "categories-v1.0.0",
+				"terms": [
+					{"id": "non-profit", "labels": {"en": "Non-profit"}, "narrower": ["cooperative"]},
+					{"id": "cooperative", "broader": ["non-profit"]}
+				]
+			}}`
+			_, err := w.Write([]byte(response))
+			require.NoError(t, err)
+		}),
+	)
+	defer mockServer.Close()
+
+	vocabulary, err := Fetch(mockServer.URL+"/", "categories-v1")
+	require.NoError(t, err)
+	require.Equal(t, "categories-v1.0.0", vocabulary.Name)
+	require.Len(t, vocabulary.Terms, 2)
+	require.Equal(t, "Non-profit", vocabulary.Terms[0].Labels["en"])
+
+	_, err = Fetch(mockServer.URL, "topics-v1")
+	require.ErrorIs(t, err, ErrVocabularyNotFound)
+}
+
+func TestExpand(t *testing.T) {
+	vocabulary := &Vocabulary{
+		Terms: []Term{
+			{ID: "organization", Narrower: []string{"non-profit", "commercial"}},
+			{ID: "non-profit", Narrower: []string{"cooperative", "charity"}},
+			{ID: "commercial", Narrower: []string{"cooperative"}},
+			{ID: "cooperative"},
+			{ID: "charity"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		term        string
+		expExpanded []string
+		expOK       bool
+	}{
+		{
+			name:        "Narrower terms at any depth",
+			term:        "organization",
+			expExpanded: []string{"organization", "non-profit", "commercial", "cooperative", "charity"},
+			expOK:       true,
+		},
+		{
+			name:        "Narrowest term",
+			term:        "charity",
+			expExpanded: []string{"charity"},
+			expOK:       true,
+		},
+		{
+			name: "Unknown term",
+			term: "event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expanded, ok := vocabulary.Expand(tt.term)
+			require.Equal(t, tt.expOK, ok)
+			require.Equal(t, tt.expExpanded, expanded)
+		})
+	}
+}
@@ -19,6 +19,7 @@ type BatchesHandler interface {
 	Import(c *gin.Context)
 	Edit(c *gin.Context)
 	Delete(c *gin.Context)
+	DeadLinks(c *gin.Context)
 }
 
 type batchesHandler struct {
@@ -375,6 +376,55 @@ func (handler *batchesHandler) Delete(c *gin.Context) {
 	c.JSON(http.StatusOK, res)
 }
 
+// DeadLinks reports the profiles of a batch whose primary URL is dead.
+func (handler *batchesHandler) DeadLinks(c *gin.Context) {
+	userID := c.Query("user_id")
+	if len(userID) != 25 {
+		errors := jsonapi.NewError(
+			[]string{"Invalid `user_id`"},
+			[]string{"The `user_id` is not valid."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+
+	batchID := c.Query("batch_id")
+	if len(batchID) != 25 {
+		errors := jsonapi.NewError(
+			[]string{"Invalid `batch_id`"},
+			[]string{"The `batch_id` is not valid."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+
+	deadLinks, err := handler.svc.DeadLinks(userID, batchID)
+	if err != nil {
+		errors := jsonapi.NewError(
+			[]string{"Get Dead Links Failed"},
+			[]string{
+				"Failed to get dead links of `batch_id`: " + batchID + " with error: " + err.Error(),
+			},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		meta := jsonapi.NewBatchMeta("", batchID)
+		res := jsonapi.Response(nil, errors, nil, meta)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+
+	meta := jsonapi.NewBatchMeta("", batchID)
+	res := jsonapi.Response(deadLinks, nil, nil, meta)
+	c.JSON(http.StatusOK, res)
+}
+
 func validateFile(c *gin.Context) (*multipart.FileHeader, []jsonapi.Error) {
 	// Get fields from the POST request
 	file, err := c.FormFile("file")
@@ -6,3 +6,14 @@ type Batch struct {
 	BatchID string   `json:"batch_id,omitempty" bson:"batch_id,omitempty"`
 	Schemas []string `json:"schemas,omitempty"  bson:"schemas,omitempty"`
 }
+
+// DeadLink is a profile of a batch whose primary URL the index reports dead.
+type DeadLink struct {
+	Cuid        string `json:"cuid"`
+	NodeID      string `json:"node_id"`
+	ProfileURL  string `json:"profile_url"`
+	PrimaryURL  string `json:"primary_url,omitempty"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	LastChecked int64  `json:"last_checked"`
+	Failures    int    `json:"failures"`
+}
@@ -39,6 +39,7 @@ type BatchService interface {
 		string,
 	) (int, []jsonapi.Error, error)
 	Delete(string, string) error
+	DeadLinks(string, string) ([]model.DeadLink, error)
 }
 
 type batchService struct {
@@ -592,6 +593,63 @@ func (s *batchService) Delete(userID string, batchID string) error {
 	return nil
 }
 
+// DeadLinks returns the posted profiles of the batch whose primary URL the
+// index reports dead.
+func (s *batchService) DeadLinks(
+	userID string,
+	batchID string,
+) ([]model.DeadLink, error) {
+	// Check if `batch_id` belongs to user
+	isValid, err := s.batchRepo.CheckUser(userID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if !isValid {
+		return nil, errors.New("batch_id doesn't belong to user")
+	}
+
+	profiles, err := s.batchRepo.GetProfilesByBatchID(batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLinks := make([]model.DeadLink, 0)
+	for _, profile := range profiles {
+		isPosted, _ := profile["is_posted"].(bool)
+		nodeID, _ := profile["node_id"].(string)
+		if !isPosted || nodeID == "" {
+			continue
+		}
+
+		node, err := importutil.GetIndex(
+			config.Values.Index.URL + "/v2/nodes/" + nodeID,
+		)
+		if err != nil {
+			return nil, errors.New(
+				"Get profile from MurmurationsServices Index failed: " + err.Error(),
+			)
+		}
+		if node == nil || node.PrimaryURLCheck == nil ||
+			node.PrimaryURLCheck.Status != "dead" {
+			continue
+		}
+
+		cuid, _ := profile["cuid"].(string)
+		primaryURL, _ := profile["primary_url"].(string)
+		deadLinks = append(deadLinks, model.DeadLink{
+			Cuid:        cuid,
+			NodeID:      nodeID,
+			ProfileURL:  node.ProfileURL,
+			PrimaryURL:  primaryURL,
+			StatusCode:  node.PrimaryURLCheck.StatusCode,
+			LastChecked: node.PrimaryURLCheck.LastChecked,
+			Failures:    node.PrimaryURLCheck.Failures,
+		})
+	}
+
+	return deadLinks, nil
+}
+
 // Convert csv to one-to-one map[string]string.
 func csvToMap(records [][]string) []map[string]string {
 	csvHeader := records[0]
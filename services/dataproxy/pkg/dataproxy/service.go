@@ -156,6 +156,7 @@ func (s *Service) registerRoutes() {
 		v1.POST("/batch/import", batchesHandler.Import)
 		v1.PUT("/batch/import", batchesHandler.Edit)
 		v1.DELETE("/batch/import", batchesHandler.Delete)
+		v1.GET("/batch/dead-links", batchesHandler.DeadLinks)
 	}
 }
 
@@ -24,6 +24,14 @@ type config struct {
 	Nats natsConf
 	// TTL configuration
 	TTL ttlConf
+	// Profile fetching configuration
+	Fetch fetchConf
+	// Recrawl configuration
+	Recrawl recrawlConf
+	// Primary URL liveness configuration
+	LinkCheck linkCheckConf
+	// Revalidation of nodes linked to updated schemas configuration
+	SchemaRevalidation schemaRevalidationConf
 	// FeatureToggles
 	FeatureToggles map[string]bool
 }
@@ -72,6 +80,12 @@ type mongoConf struct {
 type esConf struct {
 	// Elasticsearch service URL
 	URL string `env:"ELASTICSEARCH_URL,required"`
+	// Number of queued node updates that triggers a bulk request
+	BulkActions int `env:"ES_BULK_ACTIONS,required"`
+	// Maximum time node updates wait in the bulk queue
+	BulkFlushInterval time.Duration `env:"ES_BULK_FLUSH_INTERVAL,required"`
+	// Number of concurrent bulk requests
+	BulkWorkers int `env:"ES_BULK_WORKERS,required"`
 }
 
 // natsConf contains the configuration for the NATS service.
@@ -89,3 +103,32 @@ type ttlConf struct {
 	// Time To Live for deleted items.
 	DeletedTTL int64 `env:"DELETED_TTL,required"`
 }
+
+// fetchConf contains the configuration for fetching profiles.
+type fetchConf struct {
+	// Maximum size of a profile in bytes
+	MaxBodySize int64 `env:"FETCH_MAX_BODY_SIZE,required"`
+	// Maximum nesting depth of a profile
+	MaxJSONDepth int `env:"FETCH_MAX_JSON_DEPTH,required"`
+	// Hosts that may be fetched even though they resolve to private addresses
+	AllowedHosts []string `env:"FETCH_ALLOWED_HOSTS,required" envSeparator:","`
+}
+
+// recrawlConf contains the configuration for recrawled posted nodes.
+type recrawlConf struct {
+	// Consecutive failed recrawls after which a posted node is deleted
+	MaxFailures int `env:"RECRAWL_MAX_FAILURES,required"`
+}
+
+// linkCheckConf contains the configuration for primary URL liveness checks.
+type linkCheckConf struct {
+	// Consecutive failed checks after which a primary URL is reported dead
+	DeadAfter int `env:"LINK_CHECK_DEAD_AFTER,required"`
+}
+
+// schemaRevalidationConf contains the configuration for revalidating the nodes
+// linked to updated schemas.
+type schemaRevalidationConf struct {
+	// Number of nodes sent for validation per second
+	Rate int `env:"SCHEMA_REVALIDATION_RATE,required"`
+}
@@ -8,6 +8,7 @@ import (
 	natsio "github.com/nats-io/nats.go"
 	"go.uber.org/zap"
 
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
@@ -15,10 +16,13 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
 )
 
-// NodeHandler defines methods for handling validated and invalid node events.
+// NodeHandler defines methods for handling validated, invalid, gone and link
+// checked node events.
 type NodeHandler interface {
 	Validated() error
 	ValidationFailed() error
+	Gone() error
+	LinkChecked() error
 }
 
 // nodeHandler handles node-related events.
@@ -67,6 +71,42 @@ func (handler *nodeHandler) ValidationFailed() error {
 	return nil
 }
 
+// Gone sets up a listener for events of nodes whose profile was deleted by
+// their owner and processes them.
+func (handler *nodeHandler) Gone() error {
+	err := messaging.QueueSubscribe(
+		messaging.NodeGone,
+		index.QueueGroup,
+		handler.processGoneNode,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to subscribe to '%s': %v",
+			messaging.NodeGone,
+			err,
+		)
+	}
+	return nil
+}
+
+// LinkChecked sets up a listener for events of checked primary URLs and
+// processes them.
+func (handler *nodeHandler) LinkChecked() error {
+	err := messaging.QueueSubscribe(
+		messaging.NodeLinkChecked,
+		index.QueueGroup,
+		handler.processLinkChecked,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to subscribe to '%s': %v",
+			messaging.NodeLinkChecked,
+			err,
+		)
+	}
+	return nil
+}
+
 // processValidatedNode handles the processing of validated nodes.
 func (handler *nodeHandler) processValidatedNode(msg *natsio.Msg) {
 	defer safeAcknowledgeMessage(msg)
@@ -78,14 +118,49 @@ func (handler *nodeHandler) processValidatedNode(msg *natsio.Msg) {
 		return
 	}
 
-	if err = handler.svc.SetNodeValid(&model.Node{
-		ProfileURL:  data.ProfileURL,
-		ProfileHash: &data.ProfileHash,
-		ProfileStr:  data.ProfileStr,
-		LastUpdated: &data.LastUpdated,
-		Version:     &data.Version,
-		Expires:     data.Expires,
-	}); err != nil {
+	redirects := make([]model.Redirect, 0, len(data.Redirects))
+	for _, redirect := range data.Redirects {
+		redirects = append(redirects, model.Redirect{
+			URL:        redirect.URL,
+			StatusCode: redirect.StatusCode,
+		})
+	}
+
+	revisions := make([]model.SchemaRevision, 0, len(data.SchemaRevisions))
+	for _, revision := range data.SchemaRevisions {
+		revisions = append(revisions, model.SchemaRevision{
+			Name:     revision.Name,
+			Revision: revision.Revision,
+		})
+	}
+
+	// Warnings of an earlier validation are replaced, even with none.
+	warnings := data.Warnings
+	if warnings == nil {
+		warnings = []jsonapi.Error{}
+	}
+
+	node := &model.Node{
+		ProfileURL:      data.ProfileURL,
+		ProfileHash:     &data.ProfileHash,
+		ProfileStr:      data.ProfileStr,
+		LastUpdated:     &data.LastUpdated,
+		Version:         &data.Version,
+		Expires:         data.Expires,
+		Redirects:       &redirects,
+		Warnings:        &warnings,
+		SchemaRevisions: &revisions,
+		QualityFactors:  data.Quality,
+		PrimaryURL:      &data.PrimaryURL,
+	}
+	if data.FinalURL != "" {
+		node.FinalURL = &data.FinalURL
+	}
+	if data.Moved {
+		node.MovedTo = data.FinalURL
+	}
+
+	if err = handler.svc.SetNodeValid(node); err != nil {
 		logger.Error(
 			"Failed to set node valid",
 			err,
@@ -119,6 +194,150 @@ func (handler *nodeHandler) processInvalidNode(msg *natsio.Msg) {
 	}
 }
 
+// processGoneNode handles the processing of nodes whose profile URL
+// responded with 410 Gone.
+func (handler *nodeHandler) processGoneNode(msg *natsio.Msg) {
+	defer safeAcknowledgeMessage(msg)
+
+	var data messaging.NodeGoneData
+	err := json.Unmarshal(msg.Data, &data)
+	if err != nil {
+		logger.Error("Failed to unmarshal gone node data", err)
+		return
+	}
+
+	if err = handler.svc.SetNodeGone(&model.Node{
+		ProfileURL: data.ProfileURL,
+		Version:    &data.Version,
+	}); err != nil {
+		logger.Error(
+			"Failed to delete gone node",
+			err,
+			zap.String("ProfileURL", data.ProfileURL),
+		)
+	}
+}
+
+// processLinkChecked handles the processing of checked primary URLs.
+func (handler *nodeHandler) processLinkChecked(msg *natsio.Msg) {
+	defer safeAcknowledgeMessage(msg)
+
+	var data messaging.NodeLinkCheckedData
+	err := json.Unmarshal(msg.Data, &data)
+	if err != nil {
+		logger.Error("Failed to unmarshal link checked data", err)
+		return
+	}
+
+	if err = handler.svc.RecordPrimaryURLCheck(
+		data.NodeID,
+		data.PrimaryURL,
+		data.StatusCode,
+		data.Reachable,
+		data.CheckedAt,
+	); err != nil {
+		logger.Error(
+			"Failed to record primary URL check",
+			err,
+			zap.String("NodeID", data.NodeID),
+			zap.String("PrimaryURL", data.PrimaryURL),
+		)
+	}
+}
+
+// SchemaHandler defines methods for handling schema events.
+type SchemaHandler interface {
+	Updated() error
+	Resume()
+}
+
+// schemaHandler handles schema-related events.
+type schemaHandler struct {
+	svc service.RevalidationService
+}
+
+// NewSchemaHandler creates a new handler for schema-related events.
+func NewSchemaHandler(
+	revalidationService service.RevalidationService,
+) SchemaHandler {
+	return &schemaHandler{svc: revalidationService}
+}
+
+// Updated sets up a listener for updated schema events and revalidates the
+// nodes linked to the schemas.
+func (handler *schemaHandler) Updated() error {
+	err := messaging.QueueSubscribe(
+		messaging.SchemasUpdated,
+		index.QueueGroup,
+		handler.processUpdatedSchemas,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to subscribe to '%s': %v",
+			messaging.SchemasUpdated,
+			err,
+		)
+	}
+	return nil
+}
+
+// processUpdatedSchemas handles the processing of updated schemas. The
+// message is acknowledged once the run is recorded, as sending the linked
+// nodes for validation at a throttled rate outlasts the acknowledgement
+// deadline. A run interrupted by a restart is resumed by Resume.
+func (handler *schemaHandler) processUpdatedSchemas(msg *natsio.Msg) {
+	var data messaging.SchemasUpdatedData
+	err := json.Unmarshal(msg.Data, &data)
+	if err != nil {
+		logger.Error("Failed to unmarshal updated schemas data", err)
+		safeAcknowledgeMessage(msg)
+		return
+	}
+	if len(data.Schemas) == 0 {
+		safeAcknowledgeMessage(msg)
+		return
+	}
+
+	// The message is redelivered if the run can't be recorded.
+	revalidation, err := handler.svc.Start(data.Schemas, data.Version)
+	if err != nil {
+		logger.Error(
+			"Failed to start the revalidation of nodes linked to updated schemas",
+			err,
+			zap.Strings("Schemas", data.Schemas),
+		)
+		return
+	}
+	safeAcknowledgeMessage(msg)
+
+	go handler.run(func() error {
+		return handler.svc.Run(revalidation)
+	})
+}
+
+// Resume resumes the revalidation runs interrupted by a restart in the
+// background.
+func (handler *schemaHandler) Resume() {
+	go handler.run(handler.svc.Resume)
+}
+
+// run runs a revalidation, logging its error or panic.
+func (handler *schemaHandler) run(revalidate func() error) {
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Error(
+				"Panic occurred during revalidation",
+				errors.New("panic"),
+				zap.Any("error", err),
+			)
+		}
+	}()
+
+	if err := revalidate(); err != nil {
+		logger.Error("Failed to revalidate nodes linked to updated schemas", err)
+	}
+}
+
 // safeAcknowledgeMessage safely acknowledges a message and should be called with
 // defer. It recovers from any panics that occurred during message processing and
 // then acknowledges the message.
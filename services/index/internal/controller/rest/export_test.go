@@ -1,5 +1,7 @@
 package rest
 
 var (
-	IsValidURL = isValidURL
+	IsValidURL             = isValidURL
+	IsValidateRequest      = isValidateRequest
+	ValidateAgainstSchemas = validateAgainstSchemas
 )
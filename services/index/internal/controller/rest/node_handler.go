@@ -15,6 +15,8 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/core"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/dateutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/i18n"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilevalidator"
@@ -32,10 +34,14 @@ type NodeHandler interface {
 	AddSync(c *gin.Context)
 	// Get retrieves a specific node.
 	Get(c *gin.Context)
+	// Lookup retrieves a specific node by any variant of its profile URL.
+	Lookup(c *gin.Context)
 	// GetNodes retrieves multiple nodes.
 	GetNodes(c *gin.Context)
 	// Search finds nodes that match certain criteria.
 	Search(c *gin.Context)
+	// DeadLinks finds nodes whose primary URL is dead.
+	DeadLinks(c *gin.Context)
 	// Delete removes a node.
 	Delete(c *gin.Context)
 	// Validate validates a node.
@@ -68,10 +74,21 @@ var validationFields = []string{
 	"tags",
 	"tags_filter",
 	"tags_exact",
+	"tags_vocabulary",
 	"primary_url",
 	"page",
 	"page_size",
 	"expires",
+	"min_quality",
+	"rank_by_quality",
+	"primary_url_status",
+}
+
+// deadLinksFields are the query parameters of the dead links report.
+var deadLinksFields = []string{
+	"schema",
+	"page",
+	"page_size",
 }
 
 func (handler *nodeHandler) getNodeID(
@@ -175,7 +192,7 @@ func (handler *nodeHandler) AddSync(c *gin.Context) {
 
 		if nodeInfo.Status == constant.NodeStatus.ValidationFailed {
 			meta := jsonapi.NewMeta("", nodeInfo.ID, nodeInfo.ProfileURL)
-			errors := *nodeInfo.FailureReasons
+			errors := i18n.Localize(*nodeInfo.FailureReasons, requestLanguage(c))
 			res := jsonapi.Response(nil, errors, nil, meta)
 			c.JSON(errors[0].Status, res)
 			return
@@ -187,7 +204,7 @@ func (handler *nodeHandler) AddSync(c *gin.Context) {
 				ToGetNodeResponse(nodeInfo),
 				nil,
 				nil,
-				nil,
+				warningsMeta(nodeInfo, requestLanguage(c)),
 			)
 			c.JSON(http.StatusOK, res)
 			return
@@ -218,6 +235,46 @@ func (handler *nodeHandler) Get(c *gin.Context) {
 		return
 	}
 
+	respondWithNode(c, node)
+}
+
+func (handler *nodeHandler) Lookup(c *gin.Context) {
+	profileURL := c.Query("profile_url")
+	if profileURL == "" {
+		errors := jsonapi.NewError(
+			[]string{"Missing Query Parameter"},
+			[]string{"The `profile_url` query parameter is required."},
+			nil,
+			[]int{http.StatusBadRequest},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(errors[0].Status, res)
+		return
+	}
+
+	node, err := handler.svc.GetNodeByProfileURL(profileURL)
+	if err != nil {
+		var validationError index.ValidationError
+		if errors.As(err, &validationError) {
+			jsonErr := jsonapi.NewError(
+				[]string{"Invalid Profile URL"},
+				[]string{validationError.Reason},
+				nil,
+				[]int{http.StatusBadRequest},
+			)
+			res := jsonapi.Response(nil, jsonErr, nil, nil)
+			c.JSON(jsonErr[0].Status, res)
+			return
+		}
+		handleGetNodeErrors(c, err, nil)
+		return
+	}
+
+	respondWithNode(c, node)
+}
+
+// respondWithNode writes the node, or the reasons it failed, to the response.
+func respondWithNode(c *gin.Context, node *model.Node) {
 	if node.Status == constant.NodeStatus.PostFailed {
 		meta := jsonapi.NewMeta(
 			"The system will automatically re-post the node, please check back in a minute.",
@@ -231,18 +288,57 @@ func (handler *nodeHandler) Get(c *gin.Context) {
 
 	if node.Status == constant.NodeStatus.ValidationFailed {
 		meta := jsonapi.NewMeta("", node.ID, node.ProfileURL)
-		errors := *node.FailureReasons
+		errors := i18n.Localize(*node.FailureReasons, requestLanguage(c))
 		res := jsonapi.Response(nil, errors, nil, meta)
 		c.JSON(errors[0].Status, res)
 		return
 	}
 
-	res := jsonapi.Response(ToGetNodeResponse(node), nil, nil, nil)
+	res := jsonapi.Response(
+		ToGetNodeResponse(node),
+		nil,
+		nil,
+		warningsMeta(node, requestLanguage(c)),
+	)
 	c.JSON(http.StatusOK, res)
 }
 
+// warningsMeta returns the meta holding the warnings of a valid node in the
+// given language, or nil if it has none.
+func warningsMeta(node *model.Node, lang string) *jsonapi.Meta {
+	if node.Warnings == nil || node.Status == constant.NodeStatus.ValidationFailed {
+		return nil
+	}
+	return jsonapi.NewWarningsMeta("", i18n.Localize(*node.Warnings, lang))
+}
+
+// requestLanguage returns the language to render validation messages in,
+// according to the Accept-Language header of the request.
+func requestLanguage(c *gin.Context) string {
+	return i18n.MatchLanguage(c.GetHeader("Accept-Language"))
+}
+
 func (handler *nodeHandler) Search(c *gin.Context) {
-	errs := checkInputIsValid(c, validationFields, "GET")
+	handler.search(c, validationFields, nil)
+}
+
+// DeadLinks reports the nodes whose primary URL failed too many liveness
+// checks in a row, optionally only those linked to a schema.
+func (handler *nodeHandler) DeadLinks(c *gin.Context) {
+	handler.search(c, deadLinksFields, func(esQuery *es.Query) {
+		dead := model.PrimaryURLDead
+		esQuery.PrimaryURLStatus = &dead
+	})
+}
+
+// search responds with the nodes matching the query parameters, which must
+// be among fields. restrict, if not nil, narrows the query further.
+func (handler *nodeHandler) search(
+	c *gin.Context,
+	fields []string,
+	restrict func(esQuery *es.Query),
+) {
+	errs := checkInputIsValid(c, fields, "GET")
 	if errs != nil {
 		res := jsonapi.Response(nil, errs, nil, nil)
 		c.JSON(errs[0].Status, res)
@@ -261,6 +357,9 @@ func (handler *nodeHandler) Search(c *gin.Context) {
 		c.JSON(errs[0].Status, res)
 		return
 	}
+	if restrict != nil {
+		restrict(&esQuery)
+	}
 
 	if esQuery.Page*esQuery.PageSize > 10000 {
 		errMsgs := []string{"Max Results Exceeded"}
@@ -285,9 +384,12 @@ func (handler *nodeHandler) Search(c *gin.Context) {
 		logger.Error("Failed to search a node", err)
 
 		var databaseError index.DatabaseError
+		var validationError index.ValidationError
 		var jsonErr []jsonapi.Error
 
 		switch {
+		case errors.As(err, &validationError):
+			jsonErr = invalidQueryError(validationError)
 		case errors.As(err, &databaseError):
 			jsonErr = jsonapi.NewError(
 				[]string{databaseError.Message},
@@ -393,6 +495,19 @@ func (handler *nodeHandler) Validate(c *gin.Context) {
 		return
 	}
 
+	// The body is either the profile itself, or a request naming the profile
+	// and the schemas to validate it against.
+	var schemas []interface{}
+	if request, ok := node.(map[string]interface{}); ok && isValidateRequest(request) {
+		var errors []jsonapi.Error
+		node, schemas, errors = handler.resolveValidateRequest(request)
+		if errors != nil {
+			res := jsonapi.Response(nil, errors, nil, nil)
+			c.JSON(errors[0].Status, res)
+			return
+		}
+	}
+
 	jsonString, err := json.Marshal(node)
 	if err != nil {
 		errors := jsonapi.NewError(
@@ -406,27 +521,29 @@ func (handler *nodeHandler) Validate(c *gin.Context) {
 		return
 	}
 
-	linkedSchemas, ok := getLinkedSchemas(node)
-	if !ok {
-		errors := jsonapi.NewError(
-			[]string{"Missing Required Property"},
-			[]string{"The `linked_schemas` property is required."},
-			nil,
-			[]int{http.StatusBadRequest},
-		)
-		res := jsonapi.Response(nil, errors, nil, nil)
-		c.JSON(errors[0].Status, res)
-		return
-	}
+	if schemas == nil {
+		linkedSchemas, ok := getLinkedSchemas(node)
+		if !ok {
+			errors := jsonapi.NewError(
+				[]string{"Missing Required Property"},
+				[]string{"The `linked_schemas` property is required."},
+				nil,
+				[]int{http.StatusBadRequest},
+			)
+			res := jsonapi.Response(nil, errors, nil, nil)
+			c.JSON(errors[0].Status, res)
+			return
+		}
 
-	// Validate against the default schema.
-	linkedSchemas = append(linkedSchemas, "default-v2.1.0")
+		// Validate against the default schema and the schemas specified
+		// inside the profile data.
+		for _, linkedSchema := range linkedSchemas {
+			schemas = append(schemas, linkedSchema)
+		}
+		schemas = append(schemas, "default-v2.1.0")
+	}
 
-	// Validate against schemes specify inside the profile data.
-	validator, err := profilevalidator.NewBuilder().
-		WithURLSchemas(config.Values.Library.InternalURL, linkedSchemas).
-		WithStrProfile(string(jsonString)).
-		Build()
+	result, err := validateAgainstSchemas(string(jsonString), schemas)
 	if err != nil {
 		// Log the error for internal debugging and auditing.
 		logger.Error("Failed to build schema validator", err)
@@ -444,20 +561,21 @@ func (handler *nodeHandler) Validate(c *gin.Context) {
 		return
 	}
 
-	result := validator.Validate()
+	lang := requestLanguage(c)
+	warnings := i18n.Localize(result.Warnings(), lang)
 	if !result.Valid {
 		message := "Failed to validate against schemas: " + strings.Join(
 			result.ErrorMessages,
 			" ",
 		)
 		logger.Info(message)
-		errors := jsonapi.NewError(
-			result.ErrorMessages,
-			result.Details,
-			result.Sources,
-			result.ErrorStatus,
+		errors := i18n.Localize(result.Errors(), lang)
+		res := jsonapi.Response(
+			nil,
+			errors,
+			nil,
+			jsonapi.NewWarningsMeta("", warnings),
 		)
-		res := jsonapi.Response(nil, errors, nil, nil)
 		c.JSON(errors[0].Status, res)
 		return
 	}
@@ -494,12 +612,32 @@ func (handler *nodeHandler) Validate(c *gin.Context) {
 		}
 	}
 
-	meta := jsonapi.NewMeta(
+	meta := jsonapi.NewWarningsMeta(
 		"The submitted profile was validated successfully to its linked schemas.",
-		"",
-		"",
+		warnings,
 	)
-	res := jsonapi.Response(nil, nil, nil, meta)
+
+	// Show what the index would store, if asked to.
+	var data interface{}
+	if c.Query("preview") == "true" {
+		profile := model.NewProfile(string(jsonString))
+		if err := profile.Normalize(); err != nil {
+			errors := jsonapi.NewError(
+				[]string{"Cannot Preview Document"},
+				[]string{
+					"The profile could not be normalized for the index: " + err.Error(),
+				},
+				nil,
+				[]int{http.StatusBadRequest},
+			)
+			res := jsonapi.Response(nil, errors, nil, meta)
+			c.JSON(errors[0].Status, res)
+			return
+		}
+		data = ToPreviewResponse(profile)
+	}
+
+	res := jsonapi.Response(data, nil, nil, meta)
 	c.JSON(http.StatusOK, res)
 }
 
@@ -607,6 +745,13 @@ func (handler *nodeHandler) GetNodes(c *gin.Context) {
 
 	searchResult, err := handler.svc.GetNodes(&esQuery)
 	if err != nil {
+		var validationError index.ValidationError
+		if errors.As(err, &validationError) {
+			jsonErr := invalidQueryError(validationError)
+			res := jsonapi.Response(nil, jsonErr, nil, nil)
+			c.JSON(jsonErr[0].Status, res)
+			return
+		}
 		handleGetNodeErrors(c, err, nil)
 		return
 	}
@@ -637,6 +782,143 @@ func (handler *nodeHandler) GetNodes(c *gin.Context) {
 	c.JSON(http.StatusOK, res)
 }
 
+// isValidateRequest reports whether the body of a validate request names the
+// profile to validate, with "profile_url" or "profile", instead of being the
+// profile itself. Profiles have "linked_schemas".
+func isValidateRequest(body map[string]interface{}) bool {
+	if _, ok := body["linked_schemas"]; ok {
+		return false
+	}
+	_, hasURL := body["profile_url"]
+	_, hasProfile := body["profile"]
+	return hasURL || hasProfile
+}
+
+// resolveValidateRequest returns the profile named by a validate request,
+// fetching it from its "profile_url" if needed, and the "schemas" it should be
+// validated against, if any.
+func (handler *nodeHandler) resolveValidateRequest(
+	request map[string]interface{},
+) (interface{}, []interface{}, []jsonapi.Error) {
+	var schemas []interface{}
+	if value, ok := request["schemas"]; ok {
+		schemas, ok = value.([]interface{})
+		if !ok || len(schemas) == 0 || !isSchemaList(schemas) {
+			return nil, nil, jsonapi.NewError(
+				[]string{"Invalid Schemas"},
+				[]string{
+					"The `schemas` property must be a non-empty array of schema names or JSON schemas.",
+				},
+				[][]string{{"pointer", "/schemas"}},
+				[]int{http.StatusBadRequest},
+			)
+		}
+	}
+
+	if value, ok := request["profile"]; ok {
+		if _, ok := value.(map[string]interface{}); !ok {
+			return nil, nil, jsonapi.NewError(
+				[]string{"Invalid Profile"},
+				[]string{"The `profile` property must be a JSON object."},
+				[][]string{{"pointer", "/profile"}},
+				[]int{http.StatusBadRequest},
+			)
+		}
+		return value, schemas, nil
+	}
+
+	profileURL, ok := request["profile_url"].(string)
+	if !ok {
+		return nil, nil, jsonapi.NewError(
+			[]string{"Invalid Profile URL"},
+			[]string{"The `profile_url` is not a valid URL."},
+			[][]string{{"pointer", "/profile_url"}},
+			[]int{http.StatusBadRequest},
+		)
+	}
+	profileStr, err := handler.svc.FetchProfile(profileURL)
+	if err != nil {
+		var validationError index.ValidationError
+		if errors.As(err, &validationError) {
+			return nil, nil, jsonapi.NewError(
+				[]string{"Invalid Profile URL"},
+				[]string{validationError.Reason},
+				[][]string{{"pointer", "/profile_url"}},
+				[]int{http.StatusBadRequest},
+			)
+		}
+		logger.Info("Failed to fetch profile to validate: " + err.Error())
+		return nil, nil, httputil.FetchErrorToJSONAPI(err, profileURL)
+	}
+
+	var profile map[string]interface{}
+	if err := json.Unmarshal([]byte(profileStr), &profile); err != nil {
+		return nil, nil, httputil.FetchErrorToJSONAPI(
+			httputil.InvalidJSONError{Err: err},
+			profileURL,
+		)
+	}
+	return profile, schemas, nil
+}
+
+// isSchemaList reports whether every schema is either the name of a library
+// schema or a JSON schema.
+func isSchemaList(schemas []interface{}) bool {
+	for _, schema := range schemas {
+		switch schema.(type) {
+		case string, map[string]interface{}:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// validateAgainstSchemas validates the profile against the given schemas,
+// which are the names of library schemas or JSON schemas. JSON schemas are
+// named after their position in the list, e.g. "schemas[1]".
+func validateAgainstSchemas(
+	profileStr string,
+	schemas []interface{},
+) (*profilevalidator.ValidationResult, error) {
+	var names, jsonNames, jsonSchemas []string
+	for i, schema := range schemas {
+		if name, ok := schema.(string); ok {
+			names = append(names, name)
+			continue
+		}
+		schemaJSON, err := json.Marshal(schema)
+		if err != nil {
+			return nil, err
+		}
+		jsonNames = append(jsonNames, fmt.Sprintf("schemas[%d]", i))
+		jsonSchemas = append(jsonSchemas, string(schemaJSON))
+	}
+
+	result := profilevalidator.NewValidationResult()
+	if len(names) > 0 {
+		validator, err := profilevalidator.NewBuilder().
+			WithURLSchemas(config.Values.Library.InternalURL, names).
+			WithStrProfile(profileStr).
+			Build()
+		if err != nil {
+			return nil, err
+		}
+		result.Merge(validator.Validate())
+	}
+	if len(jsonSchemas) > 0 {
+		validator, err := profilevalidator.NewBuilder().
+			WithJSONSchemas(jsonNames, jsonSchemas).
+			WithStrProfile(profileStr).
+			Build()
+		if err != nil {
+			return nil, err
+		}
+		result.Merge(validator.Validate())
+	}
+	return result, nil
+}
+
 func getLinkedSchemas(data interface{}) ([]string, bool) {
 	json, ok := data.(map[string]interface{})
 	if !ok {
@@ -794,6 +1076,17 @@ func handleAddNodeErrors(c *gin.Context, err error) {
 	c.JSON(jsonErr[0].Status, res)
 }
 
+// invalidQueryError reports a query parameter that failed validation, e.g. a
+// tag that isn't a term of the vocabulary given.
+func invalidQueryError(validationError index.ValidationError) []jsonapi.Error {
+	return jsonapi.NewError(
+		[]string{"Invalid Query Parameter"},
+		[]string{validationError.Reason},
+		[][]string{{"parameter", validationError.Field}},
+		[]int{http.StatusBadRequest},
+	)
+}
+
 func handleGetNodeErrors(c *gin.Context, err error, nodeID *string) {
 	var notFoundError index.NotFoundError
 	var databaseError index.DatabaseError
@@ -0,0 +1,82 @@
+package rest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/controller/rest"
+)
+
+func TestIsValidateRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     map[string]interface{}
+		expected bool
+	}{
+		{
+			name: "profile",
+			body: map[string]interface{}{
+				"linked_schemas": []interface{}{"test_schema-v2.0.0"},
+				"name":           "Some Organization Name",
+			},
+			expected: false,
+		},
+		{
+			name: "profile with a profile_url",
+			body: map[string]interface{}{
+				"linked_schemas": []interface{}{"test_schema-v2.0.0"},
+				"profile_url":    "https://example.org/profile.json",
+			},
+			expected: false,
+		},
+		{
+			name:     "profile URL",
+			body:     map[string]interface{}{"profile_url": "https://example.org/profile.json"},
+			expected: true,
+		},
+		{
+			name: "inline profile with schemas",
+			body: map[string]interface{}{
+				"profile": map[string]interface{}{"name": "Some Organization Name"},
+				"schemas": []interface{}{"test_schema-v2.0.0"},
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, rest.IsValidateRequest(tt.body))
+		})
+	}
+}
+
+func TestValidateAgainstSchemas(t *testing.T) {
+	schemas := []interface{}{
+		map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"name"},
+		},
+		map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"age": map[string]interface{}{"type": "integer"}},
+		},
+	}
+
+	result, err := rest.ValidateAgainstSchemas(`{"name": "A", "age": 1}`, schemas)
+	require.NoError(t, err)
+	require.True(t, result.Valid)
+
+	result, err = rest.ValidateAgainstSchemas(`{"age": "one"}`, schemas)
+	require.NoError(t, err)
+	require.False(t, result.Valid)
+	require.Equal(
+		t,
+		[]string{
+			"The `name` property is required - Schema: schemas[0]",
+			"Expected: integer - Given: string - Schema: schemas[1]",
+		},
+		result.Details,
+	)
+}
@@ -11,12 +11,15 @@ import (
 
 // NodeCreateRequest is a structure representing the request to create a new node.
 type NodeCreateRequest struct {
-	ID             string           `json:"node_id"`
-	ProfileURL     string           `json:"profile_url"`
-	ProfileHash    *string          `json:"profile_hash"`
-	Status         string           `json:"status"`
-	LastUpdated    *int64           `json:"last_updated"`
-	FailureReasons *[]jsonapi.Error `json:"failure_reasons"`
+	ID              string                  `json:"node_id"`
+	ProfileURL      string                  `json:"profile_url"`
+	ProfileHash     *string                 `json:"profile_hash"`
+	Status          string                  `json:"status"`
+	LastUpdated     *int64                  `json:"last_updated"`
+	FailureReasons  *[]jsonapi.Error        `json:"failure_reasons"`
+	Quality         *int                    `json:"quality"`
+	PrimaryURLCheck *model.PrimaryURLCheck  `json:"primary_url_check"`
+	SchemaRevisions *[]model.SchemaRevision `json:"schema_revisions"`
 }
 
 // Validate is a method of NodeCreateRequest that validates the request fields.
@@ -61,11 +64,14 @@ func validHostname(host string) bool {
 // toDTO is a function that converts the model entity to a NodeCreateRequest DTO.
 func toDTO(node *model.Node) *NodeCreateRequest {
 	return &NodeCreateRequest{
-		ID:             node.ID,
-		ProfileURL:     node.ProfileURL,
-		ProfileHash:    node.ProfileHash,
-		Status:         node.Status,
-		LastUpdated:    node.LastUpdated,
-		FailureReasons: node.FailureReasons,
+		ID:              node.ID,
+		ProfileURL:      node.ProfileURL,
+		ProfileHash:     node.ProfileHash,
+		Status:          node.Status,
+		LastUpdated:     node.LastUpdated,
+		FailureReasons:  node.FailureReasons,
+		Quality:         node.Quality,
+		PrimaryURLCheck: node.PrimaryURLCheck,
+		SchemaRevisions: node.SchemaRevisions,
 	}
 }
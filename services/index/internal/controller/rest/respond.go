@@ -19,12 +19,15 @@ type AddNodeResponse struct {
 
 // GetNodeResponse struct is used to format the GetNode operation response.
 type GetNodeResponse struct {
-	ID             string    `json:"node_id,omitempty"`
-	ProfileURL     string    `json:"profile_url,omitempty"`
-	ProfileHash    *string   `json:"profile_hash,omitempty"`
-	Status         string    `json:"status,omitempty"`
-	LastUpdated    *int64    `json:"last_updated,omitempty"`
-	FailureReasons *[]string `json:"failure_reasons,omitempty"`
+	ID              string                  `json:"node_id,omitempty"`
+	ProfileURL      string                  `json:"profile_url,omitempty"`
+	ProfileHash     *string                 `json:"profile_hash,omitempty"`
+	Status          string                  `json:"status,omitempty"`
+	LastUpdated     *int64                  `json:"last_updated,omitempty"`
+	FailureReasons  *[]string               `json:"failure_reasons,omitempty"`
+	Quality         *int                    `json:"quality,omitempty"`
+	PrimaryURLCheck *model.PrimaryURLCheck  `json:"primary_url_check,omitempty"`
+	SchemaRevisions *[]model.SchemaRevision `json:"schema_revisions,omitempty"`
 }
 
 // SearchNodeResponse struct is used to format the SearchNode operation response.
@@ -33,6 +36,13 @@ type SearchNodeResponse struct {
 	LastUpdated *int64 `json:"last_updated,omitempty"`
 }
 
+// PreviewResponse struct is used to format the normalized document returned by
+// the Validate operation in preview mode.
+type PreviewResponse struct {
+	Document        map[string]interface{} `json:"document"`
+	Transformations []model.Transformation `json:"transformations"`
+}
+
 // ToAddNodeResponse converts the node model to AddNodeResponse format.
 func ToAddNodeResponse(node *model.Node) interface{} {
 	return AddNodeResponse{
@@ -61,6 +71,14 @@ func ToGetNodeResponse(node *model.Node) interface{} {
 	return res
 }
 
+// ToPreviewResponse converts a normalized profile to PreviewResponse format.
+func ToPreviewResponse(profile *model.Profile) interface{} {
+	return PreviewResponse{
+		Document:        profile.GetJSON(),
+		Transformations: profile.Transformations(),
+	}
+}
+
 // ToSearchNodeResponse converts the nodes model to SearchNodeResponse format.
 func ToSearchNodeResponse(nodes model.Nodes) interface{} {
 	data := make([]interface{}, len(nodes))
@@ -72,3 +90,42 @@ func ToSearchNodeResponse(nodes model.Nodes) interface{} {
 	}
 	return Respond{Data: data}
 }
+
+// RevalidationResponse struct is used to format a run revalidating the nodes
+// linked to updated schemas.
+type RevalidationResponse struct {
+	ID         string   `json:"revalidation_id"`
+	Schemas    []string `json:"schemas"`
+	Version    string   `json:"version,omitempty"`
+	StartedAt  int64    `json:"started_at"`
+	FinishedAt int64    `json:"finished_at,omitempty"`
+	Sent       int      `json:"sent"`
+	Passed     int      `json:"passed"`
+	Failed     int      `json:"failed"`
+	Errored    int      `json:"errored"`
+	// Pending is the number of sent nodes whose validation result is yet to
+	// arrive.
+	Pending int `json:"pending"`
+}
+
+// ToRevalidationResponse converts the revalidation model to
+// RevalidationResponse format.
+func ToRevalidationResponse(revalidation *model.Revalidation) RevalidationResponse {
+	pending := revalidation.Sent - revalidation.Passed - revalidation.Failed -
+		revalidation.Errored
+	if pending < 0 {
+		pending = 0
+	}
+	return RevalidationResponse{
+		ID:         revalidation.ID,
+		Schemas:    revalidation.Schemas,
+		Version:    revalidation.Version,
+		StartedAt:  revalidation.StartedAt,
+		FinishedAt: revalidation.FinishedAt,
+		Sent:       revalidation.Sent,
+		Passed:     revalidation.Passed,
+		Failed:     revalidation.Failed,
+		Errored:    revalidation.Errored,
+		Pending:    pending,
+	}
+}
@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
+)
+
+type RevalidationHandler interface {
+	// Get retrieves a run revalidating the nodes linked to updated schemas.
+	Get(c *gin.Context)
+	// List retrieves the most recent revalidation runs.
+	List(c *gin.Context)
+}
+
+type revalidationHandler struct {
+	svc service.RevalidationService
+}
+
+func NewRevalidationHandler(
+	revalidationService service.RevalidationService,
+) RevalidationHandler {
+	return &revalidationHandler{
+		svc: revalidationService,
+	}
+}
+
+func (handler *revalidationHandler) Get(c *gin.Context) {
+	revalidationID := c.Param("revalidationID")
+
+	revalidation, err := handler.svc.Get(revalidationID)
+	if err != nil {
+		var jsonErr []jsonapi.Error
+		if errors.As(err, &index.NotFoundError{}) {
+			jsonErr = jsonapi.NewError(
+				[]string{"Revalidation Not Found"},
+				[]string{fmt.Sprintf(
+					"Could not locate the following revalidation_id in the Index: %s",
+					revalidationID,
+				)},
+				nil,
+				[]int{http.StatusNotFound},
+			)
+		} else {
+			logger.Error("Failed to get a revalidation", err)
+			jsonErr = jsonapi.NewError(
+				[]string{"Database Error"},
+				[]string{"Error while trying to get a revalidation."},
+				nil,
+				[]int{http.StatusInternalServerError},
+			)
+		}
+		res := jsonapi.Response(nil, jsonErr, nil, nil)
+		c.JSON(jsonErr[0].Status, res)
+		return
+	}
+
+	res := jsonapi.Response(ToRevalidationResponse(revalidation), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+func (handler *revalidationHandler) List(c *gin.Context) {
+	revalidations, err := handler.svc.List()
+	if err != nil {
+		logger.Error("Failed to list revalidations", err)
+		jsonErr := jsonapi.NewError(
+			[]string{"Database Error"},
+			[]string{"Error while trying to list revalidations."},
+			nil,
+			[]int{http.StatusInternalServerError},
+		)
+		res := jsonapi.Response(nil, jsonErr, nil, nil)
+		c.JSON(jsonErr[0].Status, res)
+		return
+	}
+
+	data := make([]RevalidationResponse, 0, len(revalidations))
+	for _, revalidation := range revalidations {
+		data = append(data, ToRevalidationResponse(revalidation))
+	}
+	res := jsonapi.Response(data, nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
@@ -1,8 +1,11 @@
 package model
 
 import (
+	"time"
+
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/quality"
 )
 
 // Node represents a node stored in the index.
@@ -39,6 +42,100 @@ type Node struct {
 
 	// Expires stores the Unix timestamp when the node expires.
 	Expires *int64 `bson:"expires,omitempty"`
+
+	// AliasIDs stores the IDs computed from non-canonical variants of the
+	// profile URL, so nodes can still be found by them.
+	AliasIDs []string `bson:"alias_ids,omitempty"`
+
+	// FinalURL stores the URL the profile was last fetched from after
+	// following redirects. It is empty if no redirect was followed.
+	FinalURL *string `bson:"final_url,omitempty"`
+
+	// Redirects stores the redirects followed when the profile was last
+	// fetched.
+	Redirects *[]Redirect `bson:"redirects,omitempty"`
+
+	// PreviousProfileURLs stores the profile URLs the node was moved from
+	// because of permanent redirects, oldest first.
+	PreviousProfileURLs []string `bson:"previous_profile_urls,omitempty"`
+
+	// Warnings stores the issues found in the profile when it was last
+	// validated that don't make it invalid, e.g. deprecated properties.
+	Warnings *[]jsonapi.Error `bson:"warnings,omitempty"`
+
+	// SchemaRevisions stores the revisions of the schemas the profile was
+	// validated against when it was last validated.
+	SchemaRevisions *[]SchemaRevision `bson:"schema_revisions,omitempty"`
+
+	// FailedCrawls counts the consecutive recrawls of the posted node that
+	// found its profile missing or invalid.
+	FailedCrawls *int `bson:"failed_crawls,omitempty"`
+
+	// QualityFactors stores the criteria the quality of the profile was
+	// scored on when it was last validated.
+	QualityFactors *quality.Factors `bson:"quality_factors,omitempty"`
+
+	// Quality stores the quality score of the profile, from 0 to
+	// quality.MaxScore.
+	Quality *int `bson:"quality,omitempty"`
+
+	// PrimaryURL stores the primary URL of the profile as published, which
+	// is checked for liveness.
+	PrimaryURL *string `bson:"primary_url,omitempty"`
+
+	// PrimaryURLCheck stores the result of the latest checks of the primary
+	// URL.
+	PrimaryURLCheck *PrimaryURLCheck `bson:"primary_url_check,omitempty"`
+
+	// RevalidationID stores the ID of the revalidation run that sent the
+	// node for validation and awaits its result.
+	RevalidationID *string `bson:"revalidation_id,omitempty"`
+
+	// MovedTo is set on the in-memory node when its profile permanently
+	// redirects to another URL. It won't be stored in MongoDB.
+	MovedTo string `bson:"-"`
+}
+
+// Redirect represents a redirect response followed while fetching a profile.
+type Redirect struct {
+	URL        string `bson:"url"         json:"url"`
+	StatusCode int    `bson:"status_code" json:"status_code"`
+}
+
+// SchemaRevision represents the revision of a library schema a profile was
+// validated against.
+type SchemaRevision struct {
+	Name     string `bson:"name"     json:"name"`
+	Revision string `bson:"revision" json:"revision"`
+}
+
+// Liveness statuses of a node's primary URL.
+const (
+	// PrimaryURLUnchecked is the status of a primary URL not checked yet.
+	PrimaryURLUnchecked = "unchecked"
+	// PrimaryURLOK is the status of a primary URL that responded
+	// successfully when it was last checked.
+	PrimaryURLOK = "ok"
+	// PrimaryURLFailing is the status of a primary URL that failed the
+	// latest checks, but not enough of them in a row to be dead.
+	PrimaryURLFailing = "failing"
+	// PrimaryURLDead is the status of a primary URL that failed too many
+	// checks in a row.
+	PrimaryURLDead = "dead"
+)
+
+// PrimaryURLCheck represents the result of the latest liveness checks of a
+// node's primary URL.
+type PrimaryURLCheck struct {
+	// Status is the liveness status of the primary URL.
+	Status string `bson:"status"                json:"status"`
+	// StatusCode is the status code of the latest response, or 0 if there
+	// was none.
+	StatusCode int `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	// LastChecked is the Unix time of the latest check, or 0 if unchecked.
+	LastChecked int64 `bson:"last_checked"          json:"last_checked"`
+	// Failures counts the consecutive failed checks.
+	Failures int `bson:"failures"              json:"failures"`
 }
 
 func (n *Node) SetStatusValidated() {
@@ -57,7 +154,77 @@ func (n *Node) ResetFailureReasons() {
 	n.FailureReasons = &[]jsonapi.Error{}
 }
 
+// ResetFailedCrawls sets the number of consecutive failed recrawls to zero.
+func (n *Node) ResetFailedCrawls() {
+	failedCrawls := 0
+	n.FailedCrawls = &failedCrawls
+}
+
 // ClearLastUpdated sets the LastUpdated field to nil.
 func (n *Node) ClearLastUpdated() {
 	n.LastUpdated = nil
 }
+
+// ScoreQuality scores the quality of the profile at the given time, from its
+// quality factors and when it was last updated. It does nothing if the node
+// has no quality factors.
+func (n *Node) ScoreQuality(now time.Time) {
+	if n.QualityFactors == nil {
+		return
+	}
+	factors := *n.QualityFactors
+	if n.LastUpdated != nil {
+		factors.LastUpdated = *n.LastUpdated
+	}
+	score := factors.Score(now)
+	n.QualityFactors = &factors
+	n.Quality = &score
+}
+
+// KeepPrimaryURLCheck keeps the primary URL check of the stored node if the
+// primary URL didn't change. Otherwise the new primary URL is unchecked. It
+// does nothing if the node has no primary URL set.
+func (n *Node) KeepPrimaryURLCheck(stored *Node) {
+	if n.PrimaryURL == nil {
+		return
+	}
+	if stored != nil && stored.PrimaryURLCheck != nil &&
+		stored.PrimaryURL != nil && *stored.PrimaryURL == *n.PrimaryURL {
+		n.PrimaryURLCheck = stored.PrimaryURLCheck
+		return
+	}
+	n.PrimaryURLCheck = &PrimaryURLCheck{Status: PrimaryURLUnchecked}
+}
+
+// RecordPrimaryURLCheck records a check of the primary URL made at
+// checkedAt. The primary URL is dead once deadAfter checks in a row failed.
+// The quality factors are updated with whether it was reachable.
+func (n *Node) RecordPrimaryURLCheck(
+	statusCode int,
+	reachable bool,
+	checkedAt int64,
+	deadAfter int,
+) {
+	check := PrimaryURLCheck{
+		Status:      PrimaryURLOK,
+		StatusCode:  statusCode,
+		LastChecked: checkedAt,
+	}
+	if !reachable {
+		check.Failures = 1
+		if n.PrimaryURLCheck != nil {
+			check.Failures += n.PrimaryURLCheck.Failures
+		}
+		check.Status = PrimaryURLFailing
+		if check.Failures >= deadAfter {
+			check.Status = PrimaryURLDead
+		}
+	}
+	n.PrimaryURLCheck = &check
+
+	if n.QualityFactors != nil {
+		factors := *n.QualityFactors
+		factors.PrimaryURLReachable = reachable
+		n.QualityFactors = &factors
+	}
+}
@@ -2,11 +2,13 @@ package model_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/quality"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
 )
 
@@ -37,3 +39,95 @@ func TestNodeStatus(t *testing.T) {
 	node.ResetFailureReasons()
 	require.Empty(t, *node.FailureReasons)
 }
+
+func TestScoreQuality(t *testing.T) {
+	now := time.Now()
+
+	// Nodes validated before quality was scored keep no score.
+	node := &model.Node{}
+	node.ScoreQuality(now)
+	require.Nil(t, node.Quality)
+
+	lastUpdated := now.Unix()
+	node = &model.Node{
+		LastUpdated: &lastUpdated,
+		QualityFactors: &quality.Factors{
+			Recommended:         2,
+			Geolocation:         true,
+			PrimaryURLReachable: true,
+			Tags:                5,
+		},
+	}
+	node.ScoreQuality(now)
+	require.Equal(t, lastUpdated, node.QualityFactors.LastUpdated)
+	require.NotNil(t, node.Quality)
+	require.Equal(t, quality.MaxScore, *node.Quality)
+}
+
+func TestRecordPrimaryURLCheck(t *testing.T) {
+	node := &model.Node{QualityFactors: &quality.Factors{PrimaryURLReachable: true}}
+
+	checks := []struct {
+		reachable        bool
+		expectedStatus   string
+		expectedFailures int
+	}{
+		{reachable: false, expectedStatus: model.PrimaryURLFailing, expectedFailures: 1},
+		{reachable: false, expectedStatus: model.PrimaryURLFailing, expectedFailures: 2},
+		{reachable: false, expectedStatus: model.PrimaryURLDead, expectedFailures: 3},
+		{reachable: false, expectedStatus: model.PrimaryURLDead, expectedFailures: 4},
+		{reachable: true, expectedStatus: model.PrimaryURLOK, expectedFailures: 0},
+	}
+
+	for i, check := range checks {
+		node.RecordPrimaryURLCheck(0, check.reachable, int64(i+1), 3)
+		require.Equal(t, check.expectedStatus, node.PrimaryURLCheck.Status)
+		require.Equal(t, check.expectedFailures, node.PrimaryURLCheck.Failures)
+		require.Equal(t, int64(i+1), node.PrimaryURLCheck.LastChecked)
+		require.Equal(t, check.reachable, node.QualityFactors.PrimaryURLReachable)
+	}
+}
+
+func TestKeepPrimaryURLCheck(t *testing.T) {
+	primaryURL := "https://a.org"
+	otherURL := "https://b.org"
+	check := &model.PrimaryURLCheck{Status: model.PrimaryURLDead, LastChecked: 1, Failures: 3}
+	stored := &model.Node{PrimaryURL: &primaryURL, PrimaryURLCheck: check}
+
+	tests := []struct {
+		name       string
+		primaryURL *string
+		stored     *model.Node
+		expected   *model.PrimaryURLCheck
+	}{
+		{
+			name:       "Unchanged primary URL",
+			primaryURL: &primaryURL,
+			stored:     stored,
+			expected:   check,
+		},
+		{
+			name:       "Changed primary URL",
+			primaryURL: &otherURL,
+			stored:     stored,
+			expected:   &model.PrimaryURLCheck{Status: model.PrimaryURLUnchecked},
+		},
+		{
+			name:       "New node",
+			primaryURL: &primaryURL,
+			expected:   &model.PrimaryURLCheck{Status: model.PrimaryURLUnchecked},
+		},
+		{
+			name:   "No primary URL set",
+			stored: stored,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &model.Node{PrimaryURL: tt.primaryURL}
+			node.KeepPrimaryURLCheck(tt.stored)
+			require.Equal(t, tt.expected, node.PrimaryURLCheck)
+		})
+	}
+}
@@ -3,8 +3,10 @@ package model
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"go.uber.org/zap"
 
@@ -35,6 +37,14 @@ var AllowedFields = map[string]bool{
 	"expires":        true,
 }
 
+// Transformation describes a change made to the profile data when it is
+// normalized for the index.
+type Transformation struct {
+	// Field is a JSON pointer to the changed data, e.g. "/tags/5".
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
 // Profile represents the profile data for a node.
 type Profile struct {
 	// Original profile string.
@@ -42,6 +52,9 @@ type Profile struct {
 
 	// JSON representation of the profile.
 	json map[string]interface{}
+
+	// Changes made to the profile data by Normalize.
+	transformations []Transformation
 }
 
 // NewProfile initializes a new Profile based on a profile string.
@@ -66,6 +79,36 @@ func (p *Profile) GetJSON() map[string]interface{} {
 	return filteredJSON
 }
 
+// Transformations returns the changes made to the profile data by Normalize,
+// followed by the fields GetJSON leaves out.
+func (p *Profile) Transformations() []Transformation {
+	transformations := append([]Transformation{}, p.transformations...)
+
+	var dropped []string
+	for key := range p.json {
+		if !AllowedFields[key] {
+			dropped = append(dropped, key)
+		}
+	}
+	sort.Strings(dropped)
+	for _, key := range dropped {
+		transformations = append(transformations, Transformation{
+			Field:       "/" + key,
+			Description: fmt.Sprintf("%s not indexed", key),
+		})
+	}
+
+	return transformations
+}
+
+// record adds a transformation of the given field.
+func (p *Profile) record(field, format string, args ...interface{}) {
+	p.transformations = append(p.transformations, Transformation{
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
 // Update processes and updates the profile data.
 func (p *Profile) Update(
 	profileURL string,
@@ -74,6 +117,12 @@ func (p *Profile) Update(
 	p.json["profile_url"] = profileURL
 	p.json["last_updated"] = lastUpdated
 
+	return p.Normalize()
+}
+
+// Normalize converts the profile data into the form stored in the index. The
+// changes made can be listed with Transformations.
+func (p *Profile) Normalize() error {
 	if err := p.convertGeolocation(); err != nil {
 		return err
 	}
@@ -117,6 +166,7 @@ func (p *Profile) convertGeolocation() error {
 		}
 
 		p.json["geolocation"] = map[string]interface{}{"lat": lat, "lon": lon}
+		p.record("/geolocation", "geolocation '%s' converted to {lat, lon}", geoStr)
 	} else if existingGeo, ok := p.json["geolocation"].(map[string]interface{}); ok {
 		p.json["geolocation"] = existingGeo
 	} else {
@@ -129,6 +179,7 @@ func (p *Profile) convertGeolocation() error {
 		}
 		if len(geoLocation) > 0 {
 			p.json["geolocation"] = geoLocation
+			p.record("/geolocation", "latitude and longitude combined into geolocation")
 		}
 	}
 
@@ -140,6 +191,7 @@ func (p *Profile) normalizeCountryCode() error {
 	if p.json["country_iso_3166"] != nil {
 		p.json["country"] = p.json["country_iso_3166"]
 		delete(p.json, "country_iso_3166")
+		p.record("/country", "country_iso_3166 '%v' stored as country", p.json["country"])
 		return nil
 	}
 
@@ -155,8 +207,13 @@ func (p *Profile) normalizeCountryCode() error {
 	if err != nil {
 		if errors.Is(err, countries.ErrCountryCodeNotFound) {
 			logger.Info("Country code not found",
-				zap.String("country", p.json["country_name"].(string)),
-				zap.String("profile_url", p.json["profile_url"].(string)),
+				zap.Any("country", p.json["country_name"]),
+				zap.Any("profile_url", p.json["profile_url"]),
+			)
+			p.record(
+				"/country_name",
+				"country_name '%v' not recognized, no country set",
+				p.json["country_name"],
 			)
 			return nil
 		}
@@ -165,10 +222,11 @@ func (p *Profile) normalizeCountryCode() error {
 
 	p.json["country"] = countryCode
 	logger.Info("Country code matched",
-		zap.String("country", p.json["country_name"].(string)),
+		zap.Any("country", p.json["country_name"]),
 		zap.String("code", countryCode),
-		zap.String("profile_url", p.json["profile_url"].(string)),
+		zap.Any("profile_url", p.json["profile_url"]),
 	)
+	p.record("/country", "country_name '%v' → %s", p.json["country_name"], countryCode)
 
 	return nil
 }
@@ -185,9 +243,41 @@ func (p *Profile) filterTags() error {
 	if len(tags) != 0 {
 		p.json["tags"] = tags
 	}
+	p.recordTags(arraySize, stringLength)
 	return nil
 }
 
+// recordTags records how the original tags were filtered, the same way as
+// tagsfilter.Filter does.
+func (p *Profile) recordTags(arraySize, stringLength int) {
+	original, _ := jsonutil.ToJSON(p.str)["tags"].([]interface{})
+	kept := 0
+	for i, value := range original {
+		tag, ok := value.(string)
+		if !ok {
+			p.record(fmt.Sprintf("/tags/%d", i), "tag %d dropped, not a string", i)
+			continue
+		}
+		if kept >= arraySize {
+			p.record(
+				fmt.Sprintf("/tags/%d", i),
+				"tags beyond the first %d dropped",
+				arraySize,
+			)
+			return
+		}
+		if utf8.RuneCountInString(tag) > stringLength {
+			p.record(
+				fmt.Sprintf("/tags/%d", i),
+				"tag %d truncated to %d chars",
+				i,
+				stringLength,
+			)
+		}
+		kept++
+	}
+}
+
 // setDefaultStatus sets the default status of the profile.
 func (p *Profile) setDefaultStatus() {
 	p.json["status"] = constant.NodeStatus.Posted
@@ -234,3 +234,36 @@ func TestSetDefaultStatus(t *testing.T) {
 		profile.GetJSON(),
 	)
 }
+
+func TestTransformations(t *testing.T) {
+	config.Values.Server.TagsArraySize = "2"
+	config.Values.Server.TagsStringLength = "5"
+
+	profile := model.NewProfile(`{
+		"name": "John",
+		"geolocation": "40.7128,-74.0060",
+		"country_iso_3166": "US",
+		"tags": ["verylongtag", 1, "tag2", "tag3"],
+		"description": "Not indexed"
+	}`)
+	require.NoError(t, profile.Normalize())
+
+	require.Equal(t, map[string]interface{}{
+		"name":        "John",
+		"geolocation": map[string]interface{}{"lat": 40.7128, "lon": -74.006},
+		"country":     "US",
+		"tags":        []string{"veryl", "tag2"},
+		"status":      "posted",
+	}, profile.GetJSON())
+	require.Equal(t, []model.Transformation{
+		{
+			Field:       "/geolocation",
+			Description: "geolocation '40.7128,-74.0060' converted to {lat, lon}",
+		},
+		{Field: "/country", Description: "country_iso_3166 'US' stored as country"},
+		{Field: "/tags/0", Description: "tag 0 truncated to 5 chars"},
+		{Field: "/tags/1", Description: "tag 1 dropped, not a string"},
+		{Field: "/tags/3", Description: "tags beyond the first 2 dropped"},
+		{Field: "/description", Description: "description not indexed"},
+	}, profile.Transformations())
+}
@@ -0,0 +1,46 @@
+package model
+
+// Revalidation represents a run revalidating the posted nodes linked to
+// updated schemas.
+type Revalidation struct {
+	// ID is the unique identifier of the run.
+	ID string `bson:"_id"`
+
+	// Schemas lists the names of the updated schemas.
+	Schemas []string `bson:"schemas"`
+
+	// Version identifies the update of the schemas, e.g. a library commit.
+	Version string `bson:"version,omitempty"`
+
+	// StartedAt is the Unix time the run started.
+	StartedAt int64 `bson:"started_at"`
+
+	// FinishedAt is the Unix time all the nodes were sent for validation, or
+	// 0 while they are being sent.
+	FinishedAt int64 `bson:"finished_at"`
+
+	// SearchAfter is the sort of the last page of nodes sent, which a run
+	// resumed after a restart continues from.
+	SearchAfter []interface{} `bson:"search_after,omitempty"`
+
+	// Sent counts the nodes sent for validation.
+	Sent int `bson:"sent"`
+
+	// Passed counts the nodes that are still valid.
+	Passed int `bson:"passed"`
+
+	// Failed counts the nodes that are no longer valid.
+	Failed int `bson:"failed"`
+
+	// Errored counts the nodes that couldn't be validated because of a
+	// temporary error, e.g. their profile host being down.
+	Errored int `bson:"errored"`
+}
+
+// Counters of a revalidation run, named after their fields.
+const (
+	RevalidationSent    = "sent"
+	RevalidationPassed  = "passed"
+	RevalidationFailed  = "failed"
+	RevalidationErrored = "errored"
+)
@@ -16,6 +16,7 @@ type NodeRepository interface {
 	Search(q *Query) (*QueryResults, error)
 	DeleteByID(id string) error
 	SoftDelete(node *model.Node) error
+	UpdatePrimaryURLCheck(node *model.Node) error
 	Export(q *BlockQuery) (*BlockQueryResults, error)
 }
 
@@ -23,9 +24,22 @@ func NewNodeRepository() NodeRepository {
 	return &nodeRepository{}
 }
 
+// NewBulkNodeRepository returns a NodeRepository that queues index, delete
+// and soft delete operations on the given BulkProcessor instead of sending
+// them one by one. Failures are reported by the processor's OnFailure
+// callback, not by the returned errors.
+func NewBulkNodeRepository(processor elastic.BulkProcessor) NodeRepository {
+	return &bulkNodeRepository{processor: processor}
+}
+
 type nodeRepository struct {
 }
 
+type bulkNodeRepository struct {
+	nodeRepository
+	processor elastic.BulkProcessor
+}
+
 func (r *nodeRepository) IndexByID(id string, json interface{}) error {
 	_, err := elastic.Client.IndexWithID(
 		constant.ESIndex.Node,
@@ -124,6 +138,35 @@ func (r *nodeRepository) SoftDelete(node *model.Node) error {
 	return nil
 }
 
+// UpdatePrimaryURLCheck updates the primary URL check and the quality score
+// of the indexed node.
+func (r *nodeRepository) UpdatePrimaryURLCheck(node *model.Node) error {
+	err := elastic.Client.Update(
+		constant.ESIndex.Node,
+		node.ID,
+		primaryURLCheckFields(node),
+	)
+	if err != nil {
+		return index.DatabaseError{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// primaryURLCheckFields returns the fields of the node updated after its
+// primary URL was checked.
+func primaryURLCheckFields(node *model.Node) map[string]interface{} {
+	fields := map[string]interface{}{
+		"primary_url_status": node.PrimaryURLCheck.Status,
+		"primary_url_check":  node.PrimaryURLCheck,
+	}
+	if node.Quality != nil {
+		fields["quality"] = *node.Quality
+	}
+	return fields
+}
+
 func (r *nodeRepository) Export(q *BlockQuery) (*BlockQueryResults, error) {
 	result, err := elastic.Client.Export(
 		constant.ESIndex.Node,
@@ -159,3 +202,30 @@ func (r *nodeRepository) Export(q *BlockQuery) (*BlockQueryResults, error) {
 		Sort:   sort,
 	}, nil
 }
+
+func (r *bulkNodeRepository) IndexByID(id string, json interface{}) error {
+	r.processor.Index(constant.ESIndex.Node, id, json)
+	return nil
+}
+
+func (r *bulkNodeRepository) DeleteByID(id string) error {
+	r.processor.Delete(constant.ESIndex.Node, id)
+	return nil
+}
+
+func (r *bulkNodeRepository) SoftDelete(node *model.Node) error {
+	r.processor.Update(
+		constant.ESIndex.Node,
+		node.ID,
+		map[string]interface{}{
+			"status":       "deleted",
+			"last_updated": node.LastUpdated,
+		},
+	)
+	return nil
+}
+
+func (r *bulkNodeRepository) UpdatePrimaryURLCheck(node *model.Node) error {
+	r.processor.Update(constant.ESIndex.Node, node.ID, primaryURLCheckFields(node))
+	return nil
+}
@@ -3,6 +3,7 @@ package es
 import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/pagination"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaname"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
 )
 
@@ -41,6 +42,14 @@ type Query struct {
 	// TagsExact, if set to true, indicates that the "tags" field filter should perform
 	// an exact match.
 	TagsExact *string `form:"tags_exact"`
+	// TagsVocabulary, if set to the name of a vocabulary of the library,
+	// e.g. kvm_categories-v1, indicates that the comma-separated tags are
+	// terms of the vocabulary, each matching the profiles tagged with it or
+	// with any of its narrower terms.
+	TagsVocabulary *string `form:"tags_vocabulary"`
+	// TagsTerms holds, for each of the tags, the terms it was expanded to
+	// with TagsVocabulary.
+	TagsTerms [][]string `form:"-"`
 
 	// PrimaryURL is used to match profiles based on the "primary_url" field.
 	PrimaryURL *string `form:"primary_url"`
@@ -48,6 +57,17 @@ type Query struct {
 	// Expires is used to filter profiles based on the "expires" field.
 	Expires *int64 `form:"expires"`
 
+	// MinQuality is used to filter out profiles with a lower quality score.
+	MinQuality *int64 `form:"min_quality"`
+
+	// RankByQuality, if set to "true", weights the ranking of the profiles
+	// by their quality score.
+	RankByQuality *string `form:"rank_by_quality"`
+
+	// PrimaryURLStatus is used to match profiles based on the liveness
+	// status of their primary URL, e.g. "dead".
+	PrimaryURLStatus *string `form:"primary_url_status"`
+
 	// Page and PageSize are used to control the pagination of the search
 	// results.
 	Page     int64 `form:"page,default=0"`
@@ -58,7 +78,7 @@ func (q *Query) Build(isMap bool) *elastic.Query {
 	builder := &elastic.QueryBuilder{}
 
 	builder.BuildTextQuery("name", q.Name)
-	builder.BuildWildcardQuery("linked_schemas", q.Schema)
+	buildSchemaQuery(builder, q.Schema)
 	builder.BuildRangeQuery("last_updated", q.LastUpdated)
 	builder.BuildTextQuery("locality", q.Locality)
 	builder.BuildTextQuery("region", q.Region)
@@ -67,8 +87,12 @@ func (q *Query) Build(isMap bool) *elastic.Query {
 	builder.BuildMatchQuery("primary_url", q.PrimaryURL)
 	builder.BuildGeoQuery(q.Lat, q.Lon, q.Range)
 	builder.BuildRangeQueryLte("expires", q.Expires)
+	builder.BuildRangeFilter("quality", q.MinQuality)
+	builder.BuildMatchQuery("primary_url_status", q.PrimaryURLStatus)
 
-	if q.Tags != nil {
+	if q.TagsTerms != nil {
+		buildTagsTermsQuery(builder, q.TagsTerms, q.TagsFilter)
+	} else if q.Tags != nil {
 		tagQuery := elastic.NewMatchQuery("tags", *q.Tags)
 		if q.TagsFilter != nil && *q.TagsFilter == "and" {
 			tagQuery = tagQuery.Operator("AND")
@@ -88,19 +112,64 @@ func (q *Query) Build(isMap bool) *elastic.Query {
 		Must(builder.GetSubQueries()...).
 		Filter(builder.GetFilters()...)
 
+	esQuery := &elastic.Query{
+		Query: query,
+		From:  pagination.From(q.Page, q.PageSize),
+		Size:  pagination.Size(q.PageSize),
+	}
 	if isMap {
-		return &elastic.Query{
-			Query: query,
-			From:  pagination.From(q.Page, q.PageSize),
-			Size:  pagination.MaximumSize(q.PageSize),
+		esQuery.Size = pagination.MaximumSize(q.PageSize)
+	}
+	if q.RankByQuality != nil && *q.RankByQuality == "true" {
+		esQuery.Query = elastic.NewFieldValueScoreQuery(query, "quality")
+	}
+
+	return esQuery
+}
+
+// buildTagsTermsQuery matches the profiles tagged with any of the terms of
+// each of the tags, or of one of the tags if the filter isn't "and". The
+// terms match whole tags, regardless of case.
+func buildTagsTermsQuery(
+	builder *elastic.QueryBuilder,
+	tagsTerms [][]string,
+	filter *string,
+) {
+	tagQueries := elastic.NewQueries()
+	for _, terms := range tagsTerms {
+		tagQueries = append(tagQueries, elastic.NewTermsQuery("tags.keyword", terms...))
+	}
+
+	if filter != nil && *filter == "and" {
+		for _, tagQuery := range tagQueries {
+			builder.AddSubQuery(tagQuery)
 		}
+		return
 	}
+	builder.AddSubQuery(
+		elastic.NewBoolQuery().
+			Should(tagQueries...).
+			MinimumNumberShouldMatch(1),
+	)
+}
 
-	return &elastic.Query{
-		Query: query,
-		From:  pagination.From(q.Page, q.PageSize),
-		Size:  pagination.Size(q.PageSize),
+// buildSchemaQuery matches the profiles linked to the schema. A major version
+// range, e.g. organizations_schema-v1, matches the profiles linked to the
+// range itself or to any of its versions, but not to organizations_schema-v10.
+// Other values match the schema names starting with them.
+func buildSchemaQuery(builder *elastic.QueryBuilder, schema *string) {
+	if schema == nil || !schemaname.IsRange(*schema) {
+		builder.BuildWildcardQuery("linked_schemas", schema)
+		return
 	}
+	builder.AddSubQuery(
+		elastic.NewBoolQuery().
+			Should(
+				elastic.NewWildcardQuery("linked_schemas", *schema),
+				elastic.NewWildcardQuery("linked_schemas", *schema+".*"),
+			).
+			MinimumNumberShouldMatch(1),
+	)
 }
 
 type QueryResult map[string]interface{}
@@ -117,6 +186,13 @@ type BlockQuery struct {
 	// Schema is used to match blocks linked to a specific schema.
 	Schema *string `json:"schema,omitempty"`
 
+	// LinkedSchemas is used to match blocks linked to any of the exact
+	// schema names.
+	LinkedSchemas []string `json:"-"`
+
+	// Status is used to match blocks with a specific status.
+	Status *string `json:"-"`
+
 	// PageSize controls the number of results per page.
 	PageSize int64 `json:"page_size"`
 
@@ -128,7 +204,13 @@ type BlockQuery struct {
 func (q *BlockQuery) BuildBlock() *elastic.Query {
 	builder := &elastic.QueryBuilder{}
 
-	builder.BuildWildcardQuery("linked_schemas", q.Schema)
+	buildSchemaQuery(builder, q.Schema)
+	if len(q.LinkedSchemas) > 0 {
+		builder.AddSubQuery(
+			elastic.NewTermsQuery("linked_schemas", q.LinkedSchemas...),
+		)
+	}
+	builder.BuildMatchQuery("status", q.Status)
 
 	query := elastic.NewBoolQuery().Must(builder.GetSubQueries()...)
 
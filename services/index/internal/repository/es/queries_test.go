@@ -0,0 +1,54 @@
+package es
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTagsTerms(t *testing.T) {
+	and := "and"
+	tests := []struct {
+		name     string
+		filter   *string
+		expected string
+	}{
+		{
+			name:   "Any tag",
+			filter: nil,
+			expected: `{"bool": {"must": {"bool": {
+				"minimum_should_match": "1",
+				"should": [
+					{"terms": {"tags.keyword": ["non-profit", "cooperative"]}},
+					{"terms": {"tags.keyword": ["event"]}}
+				]
+			}}}}`,
+		},
+		{
+			name:   "All tags",
+			filter: &and,
+			expected: `{"bool": {"must": [
+				{"terms": {"tags.keyword": ["non-profit", "cooperative"]}},
+				{"terms": {"tags.keyword": ["event"]}}
+			]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := "non-profit,event"
+			q := &Query{
+				Tags:       &tags,
+				TagsFilter: tt.filter,
+				TagsTerms:  [][]string{{"non-profit", "cooperative"}, {"event"}},
+			}
+
+			source, err := q.Build(false).Query.Source()
+			require.NoError(t, err)
+			data, err := json.Marshal(source)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expected, string(data))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package mongo
 
 import (
+	"context"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -9,6 +10,7 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
 )
@@ -17,9 +19,15 @@ import (
 type NodeRepository interface {
 	Add(node *model.Node) error
 	GetByID(nodeID string) (*model.Node, error)
+	GetByAliasID(aliasID string) (*model.Node, error)
+	List(afterID string, limit int64) ([]*model.Node, error)
+	AddAliasIDs(nodeID string, aliasIDs []string) error
 	Update(node *model.Node) error
+	SetPrimaryURLCheck(node *model.Node) error
 	Delete(node *model.Node) error
 	SoftDelete(node *model.Node) error
+	SetRevalidationID(nodeID string, revalidationID string) (*model.Node, error)
+	ClearRevalidationID(nodeID string, revalidationID string) (bool, error)
 }
 
 // NewRepository function returns a new NodeRepository.
@@ -34,7 +42,14 @@ type nodeRepository struct {
 // Add method adds or updates a node in the database.
 func (r *nodeRepository) Add(node *model.Node) error {
 	filter := bson.M{"_id": node.ID}
+
+	// Alias IDs are added to the ones already recorded.
+	aliasIDs := node.AliasIDs
+	node.AliasIDs = nil
 	update := bson.M{"$set": node}
+	if len(aliasIDs) > 0 {
+		update["$addToSet"] = bson.M{"alias_ids": bson.M{"$each": aliasIDs}}
+	}
 	opt := options.FindOneAndUpdate().SetUpsert(true)
 
 	result, err := mongo.Client.FindOneAndUpdate(
@@ -60,6 +75,7 @@ func (r *nodeRepository) Add(node *model.Node) error {
 	}
 
 	node.Version = updated.Version
+	node.AliasIDs = updated.AliasIDs
 
 	return nil
 }
@@ -95,6 +111,97 @@ func (r *nodeRepository) GetByID(
 	return &node, nil
 }
 
+// GetByAliasID method retrieves a node from the database using one of the
+// IDs of its profile URL variants.
+func (r *nodeRepository) GetByAliasID(
+	aliasID string,
+) (*model.Node, error) {
+	filter := bson.M{"alias_ids": aliasID}
+
+	result := mongo.Client.FindOne(constant.MongoIndex.Node, filter)
+	if err := result.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, index.NotFoundError{
+				Err: err,
+			}
+		}
+		return nil, index.DatabaseError{
+			Message: "Error when trying to find a node by alias",
+			Err:     err,
+		}
+	}
+
+	var node model.Node
+	err := result.Decode(&node)
+	if err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to find a node by alias",
+			Err:     err,
+		}
+	}
+
+	return &node, nil
+}
+
+// List method returns up to limit nodes ordered by id, starting after afterID.
+func (r *nodeRepository) List(
+	afterID string,
+	limit int64,
+) ([]*model.Node, error) {
+	filter := bson.M{}
+	if afterID != "" {
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(limit)
+
+	cursor, err := mongo.Client.Find(constant.MongoIndex.Node, filter, opts)
+	if err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to list nodes",
+			Err:     err,
+		}
+	}
+
+	var nodes []*model.Node
+	if err := cursor.All(context.Background(), &nodes); err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to decode nodes",
+			Err:     err,
+		}
+	}
+
+	return nodes, nil
+}
+
+// AddAliasIDs method records additional ids the node can be found by.
+func (r *nodeRepository) AddAliasIDs(nodeID string, aliasIDs []string) error {
+	if len(aliasIDs) == 0 {
+		return nil
+	}
+
+	filter := bson.M{"_id": nodeID}
+	update := bson.M{
+		"$addToSet": bson.M{"alias_ids": bson.M{"$each": aliasIDs}},
+	}
+
+	_, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.Node,
+		filter,
+		update,
+	)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return index.DatabaseError{
+			Message: "Error when trying to add node aliases",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
 func (r *nodeRepository) Update(node *model.Node) error {
 	filter := bson.M{"_id": node.ID}
 
@@ -126,6 +233,89 @@ func (r *nodeRepository) Update(node *model.Node) error {
 	return nil
 }
 
+// SetPrimaryURLCheck records the primary URL check and the quality of the
+// node. The version of the node is left unchanged, so the validation results
+// in flight still apply to it.
+func (r *nodeRepository) SetPrimaryURLCheck(node *model.Node) error {
+	filter := bson.M{"_id": node.ID}
+	update := bson.M{"$set": bson.M{
+		"primary_url_check": node.PrimaryURLCheck,
+		"quality_factors":   node.QualityFactors,
+		"quality":           node.Quality,
+	}}
+
+	_, err := mongo.Client.GetClient().
+		Database(config.Values.Mongo.DBName).
+		Collection(constant.MongoIndex.Node).
+		UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to update a node",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// SetRevalidationID marks the node as sent for validation by the
+// revalidation run and returns the updated node. A node already marked by the
+// run, e.g. before the index restarted, isn't found, so it isn't sent twice.
+func (r *nodeRepository) SetRevalidationID(
+	nodeID string,
+	revalidationID string,
+) (*model.Node, error) {
+	result, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.Node,
+		bson.M{"_id": nodeID, "revalidation_id": bson.M{"$ne": revalidationID}},
+		bson.M{"$set": bson.M{"revalidation_id": revalidationID}},
+	)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, index.NotFoundError{
+				Err: err,
+			}
+		}
+		return nil, index.DatabaseError{
+			Message: "Error when trying to mark a node for revalidation",
+			Err:     err,
+		}
+	}
+
+	var node model.Node
+	if err := result.Decode(&node); err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to decode a node",
+			Err:     err,
+		}
+	}
+	return &node, nil
+}
+
+// ClearRevalidationID removes the mark of the revalidation run from the node.
+// It reports whether the node was marked, so the outcome of its validation is
+// counted once.
+func (r *nodeRepository) ClearRevalidationID(
+	nodeID string,
+	revalidationID string,
+) (bool, error) {
+	_, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.Node,
+		bson.M{"_id": nodeID, "revalidation_id": revalidationID},
+		bson.M{"$unset": bson.M{"revalidation_id": ""}},
+	)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, nil
+		}
+		return false, index.DatabaseError{
+			Message: "Error when trying to clear the revalidation of a node",
+			Err:     err,
+		}
+	}
+	return true, nil
+}
+
 func (r *nodeRepository) Delete(node *model.Node) error {
 	filter := bson.M{"_id": node.ID}
 
@@ -0,0 +1,183 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+)
+
+// RevalidationRepository defines the database operations on the runs
+// revalidating nodes linked to updated schemas.
+type RevalidationRepository interface {
+	Add(revalidation *model.Revalidation) error
+	Get(id string) (*model.Revalidation, error)
+	List(limit int64) ([]*model.Revalidation, error)
+	ListUnfinished() ([]*model.Revalidation, error)
+	SetSearchAfter(id string, searchAfter []interface{}) error
+	Finish(id string, finishedAt int64) error
+	Increment(id string, counter string) error
+}
+
+// NewRevalidationRepository returns a new RevalidationRepository.
+func NewRevalidationRepository() RevalidationRepository {
+	return &revalidationRepository{}
+}
+
+type revalidationRepository struct {
+}
+
+// Add stores a new revalidation run.
+func (r *revalidationRepository) Add(revalidation *model.Revalidation) error {
+	_, err := mongo.Client.InsertOne(constant.MongoIndex.Revalidation, revalidation)
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to add a revalidation",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// Get retrieves a revalidation run by its id.
+func (r *revalidationRepository) Get(id string) (*model.Revalidation, error) {
+	result := mongo.Client.FindOne(
+		constant.MongoIndex.Revalidation,
+		bson.M{"_id": id},
+	)
+	if err := result.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, index.NotFoundError{
+				Err: err,
+			}
+		}
+		return nil, index.DatabaseError{
+			Message: "Error when trying to find a revalidation",
+			Err:     err,
+		}
+	}
+
+	var revalidation model.Revalidation
+	if err := result.Decode(&revalidation); err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to decode a revalidation",
+			Err:     err,
+		}
+	}
+	return &revalidation, nil
+}
+
+// List returns up to limit revalidation runs, the most recent first.
+func (r *revalidationRepository) List(
+	limit int64,
+) ([]*model.Revalidation, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "started_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := mongo.Client.Find(constant.MongoIndex.Revalidation, bson.M{}, opts)
+	if err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to list revalidations",
+			Err:     err,
+		}
+	}
+
+	revalidations := []*model.Revalidation{}
+	if err := cursor.All(context.Background(), &revalidations); err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to decode revalidations",
+			Err:     err,
+		}
+	}
+	return revalidations, nil
+}
+
+// ListUnfinished returns the revalidation runs whose nodes weren't all sent
+// for validation, the oldest first.
+func (r *revalidationRepository) ListUnfinished() ([]*model.Revalidation, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "started_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := mongo.Client.Find(
+		constant.MongoIndex.Revalidation,
+		bson.M{"finished_at": 0},
+		opts,
+	)
+	if err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to list revalidations",
+			Err:     err,
+		}
+	}
+
+	revalidations := []*model.Revalidation{}
+	if err := cursor.All(context.Background(), &revalidations); err != nil {
+		return nil, index.DatabaseError{
+			Message: "Error when trying to decode revalidations",
+			Err:     err,
+		}
+	}
+	return revalidations, nil
+}
+
+// SetSearchAfter records the sort of the last page of nodes sent by the run.
+func (r *revalidationRepository) SetSearchAfter(
+	id string,
+	searchAfter []interface{},
+) error {
+	_, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.Revalidation,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"search_after": searchAfter}},
+	)
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to update a revalidation",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// Finish records that all the nodes of the run were sent for validation.
+func (r *revalidationRepository) Finish(id string, finishedAt int64) error {
+	_, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.Revalidation,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"finished_at": finishedAt}},
+	)
+	if err != nil {
+		return index.DatabaseError{
+			Message: "Error when trying to finish a revalidation",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// Increment increments the counter of the run named by counter, one of
+// model.RevalidationSent, model.RevalidationPassed, model.RevalidationFailed
+// and model.RevalidationErrored.
+func (r *revalidationRepository) Increment(id string, counter string) error {
+	_, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.Revalidation,
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{counter: 1}},
+	)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return index.DatabaseError{
+			Message: "Error when trying to update a revalidation counter",
+			Err:     err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"time"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/vocabulary"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
+)
+
+// RevalidationInterval is a wrapper around the unexported
+// revalidationInterval function.
+func RevalidationInterval(rate int) time.Duration {
+	return revalidationInterval(rate)
+}
+
+// ExpandTags is a wrapper around the unexported expandTags method, expanding
+// the tags with the vocabularies of the library at libraryURL.
+func ExpandTags(libraryURL string, query *es.Query) error {
+	s := &nodeService{vocabularies: vocabulary.NewCache(libraryURL, time.Minute)}
+	return s.expandTags(query)
+}
@@ -1,18 +1,22 @@
 package service
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/cryptoutil"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/dateutil"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilehasher"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/urlutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/vocabulary"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
@@ -24,44 +28,82 @@ import (
 type NodeService interface {
 	AddNode(node *model.Node) (*model.Node, error)
 	GetNode(nodeID string) (*model.Node, error)
+	GetNodeByProfileURL(profileURL string) (*model.Node, error)
 	SetNodeValid(node *model.Node) error
 	SetNodeInvalid(node *model.Node) error
+	SetNodeGone(node *model.Node) error
+	SetNodePostFailed(nodeID string) error
+	RecordPrimaryURLCheck(
+		nodeID string,
+		primaryURL string,
+		statusCode int,
+		reachable bool,
+		checkedAt int64,
+	) error
 	Search(query *es.Query) (*es.QueryResults, error)
 	Delete(nodeID string) (string, error)
 	Export(query *es.BlockQuery) (*es.BlockQueryResults, error)
 	GetNodes(query *es.Query) (*es.MapQueryResults, error)
+	FetchProfile(profileURL string) (string, error)
 }
 
+// vocabularyCacheTTL is how long the vocabularies fetched from the library
+// are kept.
+const vocabularyCacheTTL = 5 * time.Minute
+
 type nodeService struct {
-	mongoRepo   mongo.NodeRepository
-	elasticRepo es.NodeRepository
+	mongoRepo        mongo.NodeRepository
+	elasticRepo      es.NodeRepository
+	revalidationRepo mongo.RevalidationRepository
+	fetcher          *httputil.Fetcher
+	// vocabularies caches the vocabularies the tags are expanded with.
+	vocabularies *vocabulary.Cache
 }
 
 // NewNodeService creates a new instance of NodeService.
 func NewNodeService(
 	mongoRepo mongo.NodeRepository,
 	elasticRepo es.NodeRepository,
-
+	revalidationRepo mongo.RevalidationRepository,
 ) NodeService {
 	return &nodeService{
-		mongoRepo:   mongoRepo,
-		elasticRepo: elasticRepo,
+		mongoRepo:        mongoRepo,
+		elasticRepo:      elasticRepo,
+		revalidationRepo: revalidationRepo,
+		fetcher: httputil.NewFetcher(httputil.FetcherOptions{
+			MaxBodySize:  config.Values.Fetch.MaxBodySize,
+			MaxJSONDepth: config.Values.Fetch.MaxJSONDepth,
+			AllowedHosts: config.Values.Fetch.AllowedHosts,
+		}),
+		vocabularies: vocabulary.NewCache(
+			config.Values.Library.InternalURL,
+			vocabularyCacheTTL,
+		),
 	}
 }
 
 // SetNodeValid sets a node as valid, updates its status, and indexes it in the
 // repositories.
 func (s *nodeService) SetNodeValid(node *model.Node) error {
-	// Prepare the node.
-	node.ID = cryptoutil.ComputeSHA256(node.ProfileURL)
-	node.SetStatusValidated()
-	node.ResetFailureReasons()
-
 	// Retrieve the old node from the database.
-	oldNode, err := s.mongoRepo.GetByID(node.ID)
-	if err != nil && !errors.As(err, &index.NotFoundError{}) {
+	oldNode, err := s.resolveNode(node)
+	if err != nil {
 		return err
 	}
+	defer s.recordRevalidation(node, oldNode, model.RevalidationPassed)
+
+	// A profile that permanently redirects elsewhere is moved to its new URL.
+	if node.MovedTo != "" && oldNode != nil && isCurrentVersion(node, oldNode) {
+		oldNode, err = s.moveNode(node, oldNode)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Prepare the node.
+	node.SetStatusValidated()
+	node.ResetFailureReasons()
+	node.ResetFailedCrawls()
 
 	if s.isProfileHashUnchanged(node, oldNode) {
 		logger.Info(fmt.Sprintf("Node with profile hash '%s' is unchanged.", *node.ProfileHash))
@@ -69,6 +111,16 @@ func (s *nodeService) SetNodeValid(node *model.Node) error {
 		node.LastUpdated = oldNode.LastUpdated
 	}
 
+	node.KeepPrimaryURLCheck(oldNode)
+	if node.PrimaryURLCheck != nil && node.QualityFactors != nil &&
+		node.PrimaryURLCheck.LastChecked > 0 {
+		// The checks over time say more than the one made while validating.
+		factors := *node.QualityFactors
+		factors.PrimaryURLReachable = node.PrimaryURLCheck.Status == model.PrimaryURLOK
+		node.QualityFactors = &factors
+	}
+	node.ScoreQuality(time.Now())
+
 	// Update the node in MongoDB.
 	if err := s.mongoRepo.Update(node); err != nil {
 		return err
@@ -83,6 +135,10 @@ func (s *nodeService) SetNodeValid(node *model.Node) error {
 	if node.Expires != nil {
 		profileJSON["expires"] = *node.Expires
 	}
+	if node.Quality != nil {
+		profileJSON["quality"] = *node.Quality
+	}
+	addPrimaryURLCheck(profileJSON, node.PrimaryURLCheck)
 
 	// Update Elastic Search.
 	if err := s.elasticRepo.IndexByID(node.ID, profileJSON); err != nil {
@@ -101,6 +157,161 @@ func (s *nodeService) SetNodeValid(node *model.Node) error {
 	return s.mongoRepo.Update(node)
 }
 
+// addPrimaryURLCheck adds the primary URL check to the profile indexed in
+// Elasticsearch, unless the primary URL is unchecked.
+func addPrimaryURLCheck(
+	profileJSON map[string]interface{},
+	check *model.PrimaryURLCheck,
+) {
+	if check == nil || check.LastChecked == 0 {
+		return
+	}
+	profileJSON["primary_url_status"] = check.Status
+	profileJSON["primary_url_check"] = check
+}
+
+// RecordPrimaryURLCheck records a liveness check of the primary URL of a
+// posted node. Checks of a primary URL the node no longer has are ignored.
+func (s *nodeService) RecordPrimaryURLCheck(
+	nodeID string,
+	primaryURL string,
+	statusCode int,
+	reachable bool,
+	checkedAt int64,
+) error {
+	node, err := s.mongoRepo.GetByID(nodeID)
+	if errors.As(err, &index.NotFoundError{}) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if node.Status != constant.NodeStatus.Posted ||
+		node.PrimaryURL == nil || *node.PrimaryURL != primaryURL {
+		return nil
+	}
+
+	node.RecordPrimaryURLCheck(
+		statusCode,
+		reachable,
+		checkedAt,
+		config.Values.LinkCheck.DeadAfter,
+	)
+	node.ScoreQuality(time.Now())
+
+	if node.PrimaryURLCheck.Status == model.PrimaryURLDead &&
+		node.PrimaryURLCheck.Failures == config.Values.LinkCheck.DeadAfter {
+		logger.Info(fmt.Sprintf(
+			"Primary URL '%s' of node '%s' is dead.",
+			primaryURL,
+			node.ProfileURL,
+		))
+	}
+
+	// The check doesn't bump the version of the node, which would discard the
+	// results of the validations in flight for it.
+	if err := s.mongoRepo.SetPrimaryURLCheck(node); err != nil {
+		return err
+	}
+
+	return s.elasticRepo.UpdatePrimaryURLCheck(node)
+}
+
+// moveNode re-keys the stored node under the canonical form of node.MovedTo.
+// The old ID and profile URL are kept as an alias and in the node's history,
+// so the node can still be found by them. node is updated to refer to the
+// moved node, which is returned.
+func (s *nodeService) moveNode(
+	node *model.Node,
+	oldNode *model.Node,
+) (*model.Node, error) {
+	movedURL, err := urlutil.Canonicalize(node.MovedTo)
+	if err != nil || movedURL == node.ProfileURL {
+		return oldNode, nil
+	}
+
+	moved := *oldNode
+	moved.ID = cryptoutil.ComputeSHA256(movedURL)
+	moved.ProfileURL = movedURL
+	moved.Version = nil
+	moved.AliasIDs = append(append([]string{}, oldNode.AliasIDs...), oldNode.ID)
+	moved.PreviousProfileURLs = append(
+		append([]string{}, oldNode.PreviousProfileURLs...),
+		oldNode.ProfileURL,
+	)
+	if err := s.mongoRepo.Add(&moved); err != nil {
+		return nil, err
+	}
+
+	if err := s.mongoRepo.Delete(oldNode); err != nil {
+		return nil, err
+	}
+	if err := s.elasticRepo.DeleteByID(oldNode.ID); err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf(
+		"Node moved from '%s' to '%s'.",
+		oldNode.ProfileURL,
+		movedURL,
+	))
+
+	node.ID = moved.ID
+	node.ProfileURL = moved.ProfileURL
+	node.Version = moved.Version
+	return &moved, nil
+}
+
+// recordRevalidation counts the outcome of the validation of a node sent by a
+// revalidation run in the run. It is deferred, as removing the mark of the run
+// changes the version of the node.
+func (s *nodeService) recordRevalidation(
+	node *model.Node,
+	stored *model.Node,
+	outcome string,
+) {
+	if stored == nil || stored.RevalidationID == nil {
+		return
+	}
+	revalidationID := *stored.RevalidationID
+
+	// The node ID changes if the node moved.
+	cleared, err := s.mongoRepo.ClearRevalidationID(node.ID, revalidationID)
+	if err == nil && cleared {
+		err = s.revalidationRepo.Increment(revalidationID, outcome)
+	}
+	if err != nil {
+		logger.Error(
+			fmt.Sprintf("Failed to record revalidation of node '%s'", node.ProfileURL),
+			err,
+		)
+	}
+}
+
+// isCurrentVersion reports whether the event node refers to the current
+// version of the stored node.
+func isCurrentVersion(node *model.Node, stored *model.Node) bool {
+	return node.Version == nil || stored.Version == nil ||
+		*node.Version == *stored.Version
+}
+
+// SetNodePostFailed marks a posted node as post_failed. It is used when
+// indexing the node in Elasticsearch failed after SetNodeValid returned, so
+// that revalidatenode picks the node up again.
+func (s *nodeService) SetNodePostFailed(nodeID string) error {
+	node, err := s.mongoRepo.GetByID(nodeID)
+	if err != nil {
+		return err
+	}
+
+	if node.Status != constant.NodeStatus.Posted {
+		return nil
+	}
+
+	node.SetStatusPostFailed()
+	return s.mongoRepo.Update(node)
+}
+
 // isProfileHashUnchanged checks if the profile hash of the new node matches
 // the old node. It returns true if the hashes are the same.
 func (s *nodeService) isProfileHashUnchanged(
@@ -120,7 +331,23 @@ func (s *nodeService) isProfileHashUnchanged(
 
 // SetNodeInvalid sets a node as invali.
 func (s *nodeService) SetNodeInvalid(node *model.Node) error {
-	node.ID = cryptoutil.ComputeSHA256(node.ProfileURL)
+	stored, err := s.resolveNode(node)
+	if err != nil {
+		return err
+	}
+	outcome := model.RevalidationFailed
+	if isTemporaryFailure(node.FailureReasons) {
+		outcome = model.RevalidationErrored
+	}
+	defer s.recordRevalidation(node, stored, outcome)
+
+	// A posted node is only sent for validation again by the recrawler, which
+	// tolerates a few failures before the node is removed.
+	if stored != nil && stored.Status == constant.NodeStatus.Posted &&
+		isCurrentVersion(node, stored) {
+		return s.recordFailedCrawl(node, stored)
+	}
+
 	node.Status = constant.NodeStatus.ValidationFailed
 	emptystr := ""
 	node.ProfileHash = &emptystr
@@ -134,6 +361,75 @@ func (s *nodeService) SetNodeInvalid(node *model.Node) error {
 	return s.elasticRepo.DeleteByID(node.ID)
 }
 
+// SetNodeGone deletes a node whose profile URL responded with 410 Gone, which
+// means its owner removed the profile on purpose.
+func (s *nodeService) SetNodeGone(node *model.Node) error {
+	stored, err := s.resolveNode(node)
+	if err != nil {
+		return err
+	}
+	if stored == nil || !isCurrentVersion(node, stored) {
+		return nil
+	}
+
+	_, err = s.proceedWithDeletion(stored)
+	return err
+}
+
+// recordFailedCrawl counts a failed recrawl of a posted node and soft deletes
+// the node once config.Values.Recrawl.MaxFailures consecutive recrawls found
+// its profile missing or invalid. Failures that may be temporary, such as
+// server errors, aren't counted.
+func (s *nodeService) recordFailedCrawl(
+	node *model.Node,
+	stored *model.Node,
+) error {
+	if isTemporaryFailure(node.FailureReasons) {
+		logger.Info(fmt.Sprintf(
+			"Recrawl of node '%s' failed temporarily.",
+			stored.ProfileURL,
+		))
+		return nil
+	}
+
+	failedCrawls := 1
+	if stored.FailedCrawls != nil {
+		failedCrawls += *stored.FailedCrawls
+	}
+
+	if failedCrawls >= config.Values.Recrawl.MaxFailures {
+		logger.Info(fmt.Sprintf(
+			"Deleting node '%s' after %d failed recrawls.",
+			stored.ProfileURL,
+			failedCrawls,
+		))
+		_, err := s.proceedWithDeletion(stored)
+		return err
+	}
+
+	return s.mongoRepo.Update(&model.Node{
+		ID:             node.ID,
+		Version:        node.Version,
+		FailureReasons: node.FailureReasons,
+		FailedCrawls:   &failedCrawls,
+	})
+}
+
+// isTemporaryFailure reports whether any of the failure reasons is a server
+// error, on the profile host's side or ours, or rate limiting.
+func isTemporaryFailure(failureReasons *[]jsonapi.Error) bool {
+	if failureReasons == nil {
+		return false
+	}
+	for _, reason := range *failureReasons {
+		if reason.Status >= http.StatusInternalServerError ||
+			reason.Status == http.StatusTooManyRequests {
+			return true
+		}
+	}
+	return false
+}
+
 // AddNode adds a new node to the system.
 func (s *nodeService) AddNode(
 	node *model.Node,
@@ -142,6 +438,21 @@ func (s *nodeService) AddNode(
 		return nil, err
 	}
 
+	canonicalURL, err := urlutil.Canonicalize(node.ProfileURL)
+	if err != nil {
+		return nil, index.ValidationError{
+			Field:  "ProfileURL",
+			Reason: "The `profile_url` is not a valid URL.",
+		}
+	}
+	// Remember the ID of the submitted variant, so clients that keep it can
+	// still find the node.
+	var aliasIDs []string
+	if canonicalURL != node.ProfileURL {
+		aliasIDs = append(aliasIDs, cryptoutil.ComputeSHA256(node.ProfileURL))
+	}
+	node.AliasIDs = aliasIDs
+	node.ProfileURL = canonicalURL
 	node.ID = cryptoutil.ComputeSHA256(node.ProfileURL)
 
 	oldNode, err := s.mongoRepo.GetByID(node.ID)
@@ -163,6 +474,14 @@ func (s *nodeService) AddNode(
 		return nil, err
 	}
 
+	// A node stored under the submitted variant before profile URLs were
+	// canonicalized is replaced by the canonical node.
+	for _, aliasID := range aliasIDs {
+		if err := s.removeNodeByID(aliasID); err != nil {
+			return nil, err
+		}
+	}
+
 	err = messaging.Publish(messaging.NodeCreated, messaging.NodeCreatedData{
 		ProfileURL: node.ProfileURL,
 		Version:    *node.Version,
@@ -174,6 +493,23 @@ func (s *nodeService) AddNode(
 	return node, nil
 }
 
+// removeNodeByID removes the node with the given ID, if any, from both
+// repositories.
+func (s *nodeService) removeNodeByID(nodeID string) error {
+	node, err := s.mongoRepo.GetByID(nodeID)
+	if errors.As(err, &index.NotFoundError{}) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.mongoRepo.Delete(node); err != nil {
+		return err
+	}
+	return s.elasticRepo.DeleteByID(node.ID)
+}
+
 func validateProfileURL(url string) error {
 	if url == "" {
 		return index.ValidationError{
@@ -190,17 +526,101 @@ func validateProfileURL(url string) error {
 	return nil
 }
 
-// GetNode retrieves a node based on its ID.
+// GetNode retrieves a node based on its ID. IDs of non-canonical profile URL
+// variants recorded for the node are accepted too.
 func (s *nodeService) GetNode(nodeID string) (*model.Node, error) {
 	node, err := s.mongoRepo.GetByID(nodeID)
+	if errors.As(err, &index.NotFoundError{}) {
+		node, err = s.mongoRepo.GetByAliasID(nodeID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// GetNodeByProfileURL retrieves a node based on any variant of its profile URL.
+func (s *nodeService) GetNodeByProfileURL(
+	profileURL string,
+) (*model.Node, error) {
+	canonicalURL, err := urlutil.Canonicalize(profileURL)
+	if err != nil {
+		return nil, index.ValidationError{
+			Field:  "ProfileURL",
+			Reason: "The `profile_url` is not a valid URL.",
+		}
+	}
+
+	node, err := s.GetNode(cryptoutil.ComputeSHA256(canonicalURL))
+	if errors.As(err, &index.NotFoundError{}) && canonicalURL != profileURL {
+		// Nodes that haven't been migrated yet are stored under the ID of the
+		// URL as it was submitted.
+		node, err = s.GetNode(cryptoutil.ComputeSHA256(profileURL))
+	}
 	if err != nil {
 		return nil, err
 	}
 	return node, nil
 }
 
+// FetchProfile fetches the profile at the given URL the same way the
+// validation service does, without adding it to the index.
+func (s *nodeService) FetchProfile(profileURL string) (string, error) {
+	canonicalURL, err := urlutil.Canonicalize(profileURL)
+	if err != nil {
+		return "", index.ValidationError{
+			Field:  "ProfileURL",
+			Reason: "The `profile_url` is not a valid URL.",
+		}
+	}
+
+	profileStr, _, err := s.fetcher.FetchJSONStr(canonicalURL)
+	if err != nil {
+		return "", err
+	}
+	return profileStr, nil
+}
+
+// resolveNode sets the ID and profile URL of the node to those of the stored
+// node it refers to and returns the stored node, or nil if there is none.
+// Nodes are stored under their canonical profile URL, except for nodes that
+// haven't been migrated yet, which are stored under the submitted URL.
+func (s *nodeService) resolveNode(node *model.Node) (*model.Node, error) {
+	submittedURL := node.ProfileURL
+	if canonicalURL, err := urlutil.Canonicalize(node.ProfileURL); err == nil {
+		node.ProfileURL = canonicalURL
+	}
+	node.ID = cryptoutil.ComputeSHA256(node.ProfileURL)
+
+	stored, err := s.mongoRepo.GetByID(node.ID)
+	if err == nil {
+		return stored, nil
+	}
+	if !errors.As(err, &index.NotFoundError{}) {
+		return nil, err
+	}
+	if submittedURL == node.ProfileURL {
+		return nil, nil
+	}
+
+	submittedID := cryptoutil.ComputeSHA256(submittedURL)
+	stored, err = s.mongoRepo.GetByID(submittedID)
+	if errors.As(err, &index.NotFoundError{}) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	node.ProfileURL = submittedURL
+	node.ID = submittedID
+	return stored, nil
+}
+
 // Search performs a search operation based on the provided query.
 func (s *nodeService) Search(query *es.Query) (*es.QueryResults, error) {
+	if err := s.expandTags(query); err != nil {
+		return nil, err
+	}
 	result, err := s.elasticRepo.Search(query)
 	if err != nil {
 		return nil, err
@@ -208,10 +628,62 @@ func (s *nodeService) Search(query *es.Query) (*es.QueryResults, error) {
 	return result, nil
 }
 
+// expandTags expands the comma-separated tags of the query, if they are
+// terms of a vocabulary, to their narrower terms, so that the profiles
+// tagged with a more specific term match too. Empty tags are ignored, and
+// the tags aren't filtered on if none is left.
+func (s *nodeService) expandTags(query *es.Query) error {
+	if query.TagsVocabulary == nil || query.Tags == nil {
+		return nil
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(*query.Tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		query.Tags = nil
+		return nil
+	}
+
+	v, err := s.vocabularies.Get(*query.TagsVocabulary)
+	if errors.Is(err, vocabulary.ErrVocabularyNotFound) {
+		return index.ValidationError{
+			Field: "tags_vocabulary",
+			Reason: fmt.Sprintf(
+				"The vocabulary `%s` is not in the Library.",
+				*query.TagsVocabulary,
+			),
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	query.TagsTerms = make([][]string, 0, len(tags))
+	for _, tag := range tags {
+		terms, ok := v.Expand(tag)
+		if !ok {
+			return index.ValidationError{
+				Field: "tags",
+				Reason: fmt.Sprintf(
+					"The tag `%s` is not a term of the vocabulary `%s`.",
+					tag,
+					v.Name,
+				),
+			}
+		}
+		query.TagsTerms = append(query.TagsTerms, terms)
+	}
+	return nil
+}
+
 // Delete deletes a node based on its ID. It checks a feature toggle to decide
 // whether to bypass the check for the profile URL's existence.
 func (s *nodeService) Delete(nodeID string) (string, error) {
-	node, err := s.mongoRepo.GetByID(nodeID)
+	node, err := s.GetNode(nodeID)
 	if err != nil {
 		return "", err
 	}
@@ -229,7 +701,16 @@ func (s *nodeService) Delete(nodeID string) (string, error) {
 
 // checkProfileURL checks the profile URL's existence, content type.
 func (s *nodeService) checkProfileURL(node *model.Node) error {
-	resp, err := httputil.Get(node.ProfileURL)
+	_, _, err := s.fetcher.FetchJSON(node.ProfileURL)
+
+	// Only a profile URL reporting that the profile is gone makes it safe to
+	// proceed with deletion. A URL that still answers, even with a document we
+	// wouldn't accept, or that is refused by the fetcher's policy doesn't
+	// prove the profile was removed.
+	if err != nil && isProfileGone(err) {
+		return nil
+	}
+
 	if err != nil {
 		// This error message better reflects that there was an issue with the
 		// HTTP request, not that the URL is non-existent.
@@ -244,24 +725,6 @@ func (s *nodeService) checkProfileURL(node *model.Node) error {
 			ErrorCode:  index.ErrorHTTPRequestFailed,
 		}
 	}
-	defer resp.Body.Close()
-
-	// Check if the response is JSON.
-	var bodyJSON interface{}
-	isJSON := true
-	decoder := json.NewDecoder(resp.Body)
-	err = decoder.Decode(&bodyJSON)
-	if err != nil {
-		isJSON = false
-	}
-
-	// If the profile URL doesn't return a 200 OK status, or if the response is
-	// not JSON, then we consider the profile URL invalid, or non-existent for
-	// our purposes and return nil, indicating that it's safe to proceed with
-	// deletion.
-	if resp.StatusCode != http.StatusOK || !isJSON {
-		return nil
-	}
 
 	return index.DeleteNodeError{
 		Message:    "Profile Still Exists",
@@ -271,6 +734,17 @@ func (s *nodeService) checkProfileURL(node *model.Node) error {
 	}
 }
 
+// isProfileGone reports whether the fetch error means the profile URL no
+// longer serves a profile, i.e. it responded with 404 Not Found or 410 Gone.
+func isProfileGone(err error) bool {
+	var statusErr httputil.StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	return statusErr.StatusCode == http.StatusNotFound ||
+		statusErr.StatusCode == http.StatusGone
+}
+
 // proceedWithDeletion contains the logic to delete the node from the database.
 func (s *nodeService) proceedWithDeletion(node *model.Node) (string, error) {
 	var err error
@@ -306,6 +780,9 @@ func (s *nodeService) Export(
 func (s *nodeService) GetNodes(
 	query *es.Query,
 ) (*es.MapQueryResults, error) {
+	if err := s.expandTags(query); err != nil {
+		return nil, err
+	}
 	result, err := s.elasticRepo.GetNodes(query)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
+)
+
+func TestExpandTags(t *testing.T) {
+	mockServer := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v2/vocabularies/categories-v1" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, err := w.Write([]byte(`{"data": {
+				"name": "categories-v1.0.0",
+				"terms": [
+					{"id": "non-profit", "narrower": ["cooperative"]},
+					{"id": "cooperative", "broader": ["non-profit"]}
+				]
+			}}`))
+			require.NoError(t, err)
+		}),
+	)
+	defer mockServer.Close()
+
+	tests := []struct {
+		name       string
+		tags       string
+		vocabulary string
+		expTags    bool
+		expTerms   [][]string
+		expField   string
+	}{
+		{
+			name:       "Tags expanded to their narrower terms",
+			tags:       "non-profit, cooperative,",
+			vocabulary: "categories-v1",
+			expTags:    true,
+			expTerms:   [][]string{{"non-profit", "cooperative"}, {"cooperative"}},
+		},
+		{
+			name:       "No tags",
+			tags:       " , ",
+			vocabulary: "categories-v1",
+		},
+		{
+			name:       "Unknown term",
+			tags:       "charity",
+			vocabulary: "categories-v1",
+			expField:   "tags",
+		},
+		{
+			name:       "Unknown vocabulary",
+			tags:       "charity",
+			vocabulary: "topics-v1",
+			expField:   "tags_vocabulary",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := &es.Query{Tags: &tt.tags, TagsVocabulary: &tt.vocabulary}
+			err := service.ExpandTags(mockServer.URL, query)
+			if tt.expField != "" {
+				var validationErr index.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				require.Equal(t, tt.expField, validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expTags, query.Tags != nil)
+			require.Equal(t, tt.expTerms, query.TagsTerms)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/cryptoutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/dateutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaname"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+)
+
+// revalidationPageSize is the number of nodes read from Elasticsearch at a
+// time.
+const revalidationPageSize = 500
+
+// revalidationListSize is the number of most recent runs listed.
+const revalidationListSize = 100
+
+// RevalidationService sends the posted nodes linked to updated schemas for
+// validation again and reports how many of them still pass.
+type RevalidationService interface {
+	Start(schemas []string, version string) (*model.Revalidation, error)
+	Run(revalidation *model.Revalidation) error
+	Resume() error
+	Get(revalidationID string) (*model.Revalidation, error)
+	List() ([]*model.Revalidation, error)
+}
+
+type revalidationService struct {
+	mongoRepo        mongo.NodeRepository
+	elasticRepo      es.NodeRepository
+	revalidationRepo mongo.RevalidationRepository
+}
+
+// NewRevalidationService creates a new instance of RevalidationService.
+func NewRevalidationService(
+	mongoRepo mongo.NodeRepository,
+	elasticRepo es.NodeRepository,
+	revalidationRepo mongo.RevalidationRepository,
+) RevalidationService {
+	return &revalidationService{
+		mongoRepo:        mongoRepo,
+		elasticRepo:      elasticRepo,
+		revalidationRepo: revalidationRepo,
+	}
+}
+
+// Start records a run revalidating the posted nodes linking any of the
+// schemas, which Run then carries out.
+func (s *revalidationService) Start(
+	schemas []string,
+	version string,
+) (*model.Revalidation, error) {
+	revalidation := &model.Revalidation{
+		ID:        primitive.NewObjectID().Hex(),
+		Schemas:   schemas,
+		Version:   version,
+		StartedAt: dateutil.GetNowUnix(),
+	}
+	if err := s.revalidationRepo.Add(revalidation); err != nil {
+		return nil, err
+	}
+	return revalidation, nil
+}
+
+// Run pages through the posted nodes linking any of the schemas of the run,
+// directly or by their major version range, and publishes them for
+// validation at config.Values.SchemaRevalidation.Rate nodes per second. Each
+// node is marked with the run, so the index counts the outcome of its
+// validation in the run. The progress is recorded after each page, so a run
+// interrupted by a restart continues where it stopped. It returns once all
+// the nodes were sent.
+func (s *revalidationService) Run(revalidation *model.Revalidation) error {
+	var refs []string
+	for _, schema := range revalidation.Schemas {
+		refs = append(refs, schemaname.Refs(schema)...)
+	}
+
+	ticker := time.NewTicker(
+		revalidationInterval(config.Values.SchemaRevalidation.Rate),
+	)
+	defer ticker.Stop()
+
+	status := constant.NodeStatus.Posted
+	query := &es.BlockQuery{
+		LinkedSchemas: refs,
+		Status:        &status,
+		PageSize:      revalidationPageSize,
+		SearchAfter:   revalidation.SearchAfter,
+	}
+	for {
+		results, err := s.elasticRepo.Export(query)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results.Result {
+			profileURL, _ := result["profile_url"].(string)
+			if profileURL == "" {
+				continue
+			}
+			<-ticker.C
+			if err := s.send(revalidation, profileURL); err != nil {
+				return fmt.Errorf(
+					"error sending node %s for revalidation: %w",
+					profileURL,
+					err,
+				)
+			}
+		}
+
+		if len(results.Result) < revalidationPageSize || results.Sort == nil {
+			break
+		}
+		query.SearchAfter = results.Sort
+		if err := s.revalidationRepo.SetSearchAfter(
+			revalidation.ID,
+			results.Sort,
+		); err != nil {
+			return err
+		}
+	}
+
+	revalidation.FinishedAt = dateutil.GetNowUnix()
+	if err := s.revalidationRepo.Finish(
+		revalidation.ID,
+		revalidation.FinishedAt,
+	); err != nil {
+		return err
+	}
+
+	logger.Info(fmt.Sprintf(
+		"Sent %d node(s) linked to updated schemas %v for revalidation",
+		revalidation.Sent,
+		revalidation.Schemas,
+	))
+
+	return nil
+}
+
+// Resume runs the revalidations that were interrupted before all their nodes
+// were sent, the oldest first.
+func (s *revalidationService) Resume() error {
+	revalidations, err := s.revalidationRepo.ListUnfinished()
+	if err != nil {
+		return err
+	}
+	for _, revalidation := range revalidations {
+		logger.Info(fmt.Sprintf(
+			"Resuming the revalidation of nodes linked to schemas %v",
+			revalidation.Schemas,
+		))
+		if err := s.Run(revalidation); err != nil {
+			return fmt.Errorf(
+				"error resuming revalidation %s: %w",
+				revalidation.ID,
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+// revalidationInterval returns the interval between two nodes sent for
+// validation at rate nodes per second. The rate is kept between 1 and one
+// node per nanosecond, the shortest interval a ticker takes.
+func revalidationInterval(rate int) time.Duration {
+	if rate < 1 {
+		rate = 1
+	}
+	if rate > int(time.Second) {
+		rate = int(time.Second)
+	}
+	return time.Second / time.Duration(rate)
+}
+
+// send marks the node with the run and publishes it for validation.
+func (s *revalidationService) send(
+	revalidation *model.Revalidation,
+	profileURL string,
+) error {
+	node, err := s.mongoRepo.SetRevalidationID(
+		cryptoutil.ComputeSHA256(profileURL),
+		revalidation.ID,
+	)
+	// The node was deleted since it was indexed.
+	if errors.As(err, &index.NotFoundError{}) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = messaging.Publish(messaging.NodeCreated, messaging.NodeCreatedData{
+		ProfileURL: node.ProfileURL,
+		Version:    *node.Version,
+	})
+	if err != nil {
+		return err
+	}
+
+	revalidation.Sent++
+	return s.revalidationRepo.Increment(revalidation.ID, model.RevalidationSent)
+}
+
+// Get retrieves a revalidation run by its ID.
+func (s *revalidationService) Get(
+	revalidationID string,
+) (*model.Revalidation, error) {
+	return s.revalidationRepo.Get(revalidationID)
+}
+
+// List returns the most recent revalidation runs.
+func (s *revalidationService) List() ([]*model.Revalidation, error) {
+	return s.revalidationRepo.List(revalidationListSize)
+}
@@ -0,0 +1,29 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
+)
+
+func TestRevalidationInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     int
+		expected time.Duration
+	}{
+		{name: "rate", rate: 10, expected: 100 * time.Millisecond},
+		{name: "zero rate", rate: 0, expected: time.Second},
+		{name: "negative rate", rate: -5, expected: time.Second},
+		{name: "rate above one per nanosecond", rate: 2e9, expected: time.Nanosecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, service.RevalidationInterval(tt.rate))
+		})
+	}
+}
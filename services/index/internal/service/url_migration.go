@@ -0,0 +1,236 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/cryptoutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/urlutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+)
+
+// migrationPageSize is the number of nodes read from MongoDB at a time.
+const migrationPageSize = 500
+
+// URLMigrationResult summarizes a run of the profile URL migration.
+type URLMigrationResult struct {
+	// Scanned is the number of nodes read.
+	Scanned int
+	// Invalid is the number of nodes whose profile URL can't be canonicalized.
+	Invalid int
+	// Migrated is the number of canonical nodes written.
+	Migrated int
+	// Merged is the number of duplicate nodes removed.
+	Merged int
+	// Republished is the number of canonical nodes sent for validation.
+	Republished int
+}
+
+// URLMigrationService re-keys nodes stored under a non-canonical profile URL
+// and merges nodes whose profile URLs are variants of the same URL.
+type URLMigrationService interface {
+	Migrate() (*URLMigrationResult, error)
+}
+
+type urlMigrationService struct {
+	mongoRepo   mongo.NodeRepository
+	elasticRepo es.NodeRepository
+}
+
+// NewURLMigrationService creates a new instance of URLMigrationService.
+func NewURLMigrationService(
+	mongoRepo mongo.NodeRepository,
+	elasticRepo es.NodeRepository,
+) URLMigrationService {
+	return &urlMigrationService{
+		mongoRepo:   mongoRepo,
+		elasticRepo: elasticRepo,
+	}
+}
+
+// Migrate scans all nodes and, for every canonical profile URL that has
+// non-canonical variants stored, keeps the best node under the canonical ID
+// and removes the others. The IDs of the removed nodes are kept as aliases of
+// the canonical node. Canonical nodes that replace an indexed node are sent
+// for validation again so they are indexed under their new ID.
+func (s *urlMigrationService) Migrate() (*URLMigrationResult, error) {
+	result := &URLMigrationResult{}
+
+	// Nodes to migrate, grouped by canonical profile URL.
+	groups := make(map[string][]*model.Node)
+
+	afterID := ""
+	for {
+		nodes, err := s.mongoRepo.List(afterID, migrationPageSize)
+		if err != nil {
+			return result, err
+		}
+
+		for _, node := range nodes {
+			result.Scanned++
+			canonicalURL, err := urlutil.Canonicalize(node.ProfileURL)
+			if err != nil {
+				result.Invalid++
+				continue
+			}
+			if canonicalURL == node.ProfileURL {
+				continue
+			}
+			groups[canonicalURL] = append(groups[canonicalURL], node)
+		}
+
+		if len(nodes) < migrationPageSize {
+			break
+		}
+		afterID = nodes[len(nodes)-1].ID
+	}
+
+	for canonicalURL, variants := range groups {
+		if err := s.migrateGroup(canonicalURL, variants, result); err != nil {
+			return result, fmt.Errorf(
+				"error migrating nodes of profile URL %s: %w",
+				canonicalURL,
+				err,
+			)
+		}
+	}
+
+	return result, nil
+}
+
+// migrateGroup merges the nodes stored under variants of canonicalURL into
+// the canonical node.
+func (s *urlMigrationService) migrateGroup(
+	canonicalURL string,
+	variants []*model.Node,
+	result *URLMigrationResult,
+) error {
+	canonicalID := cryptoutil.ComputeSHA256(canonicalURL)
+
+	candidates := append([]*model.Node{}, variants...)
+	existing, err := s.mongoRepo.GetByID(canonicalID)
+	if err != nil && !errors.As(err, &index.NotFoundError{}) {
+		return err
+	}
+	if existing != nil {
+		candidates = append(candidates, existing)
+	}
+
+	best := bestNode(candidates)
+
+	aliasIDs := make([]string, 0, len(variants))
+	for _, node := range variants {
+		aliasIDs = append(aliasIDs, node.ID)
+		aliasIDs = append(aliasIDs, node.AliasIDs...)
+	}
+	// The aliases already recorded on the canonical node are kept when it is
+	// replaced.
+	if existing != nil && best != existing {
+		aliasIDs = append(aliasIDs, existing.AliasIDs...)
+	}
+
+	republish := false
+	var version int32
+	if best != existing {
+		merged := *best
+		merged.ID = canonicalID
+		merged.ProfileURL = canonicalURL
+		merged.Version = nil
+		merged.AliasIDs = aliasIDs
+		for _, node := range candidates {
+			if node.CreatedAt != 0 &&
+				(merged.CreatedAt == 0 || node.CreatedAt < merged.CreatedAt) {
+				merged.CreatedAt = node.CreatedAt
+			}
+		}
+		// The indexed document of an active node lives under its old ID, so
+		// the canonical node goes through validation again to be indexed.
+		if isActive(best) {
+			merged.Status = constant.NodeStatus.Received
+			republish = true
+		}
+		if err := s.mongoRepo.Add(&merged); err != nil {
+			return err
+		}
+		if merged.Version != nil {
+			version = *merged.Version
+		}
+		result.Migrated++
+	}
+
+	if best == existing {
+		if err := s.mongoRepo.AddAliasIDs(canonicalID, aliasIDs); err != nil {
+			return err
+		}
+	}
+
+	for _, node := range variants {
+		if err := s.mongoRepo.Delete(node); err != nil {
+			return err
+		}
+		if err := s.elasticRepo.DeleteByID(node.ID); err != nil {
+			return err
+		}
+		result.Merged++
+	}
+
+	if republish {
+		err := messaging.Publish(messaging.NodeCreated, messaging.NodeCreatedData{
+			ProfileURL: canonicalURL,
+			Version:    version,
+		})
+		if err != nil {
+			return err
+		}
+		result.Republished++
+	}
+
+	logger.Info(fmt.Sprintf(
+		"Migrated %d node(s) to canonical profile URL %s",
+		len(variants),
+		canonicalURL,
+	))
+
+	return nil
+}
+
+// bestNode picks the node that represents a profile URL best: active nodes
+// win over deleted and failed ones, then the most recently updated one wins.
+func bestNode(nodes []*model.Node) *model.Node {
+	var best *model.Node
+	for _, node := range nodes {
+		if best == nil {
+			best = node
+			continue
+		}
+		if isActive(node) != isActive(best) {
+			if isActive(node) {
+				best = node
+			}
+			continue
+		}
+		if lastUpdated(node) > lastUpdated(best) {
+			best = node
+		}
+	}
+	return best
+}
+
+// isActive reports whether the node is, or is on its way to being, indexed.
+func isActive(node *model.Node) bool {
+	return node.Status != constant.NodeStatus.Deleted &&
+		node.Status != constant.NodeStatus.ValidationFailed
+}
+
+func lastUpdated(node *model.Node) int64 {
+	if node.LastUpdated == nil {
+		return node.CreatedAt
+	}
+	return *node.LastUpdated
+}
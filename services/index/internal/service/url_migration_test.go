@@ -0,0 +1,188 @@
+package service_test
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/cryptoutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/index"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
+)
+
+// fakeNodeRepo keeps the nodes in memory, keyed by ID.
+type fakeNodeRepo struct {
+	mongo.NodeRepository
+	nodes map[string]*model.Node
+}
+
+func (r *fakeNodeRepo) List(afterID string, limit int64) ([]*model.Node, error) {
+	ids := make([]string, 0, len(r.nodes))
+	for id := range r.nodes {
+		if id > afterID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	var nodes []*model.Node
+	for _, id := range ids {
+		if int64(len(nodes)) == limit {
+			break
+		}
+		node := *r.nodes[id]
+		nodes = append(nodes, &node)
+	}
+	return nodes, nil
+}
+
+func (r *fakeNodeRepo) GetByID(nodeID string) (*model.Node, error) {
+	node, ok := r.nodes[nodeID]
+	if !ok {
+		return nil, index.NotFoundError{}
+	}
+	copied := *node
+	return &copied, nil
+}
+
+// Add adds the alias IDs to the ones already recorded, as $addToSet does.
+func (r *fakeNodeRepo) Add(node *model.Node) error {
+	stored := *node
+	stored.AliasIDs = nil
+	if existing, ok := r.nodes[node.ID]; ok {
+		stored.AliasIDs = existing.AliasIDs
+	}
+	r.nodes[node.ID] = &stored
+	return r.AddAliasIDs(node.ID, node.AliasIDs)
+}
+
+func (r *fakeNodeRepo) AddAliasIDs(nodeID string, aliasIDs []string) error {
+	node := r.nodes[nodeID]
+	for _, id := range aliasIDs {
+		if !slices.Contains(node.AliasIDs, id) {
+			node.AliasIDs = append(node.AliasIDs, id)
+		}
+	}
+	return nil
+}
+
+func (r *fakeNodeRepo) Delete(node *model.Node) error {
+	delete(r.nodes, node.ID)
+	return nil
+}
+
+// fakeESRepo records the IDs of the documents deleted.
+type fakeESRepo struct {
+	es.NodeRepository
+	deleted []string
+}
+
+func (r *fakeESRepo) DeleteByID(id string) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func TestURLMigrationMerge(t *testing.T) {
+	canonicalURL := "https://example.org/profile.json"
+	canonicalID := cryptoutil.ComputeSHA256(canonicalURL)
+	variantURL := "https://EXAMPLE.org/profile.json"
+	variantID := cryptoutil.ComputeSHA256(variantURL)
+	older, newer := int64(100), int64(200)
+
+	tests := []struct {
+		name     string
+		existing *model.Node
+		variant  *model.Node
+		expected *model.Node
+	}{
+		{
+			name: "variant replaces the canonical node",
+			existing: &model.Node{
+				ID:          canonicalID,
+				ProfileURL:  canonicalURL,
+				Status:      constant.NodeStatus.Deleted,
+				LastUpdated: &older,
+				AliasIDs:    []string{"previous"},
+			},
+			variant: &model.Node{
+				ID:          variantID,
+				ProfileURL:  variantURL,
+				Status:      constant.NodeStatus.Deleted,
+				LastUpdated: &newer,
+			},
+			expected: &model.Node{
+				ID:          canonicalID,
+				ProfileURL:  canonicalURL,
+				Status:      constant.NodeStatus.Deleted,
+				LastUpdated: &newer,
+				AliasIDs:    []string{"previous", variantID},
+			},
+		},
+		{
+			name: "canonical node is kept",
+			existing: &model.Node{
+				ID:          canonicalID,
+				ProfileURL:  canonicalURL,
+				Status:      constant.NodeStatus.Deleted,
+				LastUpdated: &newer,
+				AliasIDs:    []string{"previous"},
+			},
+			variant: &model.Node{
+				ID:          variantID,
+				ProfileURL:  variantURL,
+				Status:      constant.NodeStatus.Deleted,
+				LastUpdated: &older,
+			},
+			expected: &model.Node{
+				ID:          canonicalID,
+				ProfileURL:  canonicalURL,
+				Status:      constant.NodeStatus.Deleted,
+				LastUpdated: &newer,
+				AliasIDs:    []string{"previous", variantID},
+			},
+		},
+		{
+			name: "no canonical node",
+			variant: &model.Node{
+				ID:          variantID,
+				ProfileURL:  variantURL,
+				Status:      constant.NodeStatus.ValidationFailed,
+				LastUpdated: &older,
+				AliasIDs:    []string{"previous"},
+			},
+			expected: &model.Node{
+				ID:          canonicalID,
+				ProfileURL:  canonicalURL,
+				Status:      constant.NodeStatus.ValidationFailed,
+				LastUpdated: &older,
+				AliasIDs:    []string{variantID, "previous"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mongoRepo := &fakeNodeRepo{nodes: map[string]*model.Node{
+				variantID: tt.variant,
+			}}
+			if tt.existing != nil {
+				mongoRepo.nodes[canonicalID] = tt.existing
+			}
+			esRepo := &fakeESRepo{}
+
+			result, err := service.NewURLMigrationService(mongoRepo, esRepo).
+				Migrate()
+			require.NoError(t, err)
+			require.Equal(t, 1, result.Merged)
+			require.Equal(t, []string{variantID}, esRepo.deleted)
+			require.Equal(t, map[string]*model.Node{
+				canonicalID: tt.expected,
+			}, mongoRepo.nodes)
+		})
+	}
+}
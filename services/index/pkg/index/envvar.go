@@ -41,7 +41,10 @@ func setupElasticsearch() {
 							"status",
 							"tags",
 							"primary_url",
-							"expires"
+							"expires",
+							"quality",
+							"primary_url_status",
+							"primary_url_check"
 						]
 					},
 					"properties": {
@@ -74,7 +77,13 @@ func setupElasticsearch() {
 							"type": "keyword"
 						},
 						"tags": {
-							"type": "text"
+							"type": "text",
+							"fields": {
+								"keyword": {
+									"type": "keyword",
+									"normalizer": "lowercase"
+								}
+							}
 						},
 						"primary_url": {
 							"type": "keyword"
@@ -82,6 +91,29 @@ func setupElasticsearch() {
 						"expires": {
 							"type": "date",
 							"format": "epoch_second"
+						},
+						"quality": {
+							"type": "integer"
+						},
+						"primary_url_status": {
+							"type": "keyword"
+						},
+						"primary_url_check": {
+							"properties": {
+								"status": {
+									"type": "keyword"
+								},
+								"status_code": {
+									"type": "integer"
+								},
+								"last_checked": {
+									"type": "date",
+									"format": "epoch_second"
+								},
+								"failures": {
+									"type": "integer"
+								}
+							}
 						}
 					}
 				}
@@ -103,5 +135,16 @@ func setupElasticsearch() {
 		os.Exit(1)
 	}
 
+	// The tags of the nodes indexed before they had a keyword subfield are
+	// mapped again, so that the vocabulary filters match them.
+	err = elastic.Client.Reindex(constant.ESIndex.Node, &elastic.Query{
+		Query: elastic.NewBoolQuery().
+			Filter(elastic.NewExistQuery("tags")).
+			MustNot(elastic.NewExistQuery("tags.keyword")),
+	})
+	if err != nil {
+		logger.Error("Failed to reindex the tags of the nodes", err)
+	}
+
 	logger.Info("Elasticsearch index created successfully")
 }
@@ -13,9 +13,11 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/tevino/abool/v2"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/core"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/handler"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/middleware/limiter"
@@ -36,6 +38,10 @@ type Service struct {
 	server *http.Server
 	// Node event handler
 	nodeHandler event.NodeHandler
+	// Schema event handler
+	schemaHandler event.SchemaHandler
+	// Batches Elasticsearch writes made by the node event handler
+	bulkProcessor elastic.BulkProcessor
 	// Atomic boolean to manage service state
 	run *abool.AtomicBool
 	// HTTP router for the index service
@@ -57,17 +63,54 @@ func NewService() *Service {
 	svc.setupNATS()
 
 	svc.setupServer()
-	svc.nodeHandler = event.NewNodeHandler(
-		service.NewNodeService(
-			mongo.NewNodeRepository(),
-			es.NewNodeRepository(),
-		),
-	)
+	svc.setupNodeHandler()
 	core.InstallShutdownHandler(svc.Shutdown)
 
 	return svc
 }
 
+// setupNodeHandler initializes the node event handler. Validation events
+// arrive in bursts, so their Elasticsearch writes go through a bulk
+// processor. Nodes whose indexing fails are marked as post_failed.
+func (s *Service) setupNodeHandler() {
+	var nodeService service.NodeService
+
+	processor, err := elastic.Client.NewBulkProcessor(elastic.BulkOptions{
+		Name:          "index-node-events",
+		Workers:       config.Values.ES.BulkWorkers,
+		BulkActions:   config.Values.ES.BulkActions,
+		FlushInterval: config.Values.ES.BulkFlushInterval,
+		OnFailure: func(failure elastic.BulkItemFailure) {
+			if failure.Action != elastic.BulkActionIndex {
+				return
+			}
+			if err := nodeService.SetNodePostFailed(failure.ID); err != nil {
+				logger.Error(
+					"Failed to set node post_failed after bulk failure",
+					err,
+					zap.String("nodeID", failure.ID),
+				)
+			}
+		},
+	})
+	if err != nil {
+		s.panic("Failed to start Elasticsearch bulk processor", err)
+	}
+	s.bulkProcessor = processor
+
+	nodeService = service.NewNodeService(
+		mongo.NewNodeRepository(),
+		es.NewBulkNodeRepository(processor),
+		mongo.NewRevalidationRepository(),
+	)
+	s.nodeHandler = event.NewNodeHandler(nodeService)
+	s.schemaHandler = event.NewSchemaHandler(service.NewRevalidationService(
+		mongo.NewNodeRepository(),
+		es.NewNodeRepository(),
+		mongo.NewRevalidationRepository(),
+	))
+}
+
 // setupNATS initializes Nats service.
 func (s *Service) setupNATS() {
 	err := natsclient.Initialize(config.Values.Nats.URL)
@@ -153,15 +196,23 @@ func (s *Service) middlewares() []gin.HandlerFunc {
 
 // registerRoutes sets up the routes for the HTTP server.
 func (s *Service) registerRoutes() {
+	revalidationHandler := rest.NewRevalidationHandler(
+		service.NewRevalidationService(
+			mongo.NewNodeRepository(),
+			es.NewNodeRepository(),
+			mongo.NewRevalidationRepository(),
+		),
+	)
 	nodeHandler := rest.NewNodeHandler(
 		service.NewNodeService(
 			mongo.NewNodeRepository(),
 			es.NewNodeRepository(),
+			mongo.NewRevalidationRepository(),
 		),
 	)
 
 	s.setupV1Routes()
-	s.setupV2Routes(nodeHandler)
+	s.setupV2Routes(nodeHandler, revalidationHandler)
 }
 
 // setupV1Routes configures routes for API version 1.
@@ -171,7 +222,10 @@ func (s *Service) setupV1Routes() {
 }
 
 // setupV2Routes configures routes for API version 2.
-func (s *Service) setupV2Routes(nodeHandler rest.NodeHandler) {
+func (s *Service) setupV2Routes(
+	nodeHandler rest.NodeHandler,
+	revalidationHandler rest.RevalidationHandler,
+) {
 	v2 := s.router.Group("/v2")
 	v2.GET("/ping", handler.PingHandler)
 	v2.PUT(
@@ -184,12 +238,18 @@ func (s *Service) setupV2Routes(nodeHandler rest.NodeHandler) {
 	v2.POST("/nodes", nodeHandler.Add)
 	v2.GET("/nodes/:nodeID", nodeHandler.Get)
 	v2.GET("/nodes", nodeHandler.Search)
+	v2.GET("/nodes-lookup", nodeHandler.Lookup)
 	v2.DELETE("/nodes", nodeHandler.Delete)
 	v2.DELETE("/nodes/:nodeID", nodeHandler.Delete)
 	v2.POST("/validate", nodeHandler.Validate)
 	v2.POST("/nodes-sync", nodeHandler.AddSync)
 	v2.POST("/export", nodeHandler.Export)
 	v2.GET("/get-nodes", nodeHandler.GetNodes)
+	v2.GET("/dead-links", nodeHandler.DeadLinks)
+
+	// Revalidation-related routes
+	v2.GET("/revalidations", revalidationHandler.List)
+	v2.GET("/revalidations/:revalidationID", revalidationHandler.Get)
 }
 
 // panic performs a cleanup and then emits the supplied message as the panic value.
@@ -210,6 +270,19 @@ func (s *Service) Run() {
 		err != http.ErrServerClosed {
 		s.panic("Error when trying to listen events", err)
 	}
+	if err := s.nodeHandler.Gone(); err != nil &&
+		err != http.ErrServerClosed {
+		s.panic("Error when trying to listen events", err)
+	}
+	if err := s.nodeHandler.LinkChecked(); err != nil &&
+		err != http.ErrServerClosed {
+		s.panic("Error when trying to listen events", err)
+	}
+	if err := s.schemaHandler.Updated(); err != nil &&
+		err != http.ErrServerClosed {
+		s.panic("Error when trying to listen events", err)
+	}
+	s.schemaHandler.Resume()
 	if err := s.server.ListenAndServe(); err != nil &&
 		err != http.ErrServerClosed {
 		s.panic("Error when trying to start the server", err)
@@ -264,6 +337,15 @@ func (s *Service) cleanup() {
 		// Shutdown the context.
 		s.shutdownCancelCtx()
 
+		// Send the queued Elasticsearch writes before MongoDB goes away, so
+		// bulk failures can still be recorded.
+		if s.bulkProcessor != nil {
+			if err := s.bulkProcessor.Close(); err != nil {
+				logger.Error("Error closing Elasticsearch bulk processor", err)
+				errOccurred = true
+			}
+		}
+
 		// Disconnect from MongoDB.
 		mongodb.Client.Disconnect()
 
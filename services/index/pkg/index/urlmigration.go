@@ -0,0 +1,87 @@
+package index
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	mongodb "github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/natsclient"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/es"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/index/internal/service"
+)
+
+// URLMigration re-keys nodes stored under non-canonical profile URLs and
+// merges the duplicates they created.
+type URLMigration struct {
+	// Ensures cleanup is run only once.
+	runCleanup sync.Once
+}
+
+// NewURLMigration connects to MongoDB, Elasticsearch and NATS and returns a
+// URLMigration.
+func NewURLMigration() *URLMigration {
+	uri := mongodb.GetURI(
+		config.Values.Mongo.USERNAME,
+		config.Values.Mongo.PASSWORD,
+		config.Values.Mongo.HOST,
+	)
+	if err := mongodb.NewClient(uri, config.Values.Mongo.DBName); err != nil {
+		logger.Error("error when trying to connect to MongoDB", err)
+		os.Exit(1)
+	}
+
+	if err := mongodb.Client.Ping(); err != nil {
+		logger.Error("error when trying to ping the MongoDB", err)
+		os.Exit(1)
+	}
+
+	if err := elastic.NewClient(config.Values.ES.URL); err != nil {
+		logger.Error("Failed to connect to Elasticsearch", err)
+		os.Exit(1)
+	}
+
+	if err := natsclient.Initialize(config.Values.Nats.URL); err != nil {
+		logger.Error("Failed to create Nats client", err)
+		os.Exit(1)
+	}
+
+	return &URLMigration{}
+}
+
+// Run executes the migration.
+func (m *URLMigration) Run() error {
+	defer m.cleanup()
+
+	result, err := service.NewURLMigrationService(
+		mongo.NewNodeRepository(),
+		es.NewNodeRepository(),
+	).Migrate()
+	if result != nil {
+		logger.Info(fmt.Sprintf(
+			"Profile URL migration: scanned %d, invalid %d, migrated %d, "+
+				"merged %d, republished %d",
+			result.Scanned,
+			result.Invalid,
+			result.Migrated,
+			result.Merged,
+			result.Republished,
+		))
+	}
+	return err
+}
+
+// cleanup disconnects MongoDB and NATS clients.
+func (m *URLMigration) cleanup() {
+	m.runCleanup.Do(func() {
+		mongodb.Client.Disconnect()
+
+		if err := natsclient.GetInstance().Disconnect(); err != nil {
+			logger.Error("Error disconnecting from NATS: %v", err)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
+)
+
+// FieldHandler defines the actions that can be performed with a Field.
+type FieldHandler interface {
+	Get(c *gin.Context)
+	Search(c *gin.Context)
+}
+
+type fieldHandler struct {
+	svc service.FieldService
+}
+
+// NewFieldHandler returns a new fieldHandler with the provided service.
+func NewFieldHandler(svc service.FieldService) FieldHandler {
+	return &fieldHandler{
+		svc: svc,
+	}
+}
+
+// Get fetches a field with a specific name, with its full definition and the
+// schemas using it.
+func (handler *fieldHandler) Get(c *gin.Context) {
+	field, err := handler.svc.Get(c.Param("fieldName"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	res := jsonapi.Response(field.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+// Search fetches all fields, or only the fields used by the schema given in
+// the schema query parameter.
+func (handler *fieldHandler) Search(c *gin.Context) {
+	fields, err := handler.svc.Search(c.Query("schema"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	res := jsonapi.Response(fields.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,113 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/controller/rest"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+type MockFieldService struct {
+	field *model.SingleField
+	err   error
+}
+
+func (s *MockFieldService) Get(_ string) (*model.SingleField, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.field, nil
+}
+
+func (s *MockFieldService) Search(_ string) (*model.Fields, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.Fields{&s.field.Field}, nil
+}
+
+func TestFieldHandler_Get(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	field := &model.SingleField{
+		Field: model.Field{
+			Name:   "name",
+			Title:  "Name",
+			Type:   "string",
+			UsedBy: []string{"people_schema-v0.1.0"},
+		},
+		FullField: bson.D{
+			{Key: "title", Value: "Name"},
+			{Key: "type", Value: "string"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mockSvc        *MockFieldService
+		expectedStatus int
+		expectedData   map[string]interface{}
+	}{
+		{
+			name:           "success",
+			mockSvc:        &MockFieldService{field: field},
+			expectedStatus: http.StatusOK,
+			expectedData: map[string]interface{}{
+				"name":        "name",
+				"title":       "Name",
+				"description": "",
+				"type":        "string",
+				"used_by":     []interface{}{"people_schema-v0.1.0"},
+				"definition": map[string]interface{}{
+					"title": "Name",
+					"type":  "string",
+				},
+			},
+		},
+		{
+			name: "field not found",
+			mockSvc: &MockFieldService{
+				err: library.FieldNotFoundError{FieldName: "name"},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "database error",
+			mockSvc: &MockFieldService{
+				err: library.DatabaseError{},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewFieldHandler(tt.mockSvc)
+
+			r := gin.Default()
+			r.GET("/fields/:fieldName", handler.Get)
+
+			req, _ := http.NewRequest(http.MethodGet, "/fields/name", nil)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+			if tt.expectedData != nil {
+				var body struct {
+					Data map[string]interface{} `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+				require.Equal(t, tt.expectedData, body.Data)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemacodegen"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
 )
@@ -16,6 +17,10 @@ import (
 type SchemaHandler interface {
 	Get(c *gin.Context)
 	Search(c *gin.Context)
+	Versions(c *gin.Context)
+	Latest(c *gin.Context)
+	Diff(c *gin.Context)
+	Generate(c *gin.Context)
 }
 
 type schemaHandler struct {
@@ -29,7 +34,9 @@ func NewSchemaHandler(svc service.SchemaService) SchemaHandler {
 	}
 }
 
-// Get fetches a schema with a specific name.
+// Get fetches a schema with a specific name. A major version range, e.g.
+// organizations_schema-v1, fetches the newest compatible version. The at
+// query parameter fetches the schema as it was at a commit or date.
 func (handler *schemaHandler) Get(c *gin.Context) {
 	schemaName, found := c.Params.Get("schemaName")
 	// This normally won't happen, as if the user doesn't provide the name,
@@ -46,44 +53,181 @@ func (handler *schemaHandler) Get(c *gin.Context) {
 		return
 	}
 
-	schema, err := handler.svc.Get(schemaName)
+	resolved, err := handler.svc.Resolve(schemaName)
 	if err != nil {
-		var schemaNotFoundError library.SchemaNotFoundError
-		var dbError library.DatabaseError
-
-		switch {
-		case errors.As(err, &schemaNotFoundError):
-			errors := jsonapi.NewError(
-				[]string{"Schema Not Found"},
-				[]string{schemaNotFoundError.Error()},
-				nil,
-				[]int{http.StatusNotFound},
-			)
-			res := jsonapi.Response(nil, errors, nil, nil)
-			c.JSON(http.StatusNotFound, res)
-		case errors.As(err, &dbError):
-			errors := jsonapi.NewError(
-				[]string{"Database Error"},
-				[]string{dbError.Error()},
-				nil,
-				[]int{http.StatusInternalServerError},
-			)
-			res := jsonapi.Response(nil, errors, nil, nil)
-			c.JSON(http.StatusInternalServerError, res)
-		default:
-			errors := jsonapi.NewError(
-				[]string{"Unknown Error"},
-				[]string{"An unexpected error has occurred."},
-				nil,
-				[]int{http.StatusInternalServerError},
-			)
-			res := jsonapi.Response(nil, errors, nil, nil)
-			c.JSON(http.StatusInternalServerError, res)
-		}
+		respondWithError(c, err)
 		return
 	}
+	handler.respondWithSchema(c, resolved, schemaName)
+}
+
+// Latest fetches the newest version of a schema, given by its name without
+// the version or by a major version range.
+func (handler *schemaHandler) Latest(c *gin.Context) {
+	schemaName, err := handler.svc.Latest(c.Param("schemaName"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	handler.respondWithSchema(c, schemaName, "")
+}
+
+// Versions lists the versions of a schema, newest first, given by its name
+// without the version or by a major version range.
+func (handler *schemaHandler) Versions(c *gin.Context) {
+	versions, err := handler.svc.Versions(c.Param("schemaName"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	res := jsonapi.Response(versions.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+// Diff reports the changes to a schema from the version given in the from
+// query parameter, or from its previous version.
+func (handler *schemaHandler) Diff(c *gin.Context) {
+	report, err := handler.svc.Diff(c.Param("schemaName"), c.Query("from"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	res := jsonapi.Response(report, nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+// generatedContentTypes are the content types of the artifacts generated for
+// each target.
+var generatedContentTypes = map[string]string{
+	schemacodegen.TargetGo:         "text/x-go; charset=utf-8",
+	schemacodegen.TargetTypeScript: "application/typescript; charset=utf-8",
+	schemacodegen.TargetTemplate:   "application/json; charset=utf-8",
+	schemacodegen.TargetCSV:        "text/csv; charset=utf-8",
+}
+
+// Generate responds with code or a template generated from a schema: Go
+// structs, TypeScript interfaces, an empty profile or the header of a CSV
+// file for the dataproxy batch importer.
+func (handler *schemaHandler) Generate(c *gin.Context) {
+	target := c.Param("target")
+	data, err := handler.svc.Generate(c.Param("schemaName"), target)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, generatedContentTypes[target], data)
+}
+
+// respondWithSchema responds with the schema of the given name, as it was at
+// the commit or date given in the at query parameter, if any. The
+// Content-Location header tells which schema was served when it differs from
+// the requested one, and the ETag header identifies its revision.
+func (handler *schemaHandler) respondWithSchema(
+	c *gin.Context,
+	schemaName string,
+	requested string,
+) {
+	schema, err := handler.svc.Get(schemaName, c.Query("at"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if schemaName != requested {
+		c.Header("Content-Location", "/v2/schemas/"+schemaName)
+	}
+	if schema.Revision != "" {
+		c.Header("ETag", `"`+schema.Revision+`"`)
+	}
+	c.JSON(http.StatusOK, schema.ToMap())
+}
+
+// respondWithError responds with the error of a schema or field operation.
+func respondWithError(c *gin.Context, err error) {
+	var schemaNotFoundError library.SchemaNotFoundError
+	var previousVersionNotFoundError library.PreviousVersionNotFoundError
+	var revisionNotFoundError library.RevisionNotFoundError
+	var targetNotFoundError library.TargetNotFoundError
+	var fieldNotFoundError library.FieldNotFoundError
+	var vocabularyNotFoundError library.VocabularyNotFoundError
+	var dbError library.DatabaseError
 
-	c.JSON(http.StatusOK, schema)
+	switch {
+	case errors.As(err, &schemaNotFoundError):
+		errors := jsonapi.NewError(
+			[]string{"Schema Not Found"},
+			[]string{schemaNotFoundError.Error()},
+			nil,
+			[]int{http.StatusNotFound},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(http.StatusNotFound, res)
+	case errors.As(err, &previousVersionNotFoundError):
+		errors := jsonapi.NewError(
+			[]string{"Previous Version Not Found"},
+			[]string{previousVersionNotFoundError.Error()},
+			nil,
+			[]int{http.StatusNotFound},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(http.StatusNotFound, res)
+	case errors.As(err, &revisionNotFoundError):
+		errors := jsonapi.NewError(
+			[]string{"Schema Revision Not Found"},
+			[]string{revisionNotFoundError.Error()},
+			nil,
+			[]int{http.StatusNotFound},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(http.StatusNotFound, res)
+	case errors.As(err, &targetNotFoundError):
+		errors := jsonapi.NewError(
+			[]string{"Target Not Found"},
+			[]string{targetNotFoundError.Error()},
+			nil,
+			[]int{http.StatusNotFound},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(http.StatusNotFound, res)
+	case errors.As(err, &fieldNotFoundError):
+		errors := jsonapi.NewError(
+			[]string{"Field Not Found"},
+			[]string{fieldNotFoundError.Error()},
+			nil,
+			[]int{http.StatusNotFound},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(http.StatusNotFound, res)
+	case errors.As(err, &vocabularyNotFoundError):
+		errors := jsonapi.NewError(
+			[]string{"Vocabulary Not Found"},
+			[]string{vocabularyNotFoundError.Error()},
+			nil,
+			[]int{http.StatusNotFound},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(http.StatusNotFound, res)
+	case errors.As(err, &dbError):
+		errors := jsonapi.NewError(
+			[]string{"Database Error"},
+			[]string{dbError.Error()},
+			nil,
+			[]int{http.StatusInternalServerError},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(http.StatusInternalServerError, res)
+	default:
+		errors := jsonapi.NewError(
+			[]string{"Unknown Error"},
+			[]string{"An unexpected error has occurred."},
+			nil,
+			[]int{http.StatusInternalServerError},
+		)
+		res := jsonapi.Response(nil, errors, nil, nil)
+		c.JSON(http.StatusInternalServerError, res)
+	}
 }
 
 // Search fetches all schemas that match the search criteria.
@@ -9,21 +9,30 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemadiff"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/controller/rest"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
 )
 
 type MockSchemaService struct {
-	schema *model.Schema
-	err    error
+	schema   *model.Schema
+	revision string
+	err      error
+	// at is the at parameter Get was called with.
+	at string
 }
 
-func (s *MockSchemaService) Get(_ string) (interface{}, error) {
+func (s *MockSchemaService) Get(_ string, at string) (*model.SingleSchema, error) {
+	s.at = at
 	if s.err != nil {
 		return nil, s.err
 	}
-	return s.schema, nil
+	return &model.SingleSchema{
+		Name:        s.schema.Name,
+		Description: s.schema.Description,
+		Revision:    s.revision,
+	}, nil
 }
 
 func (s *MockSchemaService) Search() (*model.Schemas, error) {
@@ -46,6 +55,47 @@ func (s *MockSchemaService) Search() (*model.Schemas, error) {
 	}, nil
 }
 
+func (s *MockSchemaService) Resolve(schemaName string) (string, error) {
+	return schemaName, nil
+}
+
+func (s *MockSchemaService) Versions(_ string) (*model.Schemas, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.Schemas{
+		&model.Schema{Name: "test_schema-v1.1.0", Version: "1.1.0"},
+		&model.Schema{Name: "test_schema-v1.0.0", Version: "1.0.0"},
+	}, nil
+}
+
+func (s *MockSchemaService) Latest(_ string) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	return "test_schema-v1.1.0", nil
+}
+
+func (s *MockSchemaService) Diff(
+	schemaName string,
+	from string,
+) (*schemadiff.Report, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &schemadiff.Report{From: from, To: schemaName}, nil
+}
+
+func (s *MockSchemaService) Generate(
+	schemaName string,
+	target string,
+) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []byte(target + ":" + schemaName), nil
+}
+
 func TestSchemaHandler_Get(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -115,6 +165,83 @@ func TestSchemaHandler_Get(t *testing.T) {
 	}
 }
 
+func TestSchemaHandler_GetAt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSvc        *MockSchemaService
+		expectedStatus int
+		expectedAt     string
+		expectedETag   string
+	}{
+		{
+			name:  "current revision",
+			query: "",
+			mockSvc: &MockSchemaService{
+				schema:   &model.Schema{Name: "test_schema-v1.0.0"},
+				revision: "abc123",
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"abc123"`,
+		},
+		{
+			name:  "revision at a commit",
+			query: "?at=0a1b2c3",
+			mockSvc: &MockSchemaService{
+				schema:   &model.Schema{Name: "test_schema-v1.0.0"},
+				revision: "def456",
+			},
+			expectedStatus: http.StatusOK,
+			expectedAt:     "0a1b2c3",
+			expectedETag:   `"def456"`,
+		},
+		{
+			name:  "schema without revision",
+			query: "",
+			mockSvc: &MockSchemaService{
+				schema: &model.Schema{Name: "test_schema-v1.0.0"},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "revision not found",
+			query: "?at=2020-01-01",
+			mockSvc: &MockSchemaService{
+				err: library.RevisionNotFoundError{
+					SchemaName: "test_schema-v1.0.0",
+					At:         "2020-01-01",
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedAt:     "2020-01-01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewSchemaHandler(tt.mockSvc)
+
+			r := gin.Default()
+			r.GET("/schemas/:schemaName", handler.Get)
+
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"/schemas/test_schema-v1.0.0"+tt.query,
+				nil,
+			)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+			require.Equal(t, tt.expectedAt, tt.mockSvc.at)
+			require.Equal(t, tt.expectedETag, resp.Header().Get("ETag"))
+		})
+	}
+}
+
 func TestSchemaHandler_Search(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -160,3 +287,165 @@ func TestSchemaHandler_Search(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaHandler_Latest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name                    string
+		mockSvc                 *MockSchemaService
+		expectedStatus          int
+		expectedContentLocation string
+	}{
+		{
+			name:                    "success",
+			mockSvc:                 &MockSchemaService{schema: &model.Schema{}},
+			expectedStatus:          http.StatusOK,
+			expectedContentLocation: "/v2/schemas/test_schema-v1.1.0",
+		},
+		{
+			name: "schema not found",
+			mockSvc: &MockSchemaService{
+				err: library.SchemaNotFoundError{SchemaName: "test_schema"},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewSchemaHandler(tt.mockSvc)
+
+			r := gin.Default()
+			r.GET("/schemas/:schemaName/latest", handler.Latest)
+
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"/schemas/test_schema/latest",
+				nil,
+			)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+			require.Equal(
+				t,
+				tt.expectedContentLocation,
+				resp.Header().Get("Content-Location"),
+			)
+		})
+	}
+}
+
+func TestSchemaHandler_Diff(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		mockSvc        *MockSchemaService
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			mockSvc:        &MockSchemaService{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "no previous version",
+			mockSvc: &MockSchemaService{
+				err: library.PreviousVersionNotFoundError{
+					SchemaName: "test_schema-v1.0.0",
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewSchemaHandler(tt.mockSvc)
+
+			r := gin.Default()
+			r.GET("/schemas/:schemaName/diff", handler.Diff)
+
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"/schemas/test_schema-v1.1.0/diff?from=test_schema-v1.0.0",
+				nil,
+			)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+		})
+	}
+}
+
+func TestSchemaHandler_Generate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name                string
+		target              string
+		mockSvc             *MockSchemaService
+		expectedStatus      int
+		expectedContentType string
+	}{
+		{
+			name:                "typescript",
+			target:              "typescript",
+			mockSvc:             &MockSchemaService{},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/typescript; charset=utf-8",
+		},
+		{
+			name:                "csv",
+			target:              "csv",
+			mockSvc:             &MockSchemaService{},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+		},
+		{
+			name:   "unknown target",
+			target: "python",
+			mockSvc: &MockSchemaService{
+				err: library.TargetNotFoundError{Target: "python"},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "schema not found",
+			target: "go",
+			mockSvc: &MockSchemaService{
+				err: library.SchemaNotFoundError{SchemaName: "test_schema-v1"},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewSchemaHandler(tt.mockSvc)
+
+			r := gin.Default()
+			r.GET("/schemas/:schemaName/codegen/:target", handler.Generate)
+
+			req, _ := http.NewRequest(
+				http.MethodGet,
+				"/schemas/test_schema-v1/codegen/"+tt.target,
+				nil,
+			)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+			if tt.expectedStatus == http.StatusOK {
+				require.Equal(t, tt.expectedContentType, resp.Header().Get("Content-Type"))
+				require.Equal(t, tt.target+":test_schema-v1", resp.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
+)
+
+// VocabularyHandler defines the actions that can be performed with a
+// Vocabulary.
+type VocabularyHandler interface {
+	Get(c *gin.Context)
+	Search(c *gin.Context)
+}
+
+type vocabularyHandler struct {
+	svc service.VocabularyService
+}
+
+// NewVocabularyHandler returns a new vocabularyHandler with the provided
+// service.
+func NewVocabularyHandler(svc service.VocabularyService) VocabularyHandler {
+	return &vocabularyHandler{
+		svc: svc,
+	}
+}
+
+// Get fetches a vocabulary with a specific name or major version range, with
+// its terms and the schemas using it.
+func (handler *vocabularyHandler) Get(c *gin.Context) {
+	vocabulary, err := handler.svc.Get(c.Param("vocabularyName"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	res := jsonapi.Response(vocabulary.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
+
+// Search fetches all vocabularies, or only the vocabularies used by the
+// schema given in the schema query parameter.
+func (handler *vocabularyHandler) Search(c *gin.Context) {
+	vocabularies, err := handler.svc.Search(c.Query("schema"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	res := jsonapi.Response(vocabularies.Marshall(), nil, nil, nil)
+	c.JSON(http.StatusOK, res)
+}
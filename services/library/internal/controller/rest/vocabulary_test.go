@@ -0,0 +1,119 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/controller/rest"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+type MockVocabularyService struct {
+	vocabulary *model.Vocabulary
+	err        error
+}
+
+func (s *MockVocabularyService) Get(_ string) (*model.Vocabulary, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.vocabulary, nil
+}
+
+func (s *MockVocabularyService) Search(_ string) (*model.Vocabularies, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &model.Vocabularies{s.vocabulary}, nil
+}
+
+func TestVocabularyHandler_Get(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	vocabulary := &model.Vocabulary{
+		Name:  "categories-v1.0.0",
+		Title: "Categories",
+		Terms: []model.Term{
+			{
+				ID:       "non-profit",
+				Labels:   map[string]string{"en": "Non-profit"},
+				Narrower: []string{"cooperative"},
+			},
+			{ID: "cooperative", Broader: []string{"non-profit"}},
+		},
+		UsedBy: []string{"organizations_schema-v1.0.0"},
+	}
+
+	tests := []struct {
+		name           string
+		mockSvc        *MockVocabularyService
+		expectedStatus int
+		expectedData   map[string]interface{}
+	}{
+		{
+			name:           "success",
+			mockSvc:        &MockVocabularyService{vocabulary: vocabulary},
+			expectedStatus: http.StatusOK,
+			expectedData: map[string]interface{}{
+				"name":        "categories-v1.0.0",
+				"title":       "Categories",
+				"description": "",
+				"terms": []interface{}{
+					map[string]interface{}{
+						"id":       "non-profit",
+						"labels":   map[string]interface{}{"en": "Non-profit"},
+						"narrower": []interface{}{"cooperative"},
+					},
+					map[string]interface{}{
+						"id":      "cooperative",
+						"broader": []interface{}{"non-profit"},
+					},
+				},
+				"used_by": []interface{}{"organizations_schema-v1.0.0"},
+			},
+		},
+		{
+			name: "vocabulary not found",
+			mockSvc: &MockVocabularyService{
+				err: library.VocabularyNotFoundError{VocabularyName: "categories-v1"},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "database error",
+			mockSvc: &MockVocabularyService{
+				err: library.DatabaseError{},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := rest.NewVocabularyHandler(tt.mockSvc)
+
+			r := gin.Default()
+			r.GET("/vocabularies/:vocabularyName", handler.Get)
+
+			req, _ := http.NewRequest(http.MethodGet, "/vocabularies/categories-v1", nil)
+			resp := httptest.NewRecorder()
+
+			r.ServeHTTP(resp, req)
+
+			require.Equal(t, tt.expectedStatus, resp.Code)
+			if tt.expectedData != nil {
+				var body struct {
+					Data map[string]interface{} `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+				require.Equal(t, tt.expectedData, body.Data)
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package library
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemacodegen"
 )
 
 // SchemaNotFoundError represents an error that occurs when a specified schema
@@ -24,6 +27,79 @@ func (e SchemaNotFoundError) Unwrap() error {
 	return e.Err
 }
 
+// PreviousVersionNotFoundError represents an error that occurs when a schema
+// has no previous version to compare it with.
+type PreviousVersionNotFoundError struct {
+	SchemaName string
+}
+
+// Error conforms to go conventions.
+func (e PreviousVersionNotFoundError) Error() string {
+	return fmt.Sprintf(
+		"the following schema has no previous version in the Library: %s",
+		e.SchemaName,
+	)
+}
+
+// RevisionNotFoundError represents an error that occurs when a schema has no
+// revision at the given commit or date.
+type RevisionNotFoundError struct {
+	SchemaName string
+	At         string
+}
+
+// Error conforms to go conventions.
+func (e RevisionNotFoundError) Error() string {
+	return fmt.Sprintf(
+		"the following schema has no revision in the Library at %s: %s",
+		e.At,
+		e.SchemaName,
+	)
+}
+
+// TargetNotFoundError represents an error that occurs when artifacts can't
+// be generated from schemas for a target.
+type TargetNotFoundError struct {
+	Target string
+}
+
+// Error conforms to go conventions.
+func (e TargetNotFoundError) Error() string {
+	return fmt.Sprintf(
+		"the Library can't generate %s from schemas, the targets are: %s",
+		e.Target,
+		strings.Join(schemacodegen.Targets, ", "),
+	)
+}
+
+// FieldNotFoundError represents an error that occurs when a specified field
+// is not found in the library.
+type FieldNotFoundError struct {
+	FieldName string
+}
+
+// Error conforms to go conventions.
+func (e FieldNotFoundError) Error() string {
+	return fmt.Sprintf(
+		"could not locate the following field in the Library: %s",
+		e.FieldName,
+	)
+}
+
+// VocabularyNotFoundError represents an error that occurs when a specified
+// vocabulary is not found in the library.
+type VocabularyNotFoundError struct {
+	VocabularyName string
+}
+
+// Error conforms to go conventions.
+func (e VocabularyNotFoundError) Error() string {
+	return fmt.Sprintf(
+		"could not locate the following vocabulary in the Library: %s",
+		e.VocabularyName,
+	)
+}
+
 // DatabaseError represents an error that occurs during a database operation.
 type DatabaseError struct {
 	Err error
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"github.com/iancoleman/orderedmap"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Field defines the structure for a shared field definition.
+type Field struct {
+	Name        string `json:"name"        bson:"name,omitempty"`
+	Title       string `json:"title"       bson:"title,omitempty"`
+	Description string `json:"description" bson:"description,omitempty"`
+	Type        string `json:"type"        bson:"type,omitempty"`
+	// UsedBy lists the names of the schemas using the field.
+	UsedBy []string `json:"used_by" bson:"used_by"`
+}
+
+// Marshall transforms the Field instance to an interface.
+func (field *Field) Marshall() interface{} {
+	return field
+}
+
+// Fields is a slice of Field instances.
+type Fields []*Field
+
+func (fields Fields) Marshall() interface{} {
+	data := make([]interface{}, len(fields))
+	for index, field := range fields {
+		data[index] = field.Marshall()
+	}
+	return data
+}
+
+// SingleField represents a field with its full definition.
+type SingleField struct {
+	Field     `bson:",inline"`
+	FullField bson.D `bson:"full_field"`
+}
+
+// Marshall transforms the SingleField instance to an interface, with the
+// full definition as an ordered map.
+func (field *SingleField) Marshall() interface{} {
+	definition := &SingleSchema{FullSchema: field.FullField}
+	return struct {
+		*Field
+		Definition *orderedmap.OrderedMap `json:"definition"`
+	}{
+		Field:      &field.Field,
+		Definition: definition.ToMap(),
+	}
+}
@@ -3,6 +3,8 @@ package model
 import (
 	"github.com/iancoleman/orderedmap"
 	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemadiff"
 )
 
 // Schema defines the structure for a schema.
@@ -11,6 +13,9 @@ type Schema struct {
 	Description string `json:"description" bson:"description,omitempty"`
 	Name        string `json:"name"        bson:"name,omitempty"`
 	URL         string `json:"url"         bson:"url,omitempty"`
+	// Version is the version part of the name, set when listing the versions
+	// of a schema.
+	Version string `json:"version,omitempty" bson:"-"`
 }
 
 // Marshall transforms the Schema instance to an interface.
@@ -31,8 +36,40 @@ func (schemas Schemas) Marshall() interface{} {
 
 // SingleSchema represents a schema with its description and full schema.
 type SingleSchema struct {
+	Name        string `bson:"name"`
 	Description string `bson:"description"`
 	FullSchema  bson.D `bson:"full_schema"`
+	// Revision identifies the content of the full schema.
+	Revision string `bson:"revision"`
+	// Compatibility reports the changes from the previous version.
+	Compatibility *schemadiff.Report `bson:"compatibility"`
+}
+
+// SchemaRevision is a content a schema had, along with the loads of the
+// sources that changed the schema to it.
+type SchemaRevision struct {
+	Name       string       `bson:"name"`
+	Revision   string       `bson:"revision"`
+	FullSchema bson.D       `bson:"full_schema"`
+	Loads      []SchemaLoad `bson:"loads"`
+}
+
+// SchemaLoad records a load of the sources of the schemas.
+type SchemaLoad struct {
+	// Commit is the version of the source of the schema, e.g. a commit SHA.
+	Commit string `bson:"commit"`
+	// Version is the version of all the sources.
+	Version  string `bson:"version"`
+	LoadedAt int64  `bson:"loaded_at"`
+}
+
+// SourcesLoad records an update of the schemas from the sources.
+type SourcesLoad struct {
+	// Version is the version of all the sources.
+	Version string `bson:"version"`
+	// Commits are the versions of each source, e.g. commit SHAs.
+	Commits  []string `bson:"commits"`
+	LoadedAt int64    `bson:"loaded_at"`
 }
 
 // ToMap transforms the full schema into an ordered map.
@@ -0,0 +1,39 @@
+package model
+
+// Vocabulary defines the structure for a controlled vocabulary, whose terms
+// are the values the properties referencing it allow.
+type Vocabulary struct {
+	Name        string `json:"name"        bson:"name,omitempty"`
+	Title       string `json:"title"       bson:"title,omitempty"`
+	Description string `json:"description" bson:"description,omitempty"`
+	// Terms are left out when listing the vocabularies.
+	Terms []Term `json:"terms,omitempty" bson:"terms,omitempty"`
+	// UsedBy lists the names of the schemas using the vocabulary.
+	UsedBy []string `json:"used_by" bson:"used_by"`
+}
+
+// Term is a term of a vocabulary, along with the IDs of its broader and
+// narrower terms.
+type Term struct {
+	ID string `json:"id" bson:"id"`
+	// Labels maps language tags, e.g. "en", to the labels of the term.
+	Labels   map[string]string `json:"labels,omitempty"   bson:"labels,omitempty"`
+	Broader  []string          `json:"broader,omitempty"  bson:"broader,omitempty"`
+	Narrower []string          `json:"narrower,omitempty" bson:"narrower,omitempty"`
+}
+
+// Marshall transforms the Vocabulary instance to an interface.
+func (vocabulary *Vocabulary) Marshall() interface{} {
+	return vocabulary
+}
+
+// Vocabularies is a slice of Vocabulary instances.
+type Vocabularies []*Vocabulary
+
+func (vocabularies Vocabularies) Marshall() interface{} {
+	data := make([]interface{}, len(vocabularies))
+	for index, vocabulary := range vocabularies {
+		data[index] = vocabulary.Marshall()
+	}
+	return data
+}
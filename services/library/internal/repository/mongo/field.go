@@ -0,0 +1,74 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+// FieldRepo defines the methods a FieldRepo can perform.
+type FieldRepo interface {
+	Get(fieldName string) (*model.SingleField, error)
+	Search() (*model.Fields, error)
+}
+
+type fieldRepo struct{}
+
+// NewFieldRepo returns a new field repository.
+func NewFieldRepo() FieldRepo {
+	return &fieldRepo{}
+}
+
+// Get retrieves a specific field from the DB based on its name.
+func (r *fieldRepo) Get(fieldName string) (*model.SingleField, error) {
+	filter := bson.M{"name": fieldName}
+	result := mongo.Client.FindOne(constant.MongoIndex.Field, filter)
+
+	var field model.SingleField
+	err := result.Decode(&field)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, library.FieldNotFoundError{FieldName: fieldName}
+		}
+		return nil, library.DatabaseError{Err: err}
+	}
+
+	return &field, nil
+}
+
+// Search retrieves all fields from the DB, sorted by name, without their
+// full definitions.
+func (r *fieldRepo) Search() (*model.Fields, error) {
+	filter := bson.M{}
+	opts := options.Find().
+		SetSort(bson.M{"name": 1}).
+		SetProjection(bson.M{"full_field": 0})
+
+	cur, err := mongo.Client.Find(constant.MongoIndex.Field, filter, opts)
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	defer cur.Close(context.TODO())
+
+	fields := model.Fields{}
+	for cur.Next(context.TODO()) {
+		var field model.Field
+		err := cur.Decode(&field)
+		if err != nil {
+			return nil, library.DatabaseError{Err: err}
+		}
+		fields = append(fields, &field)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+
+	return &fields, nil
+}
@@ -2,8 +2,10 @@ package mongo
 
 import (
 	"context"
+	"regexp"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
@@ -13,8 +15,15 @@ import (
 
 // SchemaRepo defines the methods a SchemaRepo can perform.
 type SchemaRepo interface {
-	Get(schemaName string) (interface{}, error)
+	GetSingle(schemaName string) (*model.SingleSchema, error)
 	Search() (*model.Schemas, error)
+	// Revisions retrieves all the revisions of a schema.
+	Revisions(schemaName string) ([]*model.SchemaRevision, error)
+	// FindLoad retrieves the last update of the schemas from the sources at
+	// the version, which is the version of all the sources or the commit of
+	// one of them, or a prefix of the commit if matchPrefix is true. It
+	// returns nil if there is none.
+	FindLoad(version string, matchPrefix bool) (*model.SourcesLoad, error)
 }
 
 type schemaRepo struct{}
@@ -24,8 +33,11 @@ func NewSchemaRepo() SchemaRepo {
 	return &schemaRepo{}
 }
 
-// Get retrieves a specific schema from the DB based on its name.
-func (r *schemaRepo) Get(schemaName string) (interface{}, error) {
+// GetSingle retrieves a specific schema from the DB based on its name, with
+// its full schema and compatibility report.
+func (r *schemaRepo) GetSingle(
+	schemaName string,
+) (*model.SingleSchema, error) {
 	filter := bson.M{"name": schemaName}
 	result := mongo.Client.FindOne(constant.MongoIndex.Schema, filter)
 
@@ -38,7 +50,7 @@ func (r *schemaRepo) Get(schemaName string) (interface{}, error) {
 		return nil, library.DatabaseError{Err: err}
 	}
 
-	return singleSchema.ToMap(), nil
+	return &singleSchema, nil
 }
 
 // Search retrieves all schemas from the DB.
@@ -68,3 +80,62 @@ func (r *schemaRepo) Search() (*model.Schemas, error) {
 
 	return &schemas, nil
 }
+
+// Revisions retrieves all the revisions of a schema from the DB.
+func (r *schemaRepo) Revisions(
+	schemaName string,
+) ([]*model.SchemaRevision, error) {
+	filter := bson.M{"name": schemaName}
+
+	cur, err := mongo.Client.Find(constant.MongoIndex.SchemaRevision, filter)
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	defer cur.Close(context.TODO())
+
+	var revisions []*model.SchemaRevision
+	if err := cur.All(context.TODO(), &revisions); err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+
+	return revisions, nil
+}
+
+// FindLoad retrieves the last update of the schemas from the sources at the
+// version from the DB.
+func (r *schemaRepo) FindLoad(
+	version string,
+	matchPrefix bool,
+) (*model.SourcesLoad, error) {
+	conditions := bson.A{
+		bson.M{"version": version},
+		bson.M{"commits": version},
+	}
+	if matchPrefix {
+		conditions = append(conditions, bson.M{
+			"commits": bson.M{"$regex": "^" + regexp.QuoteMeta(version)},
+		})
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "loaded_at", Value: -1}}).
+		SetLimit(1)
+
+	cur, err := mongo.Client.Find(
+		constant.MongoIndex.SchemaLoad,
+		bson.M{"$or": conditions},
+		opts,
+	)
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	defer cur.Close(context.TODO())
+
+	var loads []*model.SourcesLoad
+	if err := cur.All(context.TODO(), &loads); err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	if len(loads) == 0 {
+		return nil, nil
+	}
+	return loads[0], nil
+}
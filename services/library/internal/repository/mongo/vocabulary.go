@@ -0,0 +1,76 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+)
+
+// VocabularyRepo defines the methods a VocabularyRepo can perform.
+type VocabularyRepo interface {
+	Get(vocabularyName string) (*model.Vocabulary, error)
+	Search() (*model.Vocabularies, error)
+}
+
+type vocabularyRepo struct{}
+
+// NewVocabularyRepo returns a new vocabulary repository.
+func NewVocabularyRepo() VocabularyRepo {
+	return &vocabularyRepo{}
+}
+
+// Get retrieves a specific vocabulary from the DB based on its name.
+func (r *vocabularyRepo) Get(vocabularyName string) (*model.Vocabulary, error) {
+	filter := bson.M{"name": vocabularyName}
+	result := mongo.Client.FindOne(constant.MongoIndex.Vocabulary, filter)
+
+	var vocabulary model.Vocabulary
+	err := result.Decode(&vocabulary)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, library.VocabularyNotFoundError{
+				VocabularyName: vocabularyName,
+			}
+		}
+		return nil, library.DatabaseError{Err: err}
+	}
+
+	return &vocabulary, nil
+}
+
+// Search retrieves all vocabularies from the DB, sorted by name, without
+// their terms.
+func (r *vocabularyRepo) Search() (*model.Vocabularies, error) {
+	filter := bson.M{}
+	opts := options.Find().
+		SetSort(bson.M{"name": 1}).
+		SetProjection(bson.M{"terms": 0})
+
+	cur, err := mongo.Client.Find(constant.MongoIndex.Vocabulary, filter, opts)
+	if err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+	defer cur.Close(context.TODO())
+
+	vocabularies := model.Vocabularies{}
+	for cur.Next(context.TODO()) {
+		var vocabulary model.Vocabulary
+		err := cur.Decode(&vocabulary)
+		if err != nil {
+			return nil, library.DatabaseError{Err: err}
+		}
+		vocabularies = append(vocabularies, &vocabulary)
+	}
+
+	if err := cur.Err(); err != nil {
+		return nil, library.DatabaseError{Err: err}
+	}
+
+	return &vocabularies, nil
+}
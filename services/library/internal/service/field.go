@@ -0,0 +1,53 @@
+package service
+
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/repository/mongo"
+)
+
+// FieldService defines methods for operations on the shared field
+// definitions.
+type FieldService interface {
+	Get(fieldName string) (*model.SingleField, error)
+	Search(schemaName string) (*model.Fields, error)
+}
+
+type fieldService struct {
+	mongoRepo mongo.FieldRepo
+}
+
+// NewFieldService creates a new FieldService with the given FieldRepo.
+func NewFieldService(mongoRepo mongo.FieldRepo) FieldService {
+	return &fieldService{
+		mongoRepo: mongoRepo,
+	}
+}
+
+// Get fetches a field with the given name, with its full definition and the
+// schemas using it.
+func (s *fieldService) Get(fieldName string) (*model.SingleField, error) {
+	return s.mongoRepo.Get(fieldName)
+}
+
+// Search retrieves all fields, or only the fields used by the given schema
+// if its name isn't empty.
+func (s *fieldService) Search(schemaName string) (*model.Fields, error) {
+	fields, err := s.mongoRepo.Search()
+	if err != nil {
+		return nil, err
+	}
+	if schemaName == "" {
+		return fields, nil
+	}
+
+	result := model.Fields{}
+	for _, field := range *fields {
+		for _, usedBy := range field.UsedBy {
+			if usedBy == schemaName {
+				result = append(result, field)
+				break
+			}
+		}
+	}
+	return &result, nil
+}
@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
+)
+
+type MockFieldRepo struct {
+	mock.Mock
+}
+
+func (m *MockFieldRepo) Get(fieldName string) (*model.SingleField, error) {
+	args := m.Called(fieldName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SingleField), args.Error(1)
+}
+
+func (m *MockFieldRepo) Search() (*model.Fields, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Fields), args.Error(1)
+}
+
+func TestFieldService(t *testing.T) {
+	fields := &model.Fields{
+		&model.Field{
+			Name:   "name",
+			UsedBy: []string{"organizations_schema-v1.0.0", "people_schema-v0.1.0"},
+		},
+		&model.Field{
+			Name:   "tags",
+			UsedBy: []string{"organizations_schema-v1.0.0"},
+		},
+		&model.Field{Name: "unused", UsedBy: []string{}},
+	}
+	mockRepo := new(MockFieldRepo)
+	mockRepo.On("Search").Return(fields, nil)
+	mockRepo.On("Get", "unknown").
+		Return(nil, library.FieldNotFoundError{FieldName: "unknown"})
+	s := service.NewFieldService(mockRepo)
+
+	tests := []struct {
+		name       string
+		schemaName string
+		expFields  []string
+	}{
+		{
+			name:      "All fields",
+			expFields: []string{"name", "tags", "unused"},
+		},
+		{
+			name:       "Fields used by a schema",
+			schemaName: "people_schema-v0.1.0",
+			expFields:  []string{"name"},
+		},
+		{
+			name:       "Unknown schema",
+			schemaName: "unknown_schema-v1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.Search(tt.schemaName)
+			assert.NoError(t, err)
+			var got []string
+			for _, field := range *result {
+				got = append(got, field.Name)
+			}
+			assert.Equal(t, tt.expFields, got)
+		})
+	}
+
+	_, err := s.Get("unknown")
+	assert.ErrorAs(t, err, &library.FieldNotFoundError{})
+}
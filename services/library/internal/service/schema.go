@@ -1,14 +1,26 @@
 package service
 
 import (
+	"strings"
+	"time"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemacodegen"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemadiff"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaname"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/repository/mongo"
 )
 
 // SchemaService defines mtehods for operations on Schemas.
 type SchemaService interface {
-	Get(schemaName string) (interface{}, error)
+	Get(schemaName string, at string) (*model.SingleSchema, error)
 	Search() (*model.Schemas, error)
+	Resolve(schemaName string) (string, error)
+	Versions(schemaName string) (*model.Schemas, error)
+	Latest(schemaName string) (string, error)
+	Diff(schemaName string, from string) (*schemadiff.Report, error)
+	Generate(schemaName string, target string) ([]byte, error)
 }
 
 type schemaService struct {
@@ -22,13 +34,104 @@ func NewSchemaService(mongoRepo mongo.SchemaRepo) SchemaService {
 	}
 }
 
-// Get fetches a Schema with the given name.
-func (s *schemaService) Get(schemaName string) (interface{}, error) {
-	result, err := s.mongoRepo.Get(schemaName)
+// Get fetches a Schema with the given name. If at isn't empty, the schema is
+// fetched as it was at a commit or date, see revisionAt.
+func (s *schemaService) Get(
+	schemaName string,
+	at string,
+) (*model.SingleSchema, error) {
+	if at == "" {
+		return s.mongoRepo.GetSingle(schemaName)
+	}
+
+	revisions, err := s.mongoRepo.Revisions(schemaName)
 	if err != nil {
 		return nil, err
 	}
-	return result, nil
+	if len(revisions) == 0 {
+		// Tell unknown schemas from schemas without revisions.
+		if _, err := s.mongoRepo.GetSingle(schemaName); err != nil {
+			return nil, err
+		}
+	}
+
+	var load *model.SourcesLoad
+	if _, isDate := parseDate(at); !isDate {
+		load, err = s.mongoRepo.FindLoad(at, len(at) >= minCommitPrefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	revision, ok := revisionAt(revisions, at, load)
+	if !ok {
+		return nil, library.RevisionNotFoundError{SchemaName: schemaName, At: at}
+	}
+	return &model.SingleSchema{
+		Name:       schemaName,
+		FullSchema: revision.FullSchema,
+		Revision:   revision.Revision,
+	}, nil
+}
+
+// minCommitPrefix is the length of the shortest commit SHA prefix matched.
+const minCommitPrefix = 7
+
+// revisionAt returns the revision the schema had at at, which is either a
+// date, in RFC 3339 format or as YYYY-MM-DD meaning the end of that day in
+// UTC, or the version the sources were loaded at: the commit of a source, a
+// prefix of it, or the version of all the sources. The revision itself, e.g.
+// from the ETag header, matches too.
+//
+// The schema had the revision it was last changed to at or before the date,
+// or the load of the sources at the version. Versions loaded before the
+// loads were logged, for which load is nil, are found in the loads of the
+// revisions.
+func revisionAt(
+	revisions []*model.SchemaRevision,
+	at string,
+	load *model.SourcesLoad,
+) (*model.SchemaRevision, bool) {
+	for _, revision := range revisions {
+		if revision.Revision == at {
+			return revision, true
+		}
+	}
+
+	date, isDate := parseDate(at)
+	if !isDate && load != nil {
+		date, isDate = load.LoadedAt, true
+	}
+
+	var found *model.SchemaRevision
+	var foundAt int64
+	for _, revision := range revisions {
+		for _, load := range revision.Loads {
+			var match bool
+			if isDate {
+				match = load.LoadedAt <= date
+			} else {
+				match = load.Commit == at || load.Version == at ||
+					len(at) >= minCommitPrefix && strings.HasPrefix(load.Commit, at)
+			}
+			if match && (found == nil || load.LoadedAt > foundAt) {
+				found, foundAt = revision, load.LoadedAt
+			}
+		}
+	}
+	return found, found != nil
+}
+
+// parseDate parses a date in RFC 3339 format, or as YYYY-MM-DD meaning the
+// end of that day in UTC, into a Unix timestamp.
+func parseDate(value string) (int64, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Unix(), true
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t.AddDate(0, 0, 1).Unix() - 1, true
+	}
+	return 0, false
 }
 
 // Search retrieves all Schemas.
@@ -39,3 +142,147 @@ func (s *schemaService) Search() (*model.Schemas, error) {
 	}
 	return result, nil
 }
+
+// Resolve returns the name of the schema to serve for the given name. A major
+// version range, e.g. organizations_schema-v1, resolves to the newest
+// compatible version, unless a schema has that exact name. Other names are
+// returned as they are.
+func (s *schemaService) Resolve(schemaName string) (string, error) {
+	if !schemaname.IsRange(schemaName) {
+		return schemaName, nil
+	}
+
+	names, err := s.names()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range names {
+		if name == schemaName {
+			return schemaName, nil
+		}
+	}
+
+	latest, ok := schemaname.Latest(names, schemaName)
+	if !ok {
+		return "", library.SchemaNotFoundError{SchemaName: schemaName}
+	}
+	return latest.String(), nil
+}
+
+// Versions retrieves the versions of a schema, newest first. The schema is
+// given by its name without the version, or by a major version range.
+func (s *schemaService) Versions(schemaName string) (*model.Schemas, error) {
+	schemas, err := s.mongoRepo.Search()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*model.Schema, len(*schemas))
+	names := make([]string, 0, len(*schemas))
+	for _, schema := range *schemas {
+		byName[schema.Name] = schema
+		names = append(names, schema.Name)
+	}
+
+	versions := schemaname.Versions(names, schemaName)
+	if len(versions) == 0 {
+		return nil, library.SchemaNotFoundError{SchemaName: schemaName}
+	}
+
+	result := make(model.Schemas, 0, len(versions))
+	for _, version := range versions {
+		schema := *byName[version.String()]
+		schema.Version = version.Version()
+		result = append(result, &schema)
+	}
+	return &result, nil
+}
+
+// Latest returns the name of the newest version of a schema, given by its
+// name without the version or by a major version range. Pre-releases are only
+// returned if there is no release.
+func (s *schemaService) Latest(schemaName string) (string, error) {
+	names, err := s.names()
+	if err != nil {
+		return "", err
+	}
+
+	latest, ok := schemaname.Latest(names, schemaName)
+	if !ok {
+		return "", library.SchemaNotFoundError{SchemaName: schemaName}
+	}
+	return latest.String(), nil
+}
+
+// Diff reports the changes to a schema from the schema named from, or from
+// its previous version if from is empty. Both names can be major version
+// ranges.
+func (s *schemaService) Diff(
+	schemaName string,
+	from string,
+) (*schemadiff.Report, error) {
+	schemaName, err := s.Resolve(schemaName)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := s.mongoRepo.GetSingle(schemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	if from == "" {
+		if schema.Compatibility == nil {
+			return nil, library.PreviousVersionNotFoundError{
+				SchemaName: schemaName,
+			}
+		}
+		return schema.Compatibility, nil
+	}
+
+	from, err = s.Resolve(from)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Compatibility != nil && schema.Compatibility.From == from {
+		return schema.Compatibility, nil
+	}
+	fromSchema, err := s.mongoRepo.GetSingle(from)
+	if err != nil {
+		return nil, err
+	}
+	return schemadiff.Compare(
+		from, fromSchema.FullSchema,
+		schemaName, schema.FullSchema,
+	), nil
+}
+
+// Generate generates the artifact of the target from the schema with the
+// given name, which can be a major version range. See schemacodegen for the
+// targets.
+func (s *schemaService) Generate(schemaName string, target string) ([]byte, error) {
+	if !schemacodegen.IsTarget(target) {
+		return nil, library.TargetNotFoundError{Target: target}
+	}
+	schemaName, err := s.Resolve(schemaName)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := s.mongoRepo.GetSingle(schemaName)
+	if err != nil {
+		return nil, err
+	}
+	return schemacodegen.Generate(target, schemaName, schema.FullSchema)
+}
+
+// names returns the names of all schemas.
+func (s *schemaService) names() ([]string, error) {
+	schemas, err := s.mongoRepo.Search()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(*schemas))
+	for _, schema := range *schemas {
+		names = append(names, schema.Name)
+	}
+	return names, nil
+}
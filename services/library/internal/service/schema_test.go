@@ -2,11 +2,15 @@ package service_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
 
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemadiff"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
 )
@@ -15,9 +19,28 @@ type MockRepo struct {
 	mock.Mock
 }
 
-func (m *MockRepo) Get(schemaName string) (interface{}, error) {
+func (m *MockRepo) Revisions(schemaName string) ([]*model.SchemaRevision, error) {
 	args := m.Called(schemaName)
-	return args.Get(0), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SchemaRevision), args.Error(1)
+}
+
+func (m *MockRepo) FindLoad(version string, matchPrefix bool) (*model.SourcesLoad, error) {
+	args := m.Called(version, matchPrefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SourcesLoad), args.Error(1)
+}
+
+func (m *MockRepo) GetSingle(schemaName string) (*model.SingleSchema, error) {
+	args := m.Called(schemaName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SingleSchema), args.Error(1)
 }
 
 func (m *MockRepo) Search() (*model.Schemas, error) {
@@ -29,11 +52,10 @@ func (m *MockRepo) Search() (*model.Schemas, error) {
 }
 
 func TestSchemaService(t *testing.T) {
-	mockSchema := &model.Schema{
-		Title:       "Title",
-		Description: "Description",
+	mockSchema := &model.SingleSchema{
 		Name:        "TestSchema",
-		URL:         "URL",
+		Description: "Description",
+		Revision:    "abc123",
 	}
 	mockSchemas := &model.Schemas{
 		&model.Schema{
@@ -63,7 +85,7 @@ func TestSchemaService(t *testing.T) {
 		{
 			name: "Test valid Get and Search",
 			repoGet: func(m *MockRepo) {
-				m.On("Get", "TestSchema").Return(mockSchema, nil)
+				m.On("GetSingle", "TestSchema").Return(mockSchema, nil)
 			},
 			repoSearch: func(m *MockRepo) {
 				m.On("Search").Return(mockSchemas, nil)
@@ -77,7 +99,7 @@ func TestSchemaService(t *testing.T) {
 		{
 			name: "Test Get and Search errors",
 			repoGet: func(m *MockRepo) {
-				m.On("Get", "NonExistent").
+				m.On("GetSingle", "NonExistent").
 					Return(nil, errors.New("schema not found"))
 			},
 			repoSearch: func(m *MockRepo) {
@@ -98,7 +120,7 @@ func TestSchemaService(t *testing.T) {
 			tt.repoSearch(mockRepo)
 			s := service.NewSchemaService(mockRepo)
 
-			resultGet, err := s.Get(tt.getSchemaName)
+			resultGet, err := s.Get(tt.getSchemaName, "")
 			if tt.expGetErr {
 				assert.Error(t, err)
 			} else {
@@ -118,3 +140,386 @@ func TestSchemaService(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaVersions(t *testing.T) {
+	schemas := &model.Schemas{
+		&model.Schema{Name: "organizations_schema-v1.0.0"},
+		&model.Schema{Name: "organizations_schema-v1.1.0", Title: "Organizations"},
+		&model.Schema{Name: "organizations_schema-v2.0.0-beta"},
+		&model.Schema{Name: "people_schema-v1.0.0"},
+	}
+	mockRepo := new(MockRepo)
+	mockRepo.On("Search").Return(schemas, nil)
+	s := service.NewSchemaService(mockRepo)
+
+	tests := []struct {
+		name        string
+		schemaName  string
+		expResolved string
+		expLatest   string
+		expVersions []string
+	}{
+		{
+			name:        "Base name",
+			schemaName:  "organizations_schema",
+			expResolved: "organizations_schema",
+			expLatest:   "organizations_schema-v1.1.0",
+			expVersions: []string{"2.0.0-beta", "1.1.0", "1.0.0"},
+		},
+		{
+			name:        "Major range",
+			schemaName:  "organizations_schema-v1",
+			expResolved: "organizations_schema-v1.1.0",
+			expLatest:   "organizations_schema-v1.1.0",
+			expVersions: []string{"1.1.0", "1.0.0"},
+		},
+		{
+			name:        "Exact version",
+			schemaName:  "people_schema-v1.0.0",
+			expResolved: "people_schema-v1.0.0",
+		},
+		{
+			name:       "Unknown range",
+			schemaName: "organizations_schema-v3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := s.Resolve(tt.schemaName)
+			if tt.expResolved == "" {
+				assert.ErrorAs(t, err, &library.SchemaNotFoundError{})
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expResolved, resolved)
+			}
+
+			latest, err := s.Latest(tt.schemaName)
+			if tt.expLatest == "" {
+				assert.ErrorAs(t, err, &library.SchemaNotFoundError{})
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expLatest, latest)
+			}
+
+			versions, err := s.Versions(tt.schemaName)
+			if tt.expVersions == nil {
+				assert.ErrorAs(t, err, &library.SchemaNotFoundError{})
+				return
+			}
+			assert.NoError(t, err)
+			var got []string
+			for _, version := range *versions {
+				got = append(got, version.Version)
+			}
+			assert.Equal(t, tt.expVersions, got)
+		})
+	}
+
+	// The schemas of the repository are left untouched.
+	assert.Empty(t, (*schemas)[0].Version)
+}
+
+func TestSchemaDiff(t *testing.T) {
+	stored := &schemadiff.Report{
+		From: "test_schema-v1.0.0",
+		To:   "test_schema-v1.1.0",
+	}
+	mockRepo := new(MockRepo)
+	mockRepo.On("Search").Return(&model.Schemas{
+		&model.Schema{Name: "test_schema-v0.1.0"},
+		&model.Schema{Name: "test_schema-v1.0.0"},
+		&model.Schema{Name: "test_schema-v1.1.0"},
+	}, nil)
+	mockRepo.On("GetSingle", "test_schema-v0.1.0").Return(&model.SingleSchema{
+		Name: "test_schema-v0.1.0",
+		FullSchema: bson.D{
+			{Key: "required", Value: bson.A{"name"}},
+		},
+	}, nil)
+	mockRepo.On("GetSingle", "test_schema-v1.0.0").Return(&model.SingleSchema{
+		Name: "test_schema-v1.0.0",
+	}, nil)
+	mockRepo.On("GetSingle", "test_schema-v1.1.0").Return(&model.SingleSchema{
+		Name: "test_schema-v1.1.0",
+		FullSchema: bson.D{
+			{Key: "required", Value: bson.A{"name", "url"}},
+		},
+		Compatibility: stored,
+	}, nil)
+	s := service.NewSchemaService(mockRepo)
+
+	tests := []struct {
+		name        string
+		schemaName  string
+		from        string
+		expErr      error
+		expFrom     string
+		expBreaking bool
+	}{
+		{
+			name:        "Previous version",
+			schemaName:  "test_schema-v1",
+			expFrom:     "test_schema-v1.0.0",
+			expBreaking: false,
+		},
+		{
+			name:        "Stored previous version",
+			schemaName:  "test_schema-v1.1.0",
+			from:        "test_schema-v1.0.0",
+			expFrom:     "test_schema-v1.0.0",
+			expBreaking: false,
+		},
+		{
+			name:        "Older version",
+			schemaName:  "test_schema-v1.1.0",
+			from:        "test_schema-v0",
+			expFrom:     "test_schema-v0.1.0",
+			expBreaking: true,
+		},
+		{
+			name:       "No previous version",
+			schemaName: "test_schema-v1.0.0",
+			expErr:     library.PreviousVersionNotFoundError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := s.Diff(tt.schemaName, tt.from)
+			if tt.expErr != nil {
+				assert.ErrorAs(t, err, &library.PreviousVersionNotFoundError{})
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expFrom, report.From)
+			assert.Equal(t, "test_schema-v1.1.0", report.To)
+			assert.Equal(t, tt.expBreaking, report.Breaking)
+		})
+	}
+}
+
+func TestSchemaGenerate(t *testing.T) {
+	mockRepo := new(MockRepo)
+	mockRepo.On("Search").Return(&model.Schemas{
+		&model.Schema{Name: "test_schema-v1.0.0"},
+		&model.Schema{Name: "test_schema-v1.1.0"},
+	}, nil)
+	mockRepo.On("GetSingle", "test_schema-v1.1.0").Return(&model.SingleSchema{
+		Name: "test_schema-v1.1.0",
+		FullSchema: bson.D{
+			{Key: "type", Value: "object"},
+			{Key: "properties", Value: bson.D{
+				{Key: "linked_schemas", Value: bson.D{{Key: "type", Value: "array"}}},
+				{Key: "name", Value: bson.D{{Key: "type", Value: "string"}}},
+			}},
+		},
+	}, nil)
+	s := service.NewSchemaService(mockRepo)
+
+	tests := []struct {
+		name       string
+		schemaName string
+		target     string
+		expErr     error
+		expData    string
+	}{
+		{
+			name:       "CSV header of the newest version",
+			schemaName: "test_schema-v1",
+			target:     "csv",
+			expData:    "oid,name\n",
+		},
+		{
+			name:       "Unknown target",
+			schemaName: "test_schema-v1",
+			target:     "python",
+			expErr:     library.TargetNotFoundError{Target: "python"},
+		},
+		{
+			name:       "Unknown schema",
+			schemaName: "other_schema-v1",
+			target:     "csv",
+			expErr:     library.SchemaNotFoundError{SchemaName: "other_schema-v1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := s.Generate(tt.schemaName, tt.target)
+			if tt.expErr != nil {
+				assert.Equal(t, tt.expErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expData, string(data))
+		})
+	}
+}
+
+func TestSchemaGetAt(t *testing.T) {
+	revision := func(
+		id string,
+		loads ...model.SchemaLoad,
+	) *model.SchemaRevision {
+		return &model.SchemaRevision{
+			Name:       "test_schema-v1.0.0",
+			Revision:   id,
+			FullSchema: bson.D{{Key: "title", Value: id}},
+			Loads:      loads,
+		}
+	}
+	// The schema changed at commit b and changed back at commit c.
+	revisions := []*model.SchemaRevision{
+		revision(
+			"r1",
+			model.SchemaLoad{Commit: "aaaaaaaaaa", Version: "aaaaaaaaaa", LoadedAt: 1704067200}, // 2024-01-01
+			model.SchemaLoad{Commit: "cccccccccc", Version: "cccccccccc", LoadedAt: 1706745600}, // 2024-02-01
+		),
+		revision(
+			"r2",
+			model.SchemaLoad{Commit: "bbbbbbbbbb", Version: "bbbbbbbbbb", LoadedAt: 1705276800}, // 2024-01-15
+		),
+	}
+
+	tests := []struct {
+		name        string
+		at          string
+		revisions   []*model.SchemaRevision
+		repoErr     error
+		expRevision string
+		expErr      error
+	}{
+		{name: "Commit", at: "bbbbbbbbbb", revisions: revisions, expRevision: "r2"},
+		{name: "Commit prefix", at: "ccccccc", revisions: revisions, expRevision: "r1"},
+		{name: "Revision", at: "r2", revisions: revisions, expRevision: "r2"},
+		{
+			name:      "Too short commit prefix",
+			at:        "cccccc",
+			revisions: revisions,
+			expErr:    library.RevisionNotFoundError{SchemaName: "test_schema-v1.0.0", At: "cccccc"},
+		},
+		{name: "Date", at: "2024-01-20", revisions: revisions, expRevision: "r2"},
+		{name: "End of the day", at: "2024-01-15", revisions: revisions, expRevision: "r2"},
+		{name: "Date and time", at: "2024-01-14T23:59:59Z", revisions: revisions, expRevision: "r1"},
+		{name: "Date after changing back", at: "2024-03-01", revisions: revisions, expRevision: "r1"},
+		{
+			name:      "Date before the first load",
+			at:        "2023-12-31",
+			revisions: revisions,
+			expErr:    library.RevisionNotFoundError{SchemaName: "test_schema-v1.0.0", At: "2023-12-31"},
+		},
+		{
+			name:      "Unknown commit",
+			at:        "dddddddddd",
+			revisions: revisions,
+			expErr:    library.RevisionNotFoundError{SchemaName: "test_schema-v1.0.0", At: "dddddddddd"},
+		},
+		{
+			name:    "Unknown schema",
+			at:      "2024-01-20",
+			repoErr: library.SchemaNotFoundError{SchemaName: "test_schema-v1.0.0"},
+			expErr:  library.SchemaNotFoundError{SchemaName: "test_schema-v1.0.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepo)
+			mockRepo.On("Revisions", "test_schema-v1.0.0").Return(tt.revisions, nil)
+			// The revisions were recorded before the loads were logged.
+			mockRepo.On("FindLoad", tt.at, mock.Anything).Return(nil, nil)
+			if len(tt.revisions) == 0 {
+				mockRepo.On("GetSingle", "test_schema-v1.0.0").Return(nil, tt.repoErr)
+			}
+			s := service.NewSchemaService(mockRepo)
+
+			schema, err := s.Get("test_schema-v1.0.0", tt.at)
+			if tt.expErr != nil {
+				assert.Equal(t, tt.expErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "test_schema-v1.0.0", schema.Name)
+			assert.Equal(t, tt.expRevision, schema.Revision)
+			assert.Equal(t, bson.D{{Key: "title", Value: tt.expRevision}}, schema.FullSchema)
+		})
+	}
+}
+
+func TestSchemaGetAtLoggedLoads(t *testing.T) {
+	revision := func(
+		id string,
+		loads ...model.SchemaLoad,
+	) *model.SchemaRevision {
+		return &model.SchemaRevision{
+			Name:       "test_schema-v1.0.0",
+			Revision:   id,
+			FullSchema: bson.D{{Key: "title", Value: id}},
+			Loads:      loads,
+		}
+	}
+	// The sources are loaded hourly from 2024-01-01, 150 times. The schema
+	// changed at the 60th load and changed back at the 120th, which are the
+	// only loads its revisions keep.
+	loads := make([]*model.SourcesLoad, 150)
+	for i := range loads {
+		commit := fmt.Sprintf("%040x", i)
+		loads[i] = &model.SourcesLoad{
+			Version:  commit,
+			Commits:  []string{commit},
+			LoadedAt: 1704067200 + int64(i)*3600,
+		}
+	}
+	schemaLoad := func(i int) model.SchemaLoad {
+		return model.SchemaLoad{
+			Commit:   loads[i].Commits[0],
+			Version:  loads[i].Version,
+			LoadedAt: loads[i].LoadedAt,
+		}
+	}
+	revisions := []*model.SchemaRevision{
+		revision("r1", schemaLoad(0), schemaLoad(120)),
+		revision("r2", schemaLoad(60)),
+	}
+
+	tests := []struct {
+		name        string
+		at          string
+		load        *model.SourcesLoad
+		expRevision string
+		expErr      error
+	}{
+		{name: "Unchanged commit", at: loads[10].Version, load: loads[10], expRevision: "r1"},
+		{name: "Changing commit", at: loads[60].Version, load: loads[60], expRevision: "r2"},
+		{name: "Commit prefix", at: loads[100].Version[:7], load: loads[100], expRevision: "r2"},
+		{name: "Changed back", at: loads[149].Version, load: loads[149], expRevision: "r1"},
+		{name: "Date", at: "2024-01-03T12:00:00Z", expRevision: "r2"},
+		{
+			name:   "Unknown commit",
+			at:     "dddddddddd",
+			expErr: library.RevisionNotFoundError{SchemaName: "test_schema-v1.0.0", At: "dddddddddd"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepo)
+			mockRepo.On("Revisions", "test_schema-v1.0.0").Return(revisions, nil)
+			if tt.load != nil {
+				mockRepo.On("FindLoad", tt.at, len(tt.at) >= 7).Return(tt.load, nil)
+			} else {
+				mockRepo.On("FindLoad", tt.at, mock.Anything).Return(nil, nil)
+			}
+			s := service.NewSchemaService(mockRepo)
+
+			schema, err := s.Get("test_schema-v1.0.0", tt.at)
+			if tt.expErr != nil {
+				assert.Equal(t, tt.expErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expRevision, schema.Revision)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaname"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/repository/mongo"
+)
+
+// VocabularyService defines methods for operations on the controlled
+// vocabularies.
+type VocabularyService interface {
+	Get(vocabularyName string) (*model.Vocabulary, error)
+	Search(schemaName string) (*model.Vocabularies, error)
+}
+
+type vocabularyService struct {
+	mongoRepo mongo.VocabularyRepo
+}
+
+// NewVocabularyService creates a new VocabularyService with the given
+// VocabularyRepo.
+func NewVocabularyService(mongoRepo mongo.VocabularyRepo) VocabularyService {
+	return &vocabularyService{
+		mongoRepo: mongoRepo,
+	}
+}
+
+// Get fetches a vocabulary with the given name, with its terms and the
+// schemas using it. A major version range, e.g. kvm_categories-v1, resolves
+// to the newest compatible version, unless a vocabulary has that exact name.
+func (s *vocabularyService) Get(
+	vocabularyName string,
+) (*model.Vocabulary, error) {
+	vocabulary, err := s.mongoRepo.Get(vocabularyName)
+	var notFound library.VocabularyNotFoundError
+	if err == nil || !errors.As(err, &notFound) ||
+		!schemaname.IsRange(vocabularyName) {
+		return vocabulary, err
+	}
+
+	vocabularies, err := s.mongoRepo.Search()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(*vocabularies))
+	for _, vocabulary := range *vocabularies {
+		names = append(names, vocabulary.Name)
+	}
+	latest, ok := schemaname.Latest(names, vocabularyName)
+	if !ok {
+		return nil, notFound
+	}
+	return s.mongoRepo.Get(latest.String())
+}
+
+// Search retrieves all vocabularies, or only the vocabularies used by the
+// given schema if its name isn't empty.
+func (s *vocabularyService) Search(
+	schemaName string,
+) (*model.Vocabularies, error) {
+	vocabularies, err := s.mongoRepo.Search()
+	if err != nil {
+		return nil, err
+	}
+	if schemaName == "" {
+		return vocabularies, nil
+	}
+
+	result := model.Vocabularies{}
+	for _, vocabulary := range *vocabularies {
+		for _, usedBy := range vocabulary.UsedBy {
+			if usedBy == schemaName {
+				result = append(result, vocabulary)
+				break
+			}
+		}
+	}
+	return &result, nil
+}
@@ -0,0 +1,112 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/library"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/library/internal/service"
+)
+
+type MockVocabularyRepo struct {
+	mock.Mock
+}
+
+func (m *MockVocabularyRepo) Get(vocabularyName string) (*model.Vocabulary, error) {
+	args := m.Called(vocabularyName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Vocabulary), args.Error(1)
+}
+
+func (m *MockVocabularyRepo) Search() (*model.Vocabularies, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Vocabularies), args.Error(1)
+}
+
+func TestVocabularyServiceGet(t *testing.T) {
+	vocabularies := &model.Vocabularies{
+		&model.Vocabulary{Name: "categories-v1.0.0"},
+		&model.Vocabulary{Name: "categories-v1.1.0"},
+		&model.Vocabulary{Name: "categories-v2.0.0-beta"},
+	}
+	latest := &model.Vocabulary{
+		Name:  "categories-v1.1.0",
+		Terms: []model.Term{{ID: "non-profit"}},
+	}
+	notFound := func(name string) error {
+		return library.VocabularyNotFoundError{VocabularyName: name}
+	}
+	mockRepo := new(MockVocabularyRepo)
+	mockRepo.On("Search").Return(vocabularies, nil)
+	mockRepo.On("Get", "categories-v1.1.0").Return(latest, nil)
+	mockRepo.On("Get", "categories-v1").Return(nil, notFound("categories-v1"))
+	mockRepo.On("Get", "categories-v3").Return(nil, notFound("categories-v3"))
+	mockRepo.On("Get", "topics-v1.0.0").Return(nil, notFound("topics-v1.0.0"))
+	s := service.NewVocabularyService(mockRepo)
+
+	tests := []struct {
+		name           string
+		vocabularyName string
+		expected       *model.Vocabulary
+	}{
+		{
+			name:           "Version",
+			vocabularyName: "categories-v1.1.0",
+			expected:       latest,
+		},
+		{
+			name:           "Major version range",
+			vocabularyName: "categories-v1",
+			expected:       latest,
+		},
+		{
+			name:           "Unknown major version range",
+			vocabularyName: "categories-v3",
+		},
+		{
+			name:           "Unknown vocabulary",
+			vocabularyName: "topics-v1.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.Get(tt.vocabularyName)
+			if tt.expected == nil {
+				assert.ErrorAs(t, err, &library.VocabularyNotFoundError{})
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestVocabularyServiceSearch(t *testing.T) {
+	vocabularies := &model.Vocabularies{
+		&model.Vocabulary{
+			Name:   "categories-v1.0.0",
+			UsedBy: []string{"organizations_schema-v1.0.0"},
+		},
+		&model.Vocabulary{Name: "topics-v1.0.0", UsedBy: []string{}},
+	}
+	mockRepo := new(MockVocabularyRepo)
+	mockRepo.On("Search").Return(vocabularies, nil)
+	s := service.NewVocabularyService(mockRepo)
+
+	result, err := s.Search("")
+	assert.NoError(t, err)
+	assert.Len(t, *result, 2)
+
+	result, err = s.Search("organizations_schema-v1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Vocabularies{(*vocabularies)[0]}, result)
+}
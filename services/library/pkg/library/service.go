@@ -131,6 +131,12 @@ func (s *Service) registerRoutes() {
 	schemaHandler := rest.NewSchemaHandler(
 		service.NewSchemaService(mongo.NewSchemaRepo()),
 	)
+	fieldHandler := rest.NewFieldHandler(
+		service.NewFieldService(mongo.NewFieldRepo()),
+	)
+	vocabularyHandler := rest.NewVocabularyHandler(
+		service.NewVocabularyService(mongo.NewVocabularyRepo()),
+	)
 	countryHandler := rest.NewCountryHandler()
 
 	v1 := s.router.Group("/v1")
@@ -140,6 +146,14 @@ func (s *Service) registerRoutes() {
 	v2.GET("/ping", handler.PingHandler)
 	v2.GET("/schemas", schemaHandler.Search)
 	v2.GET("/schemas/:schemaName", schemaHandler.Get)
+	v2.GET("/schemas/:schemaName/versions", schemaHandler.Versions)
+	v2.GET("/schemas/:schemaName/latest", schemaHandler.Latest)
+	v2.GET("/schemas/:schemaName/diff", schemaHandler.Diff)
+	v2.GET("/schemas/:schemaName/codegen/:target", schemaHandler.Generate)
+	v2.GET("/fields", fieldHandler.Search)
+	v2.GET("/fields/:fieldName", fieldHandler.Get)
+	v2.GET("/vocabularies", vocabularyHandler.Search)
+	v2.GET("/vocabularies/:vocabularyName", vocabularyHandler.Get)
 	v2.GET("/countries", countryHandler.GetMap)
 }
 
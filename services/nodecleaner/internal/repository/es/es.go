@@ -3,20 +3,25 @@ package es
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/elastic"
-	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/internal/model/query"
 )
 
 // NodeRepository defines the interface for operations that can be performed on
 // nodes in an Elasticsearch repository.
 type NodeRepository interface {
-	// Remove deletes nodes with the specified status and creation time earlier
-	// than the given timeBefore.
-	Remove(ctx context.Context, status string, timeBefore int64) error
-	// UpdateStatusByExpiration updates the status of nodes with expired status before the given time.
-	UpdateStatusByExpiration(ctx context.Context, status string, timeBefore int64) error
+	// RemoveByIDs deletes the nodes with the given IDs. It returns the IDs
+	// that could not be deleted.
+	RemoveByIDs(ctx context.Context, ids []string) ([]string, error)
+	// UpdateStatusByIDs sets the status of the nodes with the given IDs. It
+	// returns the IDs that could not be updated.
+	UpdateStatusByIDs(
+		ctx context.Context,
+		ids []string,
+		status string,
+	) ([]string, error)
 }
 
 type nodeRepository struct {
@@ -28,49 +33,64 @@ func NewNodeRepository() NodeRepository {
 	return &nodeRepository{}
 }
 
-// Remove deletes nodes from Elasticsearch that have the specified status and
-// were created or updated before the given time.
-func (r *nodeRepository) Remove(
+// RemoveByIDs deletes nodes from Elasticsearch in bulk.
+func (r *nodeRepository) RemoveByIDs(
 	_ context.Context,
-	status string,
-	timeBefore int64,
-) error {
-	q := query.EsQuery{Status: &status, TimeBefore: &timeBefore}
-
-	err := elastic.Client.DeleteMany(constant.ESIndex.Node, q.Build())
-	if err != nil {
-		return fmt.Errorf(
-			"error removing nodes with status %s and timeBefore %d from Elasticsearch: %v",
-			status,
-			timeBefore,
-			err,
-		)
-	}
-
-	return nil
+	ids []string,
+) ([]string, error) {
+	return bulk(ids, func(p elastic.BulkProcessor, id string) {
+		p.Delete(constant.ESIndex.Node, id)
+	})
 }
 
-// UpdateStatusByExpiration updates the status of nodes with expired status before the given time.
-func (r *nodeRepository) UpdateStatusByExpiration(
+// UpdateStatusByIDs updates the status of nodes in Elasticsearch in bulk.
+func (r *nodeRepository) UpdateStatusByIDs(
 	_ context.Context,
+	ids []string,
 	status string,
-	timeBefore int64,
-) error {
-	q := query.EsQuery{Status: &status, Expires: &timeBefore}
+) ([]string, error) {
+	return bulk(ids, func(p elastic.BulkProcessor, id string) {
+		p.Update(
+			constant.ESIndex.Node,
+			id,
+			map[string]interface{}{"status": status},
+		)
+	})
+}
 
-	update := map[string]interface{}{
-		"status": "deleted",
+// bulk queues one action per ID on a bulk processor, waits for all of them to
+// complete and returns the IDs whose action failed.
+func bulk(
+	ids []string,
+	add func(p elastic.BulkProcessor, id string),
+) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
 
-	err := elastic.Client.UpdateMany(constant.ESIndex.Node, q.Build(), update)
+	var (
+		mu     sync.Mutex
+		failed []string
+	)
+	p, err := elastic.Client.NewBulkProcessor(elastic.BulkOptions{
+		Name: "nodecleaner",
+		OnFailure: func(failure elastic.BulkItemFailure) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, failure.ID)
+		},
+	})
 	if err != nil {
-		return fmt.Errorf(
-			"error updating nodes status with status %s and expiresBefore %d in Elasticsearch: %v",
-			status,
-			timeBefore,
-			err,
-		)
+		return nil, fmt.Errorf("error creating bulk processor: %v", err)
+	}
+
+	for _, id := range ids {
+		add(p, id)
+	}
+
+	if err := p.Close(); err != nil {
+		return nil, fmt.Errorf("error sending bulk request: %v", err)
 	}
 
-	return nil
+	return failed, nil
 }
@@ -6,12 +6,14 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/nodecleaner/config"
 )
 
 const (
+	IDField          = "_id"
 	StatusField      = "status"
 	CreatedAtField   = "createdAt"
 	LastUpdatedField = "last_updated"
@@ -25,16 +27,22 @@ type NodeRepository interface {
 		status string,
 		timeBefore int64,
 	) error
-	RemoveByLastUpdated(
+	FindIDsByLastUpdated(
 		ctx context.Context,
 		status string,
 		timeBefore int64,
-	) error
-	UpdateStatusByExpiration(
+		afterID string,
+		limit int64,
+	) ([]string, error)
+	FindIDsByExpiration(
 		ctx context.Context,
 		status string,
 		timeBefore int64,
-	) error
+		afterID string,
+		limit int64,
+	) ([]string, error)
+	RemoveByIDs(ctx context.Context, ids []string) error
+	UpdateStatusByIDs(ctx context.Context, ids []string, status string) error
 }
 
 type nodeRepository struct {
@@ -55,14 +63,98 @@ func (r *nodeRepository) RemoveByCreatedAt(
 	return r.removeNodes(ctx, status, CreatedAtField, timeBefore)
 }
 
-// RemoveByLastUpdated removes nodes with the specified status that were last updated
-// before the given time.
-func (r *nodeRepository) RemoveByLastUpdated(
+// FindIDsByLastUpdated returns up to limit IDs of nodes with the specified
+// status that were last updated before the given time. IDs are returned in
+// ascending order, starting after afterID.
+func (r *nodeRepository) FindIDsByLastUpdated(
 	ctx context.Context,
 	status string,
 	timeBefore int64,
-) error {
-	return r.removeNodes(ctx, status, LastUpdatedField, timeBefore)
+	afterID string,
+	limit int64,
+) ([]string, error) {
+	return r.findIDs(ctx, status, LastUpdatedField, timeBefore, afterID, limit)
+}
+
+// FindIDsByExpiration returns up to limit IDs of nodes with the specified
+// status that expired before the given time. IDs are returned in ascending
+// order, starting after afterID.
+func (r *nodeRepository) FindIDsByExpiration(
+	ctx context.Context,
+	status string,
+	timeBefore int64,
+	afterID string,
+	limit int64,
+) ([]string, error) {
+	return r.findIDs(ctx, status, ExpiresField, timeBefore, afterID, limit)
+}
+
+// findIDs is a helper function encapsulating the logic for paging through the
+// IDs of nodes based on a time field, status, and timeBefore.
+func (r *nodeRepository) findIDs(
+	ctx context.Context,
+	status, timeField string,
+	timeBefore int64,
+	afterID string,
+	limit int64,
+) ([]string, error) {
+	filter := bson.M{
+		StatusField: status,
+		timeField: bson.M{
+			"$lt": timeBefore,
+		},
+	}
+	if afterID != "" {
+		filter[IDField] = bson.M{"$gt": afterID}
+	}
+
+	opts := options.Find().
+		SetProjection(bson.M{IDField: 1}).
+		SetSort(bson.M{IDField: 1}).
+		SetLimit(limit)
+
+	cursor, err := r.client.Database(config.Values.Mongo.DBName).
+		Collection(constant.MongoIndex.Node).
+		Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding nodes: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("error decoding nodes: %v", err)
+	}
+
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	return ids, nil
+}
+
+// RemoveByIDs removes the nodes with the given IDs.
+func (r *nodeRepository) RemoveByIDs(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	filter := bson.M{IDField: bson.M{"$in": ids}}
+
+	result, err := r.client.Database(config.Values.Mongo.DBName).
+		Collection(constant.MongoIndex.Node).
+		DeleteMany(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("error removing nodes: %v", err)
+	}
+
+	if result.DeletedCount > 0 {
+		fmt.Printf("Deleted %d nodes by ID\n", result.DeletedCount)
+	}
+
+	return nil
 }
 
 // removeNodes is a helper function encapsulating the logic for removing nodes
@@ -94,22 +186,21 @@ func (r *nodeRepository) removeNodes(
 	return nil
 }
 
-// UpdateStatusByExpiration updates the status of nodes with expired status before the given time.
-func (r *nodeRepository) UpdateStatusByExpiration(
+// UpdateStatusByIDs sets the status of the nodes with the given IDs.
+func (r *nodeRepository) UpdateStatusByIDs(
 	ctx context.Context,
+	ids []string,
 	status string,
-	timeBefore int64,
 ) error {
-	filter := bson.M{
-		StatusField: status,
-		ExpiresField: bson.M{
-			"$lt": timeBefore,
-		},
+	if len(ids) == 0 {
+		return nil
 	}
 
+	filter := bson.M{IDField: bson.M{"$in": ids}}
+
 	update := bson.M{
 		"$set": bson.M{
-			StatusField: constant.NodeStatus.Deleted,
+			StatusField: status,
 		},
 	}
 
@@ -121,8 +212,7 @@ func (r *nodeRepository) UpdateStatusByExpiration(
 	}
 
 	if result.ModifiedCount > 0 {
-		fmt.Printf("Updated %d nodes with %s status to %s which expired before %d\n",
-			result.ModifiedCount, status, constant.NodeStatus.Deleted, timeBefore)
+		fmt.Printf("Updated %d nodes to %s status\n", result.ModifiedCount, status)
 	}
 
 	return nil
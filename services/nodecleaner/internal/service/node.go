@@ -22,6 +22,9 @@ type NodesService interface {
 	SetExpiredToDeleted(ctx context.Context) error
 }
 
+// batchSize is the number of nodes sent to Elasticsearch per bulk request.
+const batchSize = 1000
+
 type nodesService struct {
 	mongoRepo mongo.NodeRepository
 	esRepo    es.NodeRepository
@@ -58,46 +61,117 @@ func (svc *nodesService) RemoveDeleted(ctx context.Context) error {
 		time.Duration(config.Values.TTL.DeletedTTL) * time.Second,
 	)
 
-	err := svc.mongoRepo.RemoveByLastUpdated(
+	return svc.forEachBatch(
 		ctx,
-		constant.NodeStatus.Deleted,
-		timeBefore,
+		func(afterID string) ([]string, error) {
+			return svc.mongoRepo.FindIDsByLastUpdated(
+				ctx,
+				constant.NodeStatus.Deleted,
+				timeBefore,
+				afterID,
+				batchSize,
+			)
+		},
+		func(ids []string) error {
+			failed, err := svc.esRepo.RemoveByIDs(ctx, ids)
+			if err != nil {
+				return fmt.Errorf("error removing nodes from Elasticsearch: %v", err)
+			}
+			err = svc.mongoRepo.RemoveByIDs(ctx, exclude(ids, failed))
+			if err != nil {
+				return fmt.Errorf("error removing nodes from MongoDB: %v", err)
+			}
+			return nil
+		},
 	)
-	if err != nil {
-		return fmt.Errorf("error removing nodes from MongoDB: %v", err)
-	}
-
-	err = svc.esRepo.Remove(ctx, constant.NodeStatus.Deleted, timeBefore)
-	if err != nil {
-		return fmt.Errorf("error removing nodes from Elasticsearch: %v", err)
-	}
-
-	return nil
 }
 
 // SetExpiredToDeleted sets nodes with expired status to deleted in both MongoDB and Elasticsearch.
 func (svc *nodesService) SetExpiredToDeleted(ctx context.Context) error {
 	timeBefore := dateutil.GetNowUnix()
 
-	// Update nodes in MongoDB
-	err := svc.mongoRepo.UpdateStatusByExpiration(
+	return svc.forEachBatch(
 		ctx,
-		constant.NodeStatus.Posted,
-		timeBefore,
+		func(afterID string) ([]string, error) {
+			return svc.mongoRepo.FindIDsByExpiration(
+				ctx,
+				constant.NodeStatus.Posted,
+				timeBefore,
+				afterID,
+				batchSize,
+			)
+		},
+		func(ids []string) error {
+			failed, err := svc.esRepo.UpdateStatusByIDs(
+				ctx,
+				ids,
+				constant.NodeStatus.Deleted,
+			)
+			if err != nil {
+				return fmt.Errorf("error updating nodes status in Elasticsearch: %v", err)
+			}
+			err = svc.mongoRepo.UpdateStatusByIDs(
+				ctx,
+				exclude(ids, failed),
+				constant.NodeStatus.Deleted,
+			)
+			if err != nil {
+				return fmt.Errorf("error updating nodes status in MongoDB: %v", err)
+			}
+			return nil
+		},
 	)
-	if err != nil {
-		return fmt.Errorf("error updating nodes status in MongoDB: %v", err)
+}
+
+// forEachBatch pages through node IDs with find and passes each page to
+// process. Elasticsearch is always updated before MongoDB, so nodes that fail
+// in Elasticsearch keep their MongoDB state and are retried on the next run.
+func (svc *nodesService) forEachBatch(
+	ctx context.Context,
+	find func(afterID string) ([]string, error),
+	process func(ids []string) error,
+) error {
+	afterID := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ids, err := find(afterID)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := process(ids); err != nil {
+			return err
+		}
+
+		if len(ids) < batchSize {
+			return nil
+		}
+		afterID = ids[len(ids)-1]
 	}
+}
 
-	// Update nodes in Elasticsearch
-	err = svc.esRepo.UpdateStatusByExpiration(
-		ctx,
-		constant.NodeStatus.Posted,
-		timeBefore,
-	)
-	if err != nil {
-		return fmt.Errorf("error updating nodes status in Elasticsearch: %v", err)
+// exclude returns the IDs that are not in excluded.
+func exclude(ids []string, excluded []string) []string {
+	if len(excluded) == 0 {
+		return ids
+	}
+
+	skip := make(map[string]struct{}, len(excluded))
+	for _, id := range excluded {
+		skip[id] = struct{}{}
 	}
 
-	return nil
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := skip[id]; !ok {
+			result = append(result, id)
+		}
+	}
+	return result
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"time"
 
 	env "github.com/caarlos0/env/v10"
 )
@@ -14,6 +15,11 @@ type config struct {
 	Mongo mongoConf
 	// Nats holds the configuration for NATS.
 	Nats natsConf
+	// Recrawl holds the configuration for recrawling posted nodes.
+	Recrawl recrawlConf
+	// LinkCheck holds the configuration for checking the primary URLs of
+	// posted nodes.
+	LinkCheck linkCheckConf
 }
 
 type mongoConf struct {
@@ -36,6 +42,27 @@ type natsConf struct {
 	URL string `env:"NATS_URL,required"`
 }
 
+type recrawlConf struct {
+	// Interval is how long a posted node waits before it is crawled again.
+	Interval time.Duration `env:"RECRAWL_INTERVAL,required"`
+	// BatchSize is the maximum number of nodes sent for validation per run.
+	BatchSize int `env:"RECRAWL_BATCH_SIZE,required"`
+	// HostConcurrency is the maximum number of nodes of the same host sent
+	// for validation per run.
+	HostConcurrency int `env:"RECRAWL_HOST_CONCURRENCY,required"`
+}
+
+type linkCheckConf struct {
+	// Interval is how long a primary URL waits before it is checked again.
+	Interval time.Duration `env:"LINK_CHECK_INTERVAL,required"`
+	// BatchSize is the maximum number of primary URLs checked per run.
+	BatchSize int `env:"LINK_CHECK_BATCH_SIZE,required"`
+	// Concurrency is the number of primary URLs checked at the same time.
+	Concurrency int `env:"LINK_CHECK_CONCURRENCY,required"`
+	// Timeout is the time a primary URL has to respond.
+	Timeout time.Duration `env:"LINK_CHECK_TIMEOUT,required"`
+}
+
 // Init initializes the Conf variable by parsing environment variables.
 func Init() {
 	if err := env.Parse(&Values); err != nil {
@@ -2,10 +2,14 @@ package model
 
 // Node represents a node in the system with relevant attributes.
 type Node struct {
+	// ID is the unique identifier of the node.
+	ID string `json:"-"           bson:"_id,omitempty"`
 	// URL of the node's profile.
 	ProfileURL string `json:"profile_url" bson:"profile_url,omitempty"`
 	// Status of the node.
 	Status string `json:"status"      bson:"status,omitempty"`
+	// PrimaryURL of the node's profile, as published.
+	PrimaryURL string `json:"-"           bson:"primary_url,omitempty"`
 	// Version is the version vector of the node.
 	// https://en.wikipedia.org/wiki/Version_vector
 	Version *int32 `json:"-"           bson:"__v,omitempty"`
@@ -12,6 +12,14 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/revalidatenode/internal/model"
 )
 
+// lastCrawledField stores when a posted node was last sent for validation
+// by the recrawler.
+const lastCrawledField = "last_crawled"
+
+// lastCheckedField stores when the primary URL of a node was last checked.
+// It is set by the index.
+const lastCheckedField = "primary_url_check.last_checked"
+
 // NodeRepository defines methods to interact with node data in MongoDB.
 type NodeRepository interface {
 	FindByStatuses(
@@ -19,6 +27,17 @@ type NodeRepository interface {
 		statuses []string,
 		page, pageSize int,
 	) ([]*model.Node, error)
+	FindForRecrawl(
+		ctx context.Context,
+		crawledBefore int64,
+		limit int64,
+	) ([]*model.Node, error)
+	SetLastCrawled(ctx context.Context, ids []string, crawledAt int64) error
+	FindForLinkCheck(
+		ctx context.Context,
+		checkedBefore int64,
+		limit int64,
+	) ([]*model.Node, error)
 }
 
 // NewNodeRepository initializes and returns an instance of NodeRepository.
@@ -64,3 +83,96 @@ func (r *nodeRepository) FindByStatuses(
 
 	return nodes, nil
 }
+
+// FindForRecrawl retrieves up to limit posted nodes that haven't been crawled
+// since crawledBefore, least recently crawled first. Nodes that were never
+// crawled come first.
+func (r *nodeRepository) FindForRecrawl(
+	ctx context.Context,
+	crawledBefore int64,
+	limit int64,
+) ([]*model.Node, error) {
+	filter := bson.M{
+		"status": constant.NodeStatus.Posted,
+		"$or": bson.A{
+			bson.M{lastCrawledField: bson.M{"$exists": false}},
+			bson.M{lastCrawledField: bson.M{"$lt": crawledBefore}},
+		},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: lastCrawledField, Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit)
+
+	cur, err := r.client.Database(config.Values.Mongo.DBName).
+		Collection(constant.MongoIndex.Node).
+		Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var nodes []*model.Node
+	if err := cur.All(ctx, &nodes); err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// SetLastCrawled records when the nodes with the given IDs were sent for
+// validation. The version of the nodes is left unchanged, so the validation
+// results still apply to them.
+func (r *nodeRepository) SetLastCrawled(
+	ctx context.Context,
+	ids []string,
+	crawledAt int64,
+) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": ids}}
+	update := bson.M{"$set": bson.M{lastCrawledField: crawledAt}}
+
+	_, err := r.client.Database(config.Values.Mongo.DBName).
+		Collection(constant.MongoIndex.Node).
+		UpdateMany(ctx, filter, update)
+	return err
+}
+
+// FindForLinkCheck retrieves up to limit posted nodes with a primary URL that
+// hasn't been checked since checkedBefore, least recently checked first.
+// Primary URLs that were never checked come first.
+func (r *nodeRepository) FindForLinkCheck(
+	ctx context.Context,
+	checkedBefore int64,
+	limit int64,
+) ([]*model.Node, error) {
+	filter := bson.M{
+		"status":      constant.NodeStatus.Posted,
+		"primary_url": bson.M{"$gt": ""},
+		"$or": bson.A{
+			bson.M{lastCheckedField: bson.M{"$exists": false}},
+			bson.M{lastCheckedField: bson.M{"$lt": checkedBefore}},
+		},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: lastCheckedField, Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{"_id": 1, "profile_url": 1, "primary_url": 1})
+
+	cur, err := r.client.Database(config.Values.Mongo.DBName).
+		Collection(constant.MongoIndex.Node).
+		Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var nodes []*model.Node
+	if err := cur.All(ctx, &nodes); err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
@@ -3,26 +3,42 @@ package service
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/urlutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/revalidatenode/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/revalidatenode/internal/model"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/revalidatenode/internal/repository/mongo"
 )
 
 // NodeService outlines methods to interact with node data.
 type NodeService interface {
 	RevalidateNodes() error
+	RecrawlNodes() error
+	CheckPrimaryURLs() error
 }
 
 // nodeService implements NodeService with a mongo.NodeRepository.
 type nodeService struct {
 	mongoRepo mongo.NodeRepository
+	fetcher   *httputil.Fetcher
 }
 
 // NewNodeService initializes a new nodeService instance.
 func NewNodeService(mongoRepo mongo.NodeRepository) NodeService {
-	return &nodeService{mongoRepo: mongoRepo}
+	return &nodeService{
+		mongoRepo: mongoRepo,
+		fetcher: httputil.NewFetcher(httputil.FetcherOptions{
+			Timeout: config.Values.LinkCheck.Timeout,
+		}),
+	}
 }
 
 // RevalidateNodes processes nodes with specific statuses and sends them for re-validation.
@@ -52,10 +68,9 @@ func (svc *nodeService) RevalidateNodes() error {
 
 		logger.Info(
 			fmt.Sprintf(
-				"Found %d nodes with status %s or %s on page %d, sending them to validation service",
+				"Found %d nodes with status %s on page %d, sending them to validation service",
 				len(nodes),
-				statuses[0],
-				statuses[1],
+				strings.Join(statuses, " or "),
 				page,
 			),
 		)
@@ -82,3 +97,171 @@ func (svc *nodeService) RevalidateNodes() error {
 
 	return nil
 }
+
+// recrawlScanFactor is how many candidates are read per node that can be
+// sent for validation, so that a host with many nodes doesn't starve others.
+const recrawlScanFactor = 10
+
+// RecrawlNodes sends the posted nodes that were crawled least recently for
+// validation again, so that changed, moved and vanished profiles are noticed.
+// At most config.Values.Recrawl.HostConcurrency nodes of the same host are
+// sent per run.
+func (svc *nodeService) RecrawlNodes() error {
+	ctx := context.Background()
+	batchSize := config.Values.Recrawl.BatchSize
+	crawledBefore := time.Now().Add(-config.Values.Recrawl.Interval).Unix()
+
+	candidates, err := svc.mongoRepo.FindForRecrawl(
+		ctx,
+		crawledBefore,
+		int64(batchSize*recrawlScanFactor),
+	)
+	if err != nil {
+		return err
+	}
+
+	nodes := selectForRecrawl(
+		candidates,
+		batchSize,
+		config.Values.Recrawl.HostConcurrency,
+	)
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.ID)
+	}
+	if err := svc.mongoRepo.SetLastCrawled(ctx, ids, time.Now().Unix()); err != nil {
+		return err
+	}
+
+	logger.Info(fmt.Sprintf(
+		"Found %d posted nodes to recrawl, sending them to validation service",
+		len(nodes),
+	))
+
+	for _, node := range nodes {
+		err := messaging.PublishSync(
+			messaging.NodeCreated,
+			messaging.NodeCreatedData{
+				ProfileURL: node.ProfileURL,
+				Version:    *node.Version,
+			},
+		)
+		if err != nil {
+			logger.Error("Failed to publish node:created event: ", err)
+		}
+	}
+
+	return nil
+}
+
+// selectForRecrawl picks up to batchSize nodes, in order, taking at most
+// hostConcurrency nodes of the same profile URL host.
+func selectForRecrawl(
+	candidates []*model.Node,
+	batchSize int,
+	hostConcurrency int,
+) []*model.Node {
+	selected := make([]*model.Node, 0, batchSize)
+	perHost := make(map[string]int)
+
+	for _, node := range candidates {
+		if len(selected) >= batchSize {
+			break
+		}
+		if node.Version == nil {
+			continue
+		}
+		host := profileHost(node.ProfileURL)
+		if perHost[host] >= hostConcurrency {
+			continue
+		}
+		perHost[host]++
+		selected = append(selected, node)
+	}
+
+	return selected
+}
+
+// profileHost returns the lowercased host of the profile URL.
+func profileHost(profileURL string) string {
+	u, err := url.Parse(profileURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// CheckPrimaryURLs checks that the primary URLs of the posted nodes that were
+// checked least recently are reachable and sends the results to the index.
+func (svc *nodeService) CheckPrimaryURLs() error {
+	checkedBefore := time.Now().Add(-config.Values.LinkCheck.Interval).Unix()
+
+	nodes, err := svc.mongoRepo.FindForLinkCheck(
+		context.Background(),
+		checkedBefore,
+		int64(config.Values.LinkCheck.BatchSize),
+	)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	logger.Info(fmt.Sprintf("Found %d primary URLs to check", len(nodes)))
+
+	concurrency := config.Values.LinkCheck.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	jobs := make(chan *model.Node)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for node := range jobs {
+				svc.checkPrimaryURL(node)
+			}
+		}()
+	}
+	for _, node := range nodes {
+		jobs <- node
+	}
+	close(jobs)
+	wg.Wait()
+
+	return nil
+}
+
+// checkPrimaryURL checks the primary URL of the node and publishes the
+// result.
+func (svc *nodeService) checkPrimaryURL(node *model.Node) {
+	data := messaging.NodeLinkCheckedData{
+		NodeID:     node.ID,
+		PrimaryURL: node.PrimaryURL,
+		CheckedAt:  time.Now().Unix(),
+	}
+
+	info, err := svc.fetcher.CheckURL(urlutil.LinkURL(node.PrimaryURL))
+	if info != nil {
+		data.StatusCode = info.StatusCode
+	}
+	data.Reachable = err == nil
+	if err != nil {
+		logger.Info(fmt.Sprintf(
+			"Primary URL '%s' of node '%s' is not reachable: %v",
+			node.PrimaryURL,
+			node.ProfileURL,
+			err,
+		))
+	}
+
+	if err := messaging.PublishSync(messaging.NodeLinkChecked, data); err != nil {
+		logger.Error("Failed to publish node:link_checked event: ", err)
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/revalidatenode/internal/model"
+)
+
+func TestSelectForRecrawl(t *testing.T) {
+	version := int32(1)
+	newNode := func(id, profileURL string) *model.Node {
+		return &model.Node{ID: id, ProfileURL: profileURL, Version: &version}
+	}
+
+	candidates := []*model.Node{
+		newNode("1", "https://a.org/1.json"),
+		newNode("2", "https://A.org/2.json"),
+		newNode("3", "https://a.org/3.json"),
+		newNode("4", "https://b.org/4.json"),
+		{ID: "5", ProfileURL: "https://c.org/5.json"},
+		newNode("6", "https://c.org/6.json"),
+		newNode("7", "https://d.org/7.json"),
+	}
+
+	tests := []struct {
+		name            string
+		batchSize       int
+		hostConcurrency int
+		expected        []string
+	}{
+		{
+			name:            "Host concurrency",
+			batchSize:       10,
+			hostConcurrency: 2,
+			expected:        []string{"1", "2", "4", "6", "7"},
+		},
+		{
+			name:            "Batch size",
+			batchSize:       3,
+			hostConcurrency: 1,
+			expected:        []string{"1", "4", "6"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected := selectForRecrawl(candidates, tt.batchSize, tt.hostConcurrency)
+			ids := make([]string, 0, len(selected))
+			for _, node := range selected {
+				ids = append(ids, node.ID)
+			}
+			require.Equal(t, tt.expected, ids)
+		})
+	}
+}
@@ -65,6 +65,14 @@ func (nc *NodeRevalidationCron) Run() error {
 		return err
 	}
 
+	if err := nodeService.RecrawlNodes(); err != nil {
+		return err
+	}
+
+	if err := nodeService.CheckPrimaryURLs(); err != nil {
+		return err
+	}
+
 	// Perform cleanup after running the service.
 	nc.cleanup()
 
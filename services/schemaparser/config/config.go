@@ -6,8 +6,15 @@ type config struct {
 	Library libraryConf
 	Mongo   mongoConf
 	Redis   redisConf
-	Github  githubConf
-	IsLocal bool `env:"IS_LOCAL,required"`
+	// Redis of the validation service, which is told when schemas change
+	ValidationRedis validationRedisConf
+	Github          githubConf
+	// NATS, which the index listens to for updated schemas
+	Nats natsConf
+	// Repositories the schemas and fields are read from, each of the form
+	// [<namespace>=]<kind>:<location>, where kind is github, local, git or
+	// tarball
+	Sources []string `env:"SCHEMA_SOURCES,required" envSeparator:","`
 }
 
 type libraryConf struct {
@@ -25,8 +32,16 @@ type redisConf struct {
 	URL string `env:"REDIS_URL,required"`
 }
 
+type validationRedisConf struct {
+	URL string `env:"VALIDATION_REDIS_URL,required"`
+}
+
+type natsConf struct {
+	URL string `env:"NATS_URL,required"`
+}
+
 type githubConf struct {
-	TOKEN     string `env:"GITHUB_TOKEN,required"`
-	BranchURL string `env:"GITHUB_BRANCH_URL,required"`
-	TreeURL   string `env:"GITHUB_TREE_URL,required"`
+	// Token of the requests to the GitHub API, optional for public
+	// repositories
+	TOKEN string `env:"GITHUB_TOKEN"`
 }
@@ -1,6 +1,10 @@
 package model
 
-import "go.mongodb.org/mongo-driver/bson"
+import (
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemadiff"
+)
 
 type Schema struct {
 	Title       string `bson:"title,omitempty"`
@@ -8,17 +12,77 @@ type Schema struct {
 	Name        string `bson:"name,omitempty"`
 	URL         string `bson:"url,omitempty"`
 	FullSchema  bson.D `bson:"full_schema,omitempty"`
+	// Revision identifies the content of the full schema.
+	Revision string `bson:"revision,omitempty"`
+	// Compatibility reports the changes from the previous version.
+	Compatibility *schemadiff.Report `bson:"compatibility,omitempty"`
+}
+
+// SchemaRevision is a content of a schema, along with the loads of the
+// sources that changed the schema to it. A schema changing back to a
+// previous content gets the previous revision.
+type SchemaRevision struct {
+	Name string `bson:"name"`
+	// Revision identifies the content, it hashes the full schema.
+	Revision    string       `bson:"revision"`
+	Title       string       `bson:"title,omitempty"`
+	Description string       `bson:"description,omitempty"`
+	URL         string       `bson:"url,omitempty"`
+	FullSchema  bson.D       `bson:"full_schema"`
+	Loads       []SchemaLoad `bson:"loads"`
+}
+
+// SchemaLoad records a load of the sources.
+type SchemaLoad struct {
+	// Commit is the version of the source of the schema, e.g. a commit SHA.
+	Commit string `bson:"commit"`
+	// Version is the version of all the sources.
+	Version  string `bson:"version"`
+	LoadedAt int64  `bson:"loaded_at"`
+}
+
+// SourcesLoad records an update of the schemas from the sources, so that the
+// revisions of the schemas can be found by the version or commits the
+// sources had, whether the schemas changed or not.
+type SourcesLoad struct {
+	// Version is the version of all the sources.
+	Version string `bson:"version"`
+	// Commits are the versions of each source, e.g. commit SHAs.
+	Commits  []string `bson:"commits"`
+	LoadedAt int64    `bson:"loaded_at"`
 }
 
-type BranchInfo struct {
-	Commit struct {
-		Sha         string `json:"sha"`
-		InnerCommit struct {
-			Author struct {
-				Date string `json:"date"`
-			} `json:"author"`
-		} `json:"commit"`
-	} `json:"commit"`
+// Field is a shared field definition, from the fields folder of the
+// library, along with the schemas that use it.
+type Field struct {
+	Name        string   `bson:"name,omitempty"`
+	Title       string   `bson:"title,omitempty"`
+	Description string   `bson:"description,omitempty"`
+	Type        string   `bson:"type,omitempty"`
+	FullField   bson.D   `bson:"full_field,omitempty"`
+	UsedBy      []string `bson:"used_by"`
+}
+
+// Vocabulary is a controlled vocabulary, from the vocabularies folder of the
+// library, along with the schemas that use it. Its terms are the values the
+// properties referencing it allow.
+type Vocabulary struct {
+	Name        string   `json:"name" bson:"name"`
+	Title       string   `json:"title,omitempty" bson:"title,omitempty"`
+	Description string   `json:"description,omitempty" bson:"description,omitempty"`
+	Terms       []Term   `json:"terms" bson:"terms"`
+	UsedBy      []string `json:"-" bson:"used_by"`
+}
+
+// Term is a term of a vocabulary. Its broader and narrower terms are
+// identified by their IDs, and a term is listed by the terms it relates to
+// as well, whichever of them declares the relation.
+type Term struct {
+	ID string `json:"id" bson:"id"`
+	// Labels maps language tags, e.g. "en", to the labels of the term.
+	Labels   map[string]string `json:"labels,omitempty" bson:"labels,omitempty"`
+	Broader  []string          `json:"broader,omitempty" bson:"broader,omitempty"`
+	Narrower []string          `json:"narrower,omitempty" bson:"narrower,omitempty"`
 }
 
 type SchemaJSON struct {
@@ -36,3 +100,28 @@ type InnerSchema struct {
 	Version int    `json:"version"`
 	URL     string `json:"url"`
 }
+
+// UpdateError is the error that stopped an update of the schemas. The next
+// updates wait until it is resolved.
+type UpdateError struct {
+	Message string `json:"message"`
+	// Schema is the name of the schema or field that failed, if any.
+	Schema string `json:"schema,omitempty"`
+	// Version is the version of the sources being loaded, e.g. a commit.
+	Version string `json:"version,omitempty"`
+	// Issues are the problems found when linting the schemas and fields.
+	Issues    []LintIssue `json:"issues,omitempty"`
+	CreatedAt int64       `json:"created_at,omitempty"`
+}
+
+// LintIssue is a problem found in a schema or field before it is published.
+type LintIssue struct {
+	// Rule is the check that failed, e.g. "unresolved_ref".
+	Rule string `json:"rule"`
+	// File is the path of the schema or field, e.g. "schemas/x-v1.0.0.json",
+	// prefixed with the namespace of its source.
+	File string `json:"file"`
+	// Pointer is the JSON pointer to the offending value in the file.
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
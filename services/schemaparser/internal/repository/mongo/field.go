@@ -0,0 +1,46 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
+)
+
+type FieldRepository interface {
+	Update(field *model.Field) error
+	// DeleteOthers deletes the fields whose names aren't given.
+	DeleteOthers(names []string) error
+}
+
+func NewFieldRepository() FieldRepository {
+	return &fieldRepository{}
+}
+
+type fieldRepository struct {
+}
+
+func (r *fieldRepository) Update(field *model.Field) error {
+	filter := bson.M{"name": field.Name}
+	update := bson.M{"$set": field}
+	opt := options.FindOneAndUpdate().SetUpsert(true)
+
+	_, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.Field,
+		filter,
+		update,
+		opt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *fieldRepository) DeleteOthers(names []string) error {
+	filter := bson.M{"name": bson.M{"$nin": names}}
+	return mongo.Client.DeleteMany(constant.MongoIndex.Field, filter)
+}
@@ -0,0 +1,121 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
+)
+
+type SchemaRevisionRepository interface {
+	// Record stores the revision if it is new. The load is added to it if it
+	// changed the schema to the revision, so that the loads of a revision
+	// only grow when the schema changes.
+	Record(
+		revision *model.SchemaRevision,
+		load model.SchemaLoad,
+		changed bool,
+	) error
+	// RecordLoad logs an update of the schemas from the sources.
+	RecordLoad(load *model.SourcesLoad) error
+	// FindLoad returns the last logged update of the schemas from the sources
+	// at the version, or nil if there is none.
+	FindLoad(version string) (*model.SourcesLoad, error)
+	// FindAll returns the names and loads of all revisions, without their
+	// full schemas.
+	FindAll() ([]*model.SchemaRevision, error)
+}
+
+func NewSchemaRevisionRepository() SchemaRevisionRepository {
+	return &schemaRevisionRepository{}
+}
+
+type schemaRevisionRepository struct {
+}
+
+func (r *schemaRevisionRepository) Record(
+	revision *model.SchemaRevision,
+	load model.SchemaLoad,
+	changed bool,
+) error {
+	filter := bson.M{"name": revision.Name, "revision": revision.Revision}
+	setOnInsert := bson.M{
+		"title":       revision.Title,
+		"description": revision.Description,
+		"url":         revision.URL,
+		"full_schema": revision.FullSchema,
+	}
+	update := bson.M{"$setOnInsert": setOnInsert}
+	if changed {
+		update["$push"] = bson.M{"loads": load}
+	} else {
+		// The revision of a schema stored before revisions were recorded
+		// starts with this load.
+		setOnInsert["loads"] = []model.SchemaLoad{load}
+	}
+	opt := options.FindOneAndUpdate().SetUpsert(true)
+
+	_, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.SchemaRevision,
+		filter,
+		update,
+		opt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *schemaRevisionRepository) RecordLoad(load *model.SourcesLoad) error {
+	_, err := mongo.Client.InsertOne(constant.MongoIndex.SchemaLoad, load)
+	return err
+}
+
+func (r *schemaRevisionRepository) FindAll() ([]*model.SchemaRevision, error) {
+	opts := options.Find().SetProjection(bson.M{"name": 1, "revision": 1, "loads": 1})
+
+	cur, err := mongo.Client.Find(constant.MongoIndex.SchemaRevision, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	var revisions []*model.SchemaRevision
+	if err := cur.All(context.Background(), &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *schemaRevisionRepository) FindLoad(
+	version string,
+) (*model.SourcesLoad, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "loaded_at", Value: -1}}).
+		SetLimit(1)
+
+	cur, err := mongo.Client.Find(
+		constant.MongoIndex.SchemaLoad,
+		bson.M{"version": version},
+		opts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	var loads []*model.SourcesLoad
+	if err := cur.All(context.Background(), &loads); err != nil {
+		return nil, err
+	}
+	if len(loads) == 0 {
+		return nil, nil
+	}
+	return loads[0], nil
+}
@@ -1,16 +1,25 @@
 package mongo
 
 import (
+	"bytes"
+	"context"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemadiff"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
 )
 
 type SchemaRepository interface {
-	Update(schema *model.Schema) error
+	// Update adds or updates the schema and reports whether its full schema
+	// was added or changed.
+	Update(schema *model.Schema) (bool, error)
+	// FindAll returns the names and full schemas of all schemas.
+	FindAll() ([]*model.Schema, error)
+	UpdateCompatibility(name string, report *schemadiff.Report) error
 }
 
 func NewSchemaRepository() SchemaRepository {
@@ -20,17 +29,88 @@ func NewSchemaRepository() SchemaRepository {
 type schemaRepository struct {
 }
 
-func (r *schemaRepository) Update(schema *model.Schema) error {
+func (r *schemaRepository) Update(schema *model.Schema) (bool, error) {
 	filter := bson.M{"name": schema.Name}
+
+	changed, err := r.hasChanged(schema)
+	if err != nil {
+		return false, err
+	}
+
 	update := bson.M{"$set": schema}
 	opt := options.FindOneAndUpdate().SetUpsert(true)
 
-	_, err := mongo.Client.FindOneAndUpdate(
+	_, err = mongo.Client.FindOneAndUpdate(
 		constant.MongoIndex.Schema,
 		filter,
 		update,
 		opt,
 	)
+	if err != nil {
+		return false, err
+	}
+
+	return changed, nil
+}
+
+// hasChanged reports whether the full schema differs from the stored one, or
+// isn't stored yet.
+func (r *schemaRepository) hasChanged(schema *model.Schema) (bool, error) {
+	filter := bson.M{"name": schema.Name}
+	var stored model.Schema
+	err := mongo.Client.FindOne(constant.MongoIndex.Schema, filter).Decode(&stored)
+	if err == mongo.ErrNoDocuments {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	storedBytes, err := bson.Marshal(stored.FullSchema)
+	if err != nil {
+		return false, err
+	}
+	newBytes, err := bson.Marshal(schema.FullSchema)
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(storedBytes, newBytes), nil
+}
+
+func (r *schemaRepository) FindAll() ([]*model.Schema, error) {
+	opts := options.Find().SetProjection(bson.M{"name": 1, "full_schema": 1})
+
+	cur, err := mongo.Client.Find(constant.MongoIndex.Schema, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(context.Background())
+
+	var schemas []*model.Schema
+	if err := cur.All(context.Background(), &schemas); err != nil {
+		return nil, err
+	}
+
+	return schemas, nil
+}
+
+func (r *schemaRepository) UpdateCompatibility(
+	name string,
+	report *schemadiff.Report,
+) error {
+	filter := bson.M{"name": name}
+	var update bson.M
+	if report == nil {
+		update = bson.M{"$unset": bson.M{"compatibility": ""}}
+	} else {
+		update = bson.M{"$set": bson.M{"compatibility": report}}
+	}
+
+	_, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.Schema,
+		filter,
+		update,
+	)
 	if err != nil {
 		return err
 	}
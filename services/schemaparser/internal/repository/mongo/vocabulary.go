@@ -0,0 +1,46 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/constant"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
+)
+
+type VocabularyRepository interface {
+	Update(vocabulary *model.Vocabulary) error
+	// DeleteOthers deletes the vocabularies whose names aren't given.
+	DeleteOthers(names []string) error
+}
+
+func NewVocabularyRepository() VocabularyRepository {
+	return &vocabularyRepository{}
+}
+
+type vocabularyRepository struct {
+}
+
+func (r *vocabularyRepository) Update(vocabulary *model.Vocabulary) error {
+	filter := bson.M{"name": vocabulary.Name}
+	update := bson.M{"$set": vocabulary}
+	opt := options.FindOneAndUpdate().SetUpsert(true)
+
+	_, err := mongo.Client.FindOneAndUpdate(
+		constant.MongoIndex.Vocabulary,
+		filter,
+		update,
+		opt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *vocabularyRepository) DeleteOthers(names []string) error {
+	filter := bson.M{"name": bson.M{"$nin": names}}
+	return mongo.Client.DeleteMany(constant.MongoIndex.Vocabulary, filter)
+}
@@ -0,0 +1,606 @@
+package schemaparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaname"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
+)
+
+// Lint rules.
+const (
+	// RuleInvalidJSON reports a file that isn't a JSON object.
+	RuleInvalidJSON = "invalid_json"
+	// RuleInvalidSchema reports a schema or field that doesn't compile.
+	RuleInvalidSchema = "invalid_schema"
+	// RuleUnresolvedRef reports a $ref to a missing field or location.
+	RuleUnresolvedRef = "unresolved_ref"
+	// RuleUndefinedRequired reports a required property that isn't defined.
+	RuleUndefinedRequired = "undefined_required"
+	// RuleMissingName reports a schema without metadata.schema.name.
+	RuleMissingName = "missing_name"
+	// RuleNameMismatch reports a schema whose name isn't its file name.
+	RuleNameMismatch = "name_mismatch"
+	// RuleInvalidVersion reports a schema name without a full version.
+	RuleInvalidVersion = "invalid_version"
+	// RuleMissingLinkedSchemas reports a schema without a linked_schemas
+	// property.
+	RuleMissingLinkedSchemas = "missing_linked_schemas"
+	// RuleInvalidExample reports an example that the schema rejects.
+	RuleInvalidExample = "invalid_example"
+	// RuleUnresolvedVocabulary reports a reference to a missing vocabulary.
+	RuleUnresolvedVocabulary = "unresolved_vocabulary"
+	// RuleVocabularyType reports a vocabulary referenced by a schema that
+	// doesn't only allow strings.
+	RuleVocabularyType = "vocabulary_type"
+	// RuleInvalidTerm reports a term of a vocabulary without an ID.
+	RuleInvalidTerm = "invalid_term"
+	// RuleDuplicateTerm reports a term defined more than once.
+	RuleDuplicateTerm = "duplicate_term"
+	// RuleUnknownTerm reports a broader or narrower term that isn't defined.
+	RuleUnknownTerm = "unknown_term"
+	// RuleTermCycle reports a term that is broader than itself.
+	RuleTermCycle = "term_cycle"
+)
+
+// lintLocation is the location the resolved schemas are compiled at to
+// validate their examples.
+const lintLocation = "urn:murmurations:lint"
+
+// instanceKeys are the keywords whose values are instances rather than
+// schemas, so they are not checked like schemas.
+var instanceKeys = map[string]bool{
+	"const":    true,
+	"default":  true,
+	"enum":     true,
+	"examples": true,
+}
+
+// LintSchema checks a schema before it is published: its references resolve
+// among the fields and vocabularies, keyed by file name, its required
+// properties are defined, its name is its file name and has a version, it
+// has a linked_schemas property and its examples are valid. The file is the
+// path of the schema reported with the issues, e.g.
+// "schemas/test_schema-v1.0.0.json".
+func LintSchema(
+	file string,
+	schema []byte,
+	fields map[string][]byte,
+	vocabularies map[string][]byte,
+) []model.LintIssue {
+	l := newLinter(file, fields, vocabularies)
+	doc, ok := l.decode(schema)
+	if !ok {
+		return l.issues
+	}
+
+	metadata, _ := doc["metadata"].(map[string]interface{})
+	schemaMetadata, _ := metadata["schema"].(map[string]interface{})
+	name, _ := schemaMetadata["name"].(string)
+	l.checkName(name, "/metadata/schema/name", "schema")
+	if properties, ok := doc[PropertyKey].(map[string]interface{}); !ok ||
+		properties["linked_schemas"] == nil {
+		l.add(
+			RuleMissingLinkedSchemas,
+			"/"+PropertyKey,
+			"the schema has no linked_schemas property",
+		)
+	}
+	l.walk(doc, "", doc)
+	if len(l.issues) > 0 {
+		return l.issues
+	}
+
+	result, err := NewSchemaParser().GetLocalSchema(schema, fields)
+	if err != nil {
+		l.add(RuleInvalidSchema, "", "%v", err)
+		return l.issues
+	}
+	l.checkExamples(result.FullJSON)
+	return l.issues
+}
+
+// LintField checks a field like the properties of a schema: its references
+// resolve, its required properties are defined and its examples are valid.
+// The file is the path of the field reported with the issues, e.g.
+// "fields/name.json".
+func LintField(
+	file string,
+	field []byte,
+	fields map[string][]byte,
+	vocabularies map[string][]byte,
+) []model.LintIssue {
+	l := newLinter(file, fields, vocabularies)
+	doc, ok := l.decode(field)
+	if !ok {
+		return l.issues
+	}
+
+	l.walk(doc, "", doc)
+	if len(l.issues) > 0 {
+		return l.issues
+	}
+
+	fullField, err := NewSchemaParser().GetField(path.Base(file), fields)
+	if err != nil {
+		l.add(RuleInvalidSchema, "", "%v", err)
+		return l.issues
+	}
+	l.checkExamples(fullField)
+	return l.issues
+}
+
+// LintVocabulary checks a vocabulary before it is published: its name is its
+// file name and has a version, its terms have distinct IDs, their broader
+// and narrower terms are defined and no term is broader than itself. The
+// file is the path of the vocabulary reported with the issues, e.g.
+// "vocabularies/kvm_categories-v1.0.0.json".
+func LintVocabulary(file string, data []byte) []model.LintIssue {
+	l := &linter{file: file}
+	if _, ok := l.decode(data); !ok {
+		return l.issues
+	}
+	var vocabulary model.Vocabulary
+	if err := json.Unmarshal(data, &vocabulary); err != nil {
+		l.add(RuleInvalidJSON, "", "%v", err)
+		return l.issues
+	}
+
+	l.checkName(vocabulary.Name, "/name", "vocabulary")
+	ids := make(map[string]bool, len(vocabulary.Terms))
+	for i, term := range vocabulary.Terms {
+		pointer := "/terms/" + strconv.Itoa(i) + "/id"
+		switch {
+		case term.ID == "":
+			l.add(RuleInvalidTerm, pointer, "the term has no id")
+		case ids[term.ID]:
+			l.add(RuleDuplicateTerm, pointer, "the term %s is already defined", term.ID)
+		}
+		ids[term.ID] = true
+	}
+	for i, term := range vocabulary.Terms {
+		relations := []struct {
+			key string
+			ids []string
+		}{
+			{"broader", term.Broader},
+			{"narrower", term.Narrower},
+		}
+		for _, relation := range relations {
+			for j, id := range relation.ids {
+				if !ids[id] {
+					l.add(
+						RuleUnknownTerm,
+						fmt.Sprintf("/terms/%d/%s/%d", i, relation.key, j),
+						"the term %s is not defined",
+						id,
+					)
+				}
+			}
+		}
+	}
+
+	linked := vocabulary
+	linked.Terms = append([]model.Term(nil), vocabulary.Terms...)
+	linkTerms(&linked)
+	broader := make(map[string][]string, len(linked.Terms))
+	for _, term := range linked.Terms {
+		broader[term.ID] = term.Broader
+	}
+	for i, term := range linked.Terms {
+		if broaderThanItself(broader, term.ID) {
+			l.add(
+				RuleTermCycle,
+				"/terms/"+strconv.Itoa(i),
+				"the term %s is broader than itself",
+				term.ID,
+			)
+		}
+	}
+	return l.issues
+}
+
+// broaderThanItself reports whether the term is among its transitively
+// broader terms, given the broader terms of each term.
+func broaderThanItself(broader map[string][]string, id string) bool {
+	visited := make(map[string]bool)
+	queue := append([]string{}, broader[id]...)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == id {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		queue = append(queue, broader[current]...)
+	}
+	return false
+}
+
+// linter collects the issues of a file.
+type linter struct {
+	file   string
+	fields map[string][]byte
+	// vocabularies are the vocabularies the file can reference, keyed by
+	// name. Those that fail to parse have no terms and are reported by
+	// LintVocabulary.
+	vocabularies Vocabularies
+	issues       []model.LintIssue
+}
+
+func newLinter(
+	file string,
+	fields map[string][]byte,
+	vocabularies map[string][]byte,
+) *linter {
+	l := &linter{
+		file:         file,
+		fields:       fields,
+		vocabularies: make(Vocabularies, len(vocabularies)),
+	}
+	for fileName, data := range vocabularies {
+		name := VocabularyName(fileName)
+		vocabulary, err := ParseVocabulary(data)
+		if err != nil {
+			vocabulary = &model.Vocabulary{}
+		}
+		vocabulary.Name = name
+		l.vocabularies[name] = vocabulary
+	}
+	return l
+}
+
+func (l *linter) add(rule, pointer, format string, args ...interface{}) {
+	l.issues = append(l.issues, model.LintIssue{
+		Rule:    rule,
+		File:    l.file,
+		Pointer: pointer,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// decode decodes the file, which must be a JSON object.
+func (l *linter) decode(data []byte) (map[string]interface{}, bool) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		l.add(RuleInvalidJSON, "", "%v", err)
+		return nil, false
+	}
+	return doc, true
+}
+
+// checkName checks that the name of the schema or vocabulary, at the
+// pointer, is the file name and has a version.
+func (l *linter) checkName(name, pointer, kind string) {
+	if name == "" {
+		l.add(RuleMissingName, pointer, "the %s has no name", kind)
+		return
+	}
+
+	fileName := strings.TrimSuffix(path.Base(l.file), ".json")
+	if name != fileName {
+		l.add(
+			RuleNameMismatch,
+			pointer,
+			"the name %s doesn't match the file name %s",
+			name,
+			fileName,
+		)
+	}
+	if parsed, ok := schemaname.Parse(name); !ok || parsed.IsRange {
+		l.add(
+			RuleInvalidVersion,
+			pointer,
+			"the name %s doesn't end with a version, e.g. -v1.0.0",
+			name,
+		)
+	}
+}
+
+// walk checks the references, vocabularies and required properties of the
+// schemas nested in the value.
+func (l *linter) walk(value interface{}, pointer string, root interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v[ReferenceKey].(string); ok {
+			l.checkRef(ref, pointer+"/"+escapePointer(ReferenceKey), root)
+		}
+		if ref, ok := v[VocabularyKey].(string); ok {
+			l.checkVocabulary(v, ref, pointer+"/"+VocabularyKey)
+		}
+		l.checkRequired(v, pointer)
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if instanceKeys[key] {
+				continue
+			}
+			l.walk(v[key], pointer+"/"+escapePointer(key), root)
+		}
+	case []interface{}:
+		for i, item := range v {
+			l.walk(item, pointer+"/"+strconv.Itoa(i), root)
+		}
+	}
+}
+
+// checkRef checks that a reference points to a location in the file or to
+// one of the fields.
+func (l *linter) checkRef(ref string, pointer string, root interface{}) {
+	if strings.HasPrefix(ref, "#") {
+		if _, ok := resolvePointer(root, strings.TrimPrefix(ref, "#")); !ok {
+			l.add(RuleUnresolvedRef, pointer, "%s doesn't resolve in the file", ref)
+		}
+		return
+	}
+	if _, ok := l.fields[path.Base(ref)]; !ok {
+		l.add(
+			RuleUnresolvedRef,
+			pointer,
+			"%s doesn't resolve to a field, %s not found",
+			ref,
+			path.Base(ref),
+		)
+	}
+}
+
+// checkVocabulary checks that a vocabulary reference resolves among the
+// vocabularies and is made by a schema that only allows strings.
+func (l *linter) checkVocabulary(
+	schema map[string]interface{},
+	ref string,
+	pointer string,
+) {
+	if _, ok := l.vocabularies.Resolve(ref); !ok {
+		l.add(
+			RuleUnresolvedVocabulary,
+			pointer,
+			"%s doesn't resolve to a vocabulary",
+			ref,
+		)
+	}
+
+	var types []interface{}
+	switch t := schema[TypeKey].(type) {
+	case nil:
+		return
+	case []interface{}:
+		types = t
+	default:
+		types = []interface{}{t}
+	}
+	for _, t := range types {
+		if t != "string" && t != "null" {
+			l.add(
+				RuleVocabularyType,
+				pointer,
+				"the terms of %s are strings, the schema allows %v",
+				ref,
+				t,
+			)
+			return
+		}
+	}
+}
+
+// checkRequired checks that the required properties of an object schema are
+// among its properties. Schemas referencing another one get their properties
+// from it and are checked with it.
+func (l *linter) checkRequired(schema map[string]interface{}, pointer string) {
+	required, ok := schema["required"].([]interface{})
+	if !ok || schema[ReferenceKey] != nil {
+		return
+	}
+	properties, hasProperties := schema[PropertyKey].(map[string]interface{})
+	if !hasProperties && schema[TypeKey] != ObjectType {
+		return
+	}
+
+	for i, property := range required {
+		name, ok := property.(string)
+		if !ok || properties[name] == nil {
+			l.add(
+				RuleUndefinedRequired,
+				pointer+"/required/"+strconv.Itoa(i),
+				"the required property %v is not defined",
+				property,
+			)
+		}
+	}
+}
+
+// checkExamples validates the examples of the resolved schema and of the
+// schemas nested in it against them, with the values of the vocabularies
+// restricted to their terms.
+func (l *linter) checkExamples(resolved bson.D) {
+	resolved, _, err := ApplyVocabularies(resolved, l.vocabularies)
+	if err != nil {
+		l.add(RuleInvalidSchema, "", "%v", err)
+		return
+	}
+	doc := toJSONValue(resolved)
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft7)
+	if err := compiler.AddResource(lintLocation, doc); err != nil {
+		l.add(RuleInvalidSchema, "", "%v", err)
+		return
+	}
+	if _, err := compiler.Compile(lintLocation); err != nil {
+		l.add(RuleInvalidSchema, "", "%v", err)
+		return
+	}
+
+	var walk func(value interface{}, pointer string)
+	walk = func(value interface{}, pointer string) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if examples, ok := v["examples"].([]interface{}); ok {
+				l.validateExamples(compiler, examples, pointer)
+			}
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if instanceKeys[key] {
+					continue
+				}
+				walk(v[key], pointer+"/"+escapePointer(key))
+			}
+		case []interface{}:
+			for i, item := range v {
+				walk(item, pointer+"/"+strconv.Itoa(i))
+			}
+		}
+	}
+	walk(doc, "")
+}
+
+// validateExamples validates the examples of the schema at the pointer.
+func (l *linter) validateExamples(
+	compiler *jsonschema.Compiler,
+	examples []interface{},
+	pointer string,
+) {
+	schema, err := compiler.Compile(lintLocation + "#" + fragment(pointer))
+	if err != nil {
+		l.add(RuleInvalidSchema, pointer, "%v", err)
+		return
+	}
+	for i, example := range examples {
+		if err := schema.Validate(example); err != nil {
+			l.add(
+				RuleInvalidExample,
+				pointer+"/examples/"+strconv.Itoa(i),
+				"the example is invalid: %s",
+				validationMessage(err),
+			)
+		}
+	}
+}
+
+// printer formats the messages of validation errors.
+var printer = message.NewPrinter(language.English)
+
+// validationMessage lists the causes of a validation error with the
+// locations they apply to.
+func validationMessage(err error) string {
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err.Error()
+	}
+
+	var messages []string
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			messages = append(messages, fmt.Sprintf(
+				"at '/%s': %s",
+				strings.Join(e.InstanceLocation, "/"),
+				e.ErrorKind.LocalizedString(printer),
+			))
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(validationErr)
+	return strings.Join(messages, "; ")
+}
+
+// toJSONValue converts the BSON documents built by the parser to the values
+// of decoded JSON.
+func toJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.D:
+		m := make(map[string]interface{}, len(v))
+		for _, elem := range v {
+			m[elem.Key] = toJSONValue(elem.Value)
+		}
+		return m
+	case orderedmap.OrderedMap:
+		m := make(map[string]interface{}, len(v.Keys()))
+		for _, key := range v.Keys() {
+			value, _ := v.Get(key)
+			m[key] = toJSONValue(value)
+		}
+		return m
+	case *orderedmap.OrderedMap:
+		return toJSONValue(*v)
+	case bson.A:
+		return toJSONValue([]interface{}(v))
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, item := range v {
+			s[i] = toJSONValue(item)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// resolvePointer returns the value at the JSON pointer in the document.
+func resolvePointer(doc interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return doc, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	value := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[token]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// escapePointer escapes a key to be used as a token of a JSON pointer.
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// fragment percent-encodes a JSON pointer to be used as a URL fragment.
+func fragment(pointer string) string {
+	tokens := strings.Split(pointer, "/")
+	for i, token := range tokens {
+		tokens[i] = url.PathEscape(token)
+	}
+	return strings.Join(tokens, "/")
+}
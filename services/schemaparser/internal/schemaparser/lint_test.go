@@ -0,0 +1,392 @@
+package schemaparser_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/schemaparser"
+)
+
+var lintFields = map[string][]byte{
+	"linked_schemas.json": []byte(`{
+		"title": "Linked Schemas",
+		"type": "array",
+		"items": {"type": "string"},
+		"examples": [["test_schema-v1.0.0"]]
+	}`),
+	"name.json": []byte(`{
+		"title": "Name",
+		"type": "string",
+		"maxLength": 10,
+		"examples": ["Jane"]
+	}`),
+	"geolocation.json": []byte(`{
+		"title": "Geolocation",
+		"type": "object",
+		"properties": {
+			"lat": {"type": "number"},
+			"lon": {"type": "number"}
+		},
+		"required": ["lat", "lon"]
+	}`),
+}
+
+var lintVocabularies = map[string][]byte{
+	"categories-v1.0.0.json": []byte(`{
+		"name": "categories-v1.0.0",
+		"terms": [
+			{"id": "non-profit", "labels": {"en": "Non-profit"}},
+			{"id": "cooperative", "broader": ["non-profit"]},
+			{"id": "commercial"}
+		]
+	}`),
+}
+
+func TestLintSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		schema   string
+		fields   map[string][]byte
+		expRules []string
+		expPaths []string
+	}{
+		{
+			name: "Valid schema",
+			file: "schemas/test_schema-v1.0.0.json",
+			schema: `{
+				"type": "object",
+				"properties": {
+					"linked_schemas": {"$ref": "../fields/linked_schemas.json"},
+					"name": {"$ref": "../fields/name.json", "title": "Full Name"},
+					"geolocation": {"$ref": "../fields/geolocation.json"}
+				},
+				"required": ["linked_schemas", "name"],
+				"examples": [{"linked_schemas": ["test_schema-v1.0.0"], "name": "Jane"}],
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+			}`,
+			fields: lintFields,
+		},
+		{
+			name:     "Invalid JSON",
+			file:     "schemas/test_schema-v1.0.0.json",
+			schema:   `{"type": "object",`,
+			expRules: []string{schemaparser.RuleInvalidJSON},
+			expPaths: []string{""},
+		},
+		{
+			name: "Missing name and linked_schemas",
+			file: "schemas/test_schema-v1.0.0.json",
+			schema: `{
+				"type": "object",
+				"properties": {"name": {"type": "string"}}
+			}`,
+			expRules: []string{
+				schemaparser.RuleMissingName,
+				schemaparser.RuleMissingLinkedSchemas,
+			},
+			expPaths: []string{"/metadata/schema/name", "/properties"},
+		},
+		{
+			name: "Name mismatch and missing version",
+			file: "fork:schemas/test_schema-v1.0.0.json",
+			schema: `{
+				"type": "object",
+				"properties": {"linked_schemas": {"type": "array"}},
+				"metadata": {"schema": {"name": "test_schema-v1"}}
+			}`,
+			expRules: []string{
+				schemaparser.RuleNameMismatch,
+				schemaparser.RuleInvalidVersion,
+			},
+			expPaths: []string{"/metadata/schema/name", "/metadata/schema/name"},
+		},
+		{
+			name: "Unresolved refs",
+			file: "schemas/test_schema-v1.0.0.json",
+			schema: `{
+				"type": "object",
+				"properties": {
+					"linked_schemas": {"$ref": "../fields/linked_schemas.json"},
+					"tags": {"$ref": "../fields/tags.json"},
+					"other": {"$ref": "#/definitions/other"}
+				},
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+			}`,
+			fields: lintFields,
+			expRules: []string{
+				schemaparser.RuleUnresolvedRef,
+				schemaparser.RuleUnresolvedRef,
+			},
+			expPaths: []string{"/properties/other/$ref", "/properties/tags/$ref"},
+		},
+		{
+			name: "Undefined required properties",
+			file: "schemas/test_schema-v1.0.0.json",
+			schema: `{
+				"type": "object",
+				"properties": {
+					"linked_schemas": {"type": "array"},
+					"address": {
+						"type": "object",
+						"properties": {"street": {"type": "string"}},
+						"required": ["street", "city"]
+					}
+				},
+				"required": ["linked_schemas", "primary_url"],
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+			}`,
+			expRules: []string{
+				schemaparser.RuleUndefinedRequired,
+				schemaparser.RuleUndefinedRequired,
+			},
+			expPaths: []string{"/required/1", "/properties/address/required/1"},
+		},
+		{
+			name: "Unresolved and mistyped vocabularies",
+			file: "schemas/test_schema-v1.0.0.json",
+			schema: `{
+				"type": "object",
+				"properties": {
+					"linked_schemas": {"type": "array"},
+					"category": {"type": "string", "vocabulary": "categories-v1"},
+					"tags": {"type": "array", "vocabulary": "categories-v1"},
+					"topic": {"type": "string", "vocabulary": "topics-v1"}
+				},
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+			}`,
+			expRules: []string{
+				schemaparser.RuleVocabularyType,
+				schemaparser.RuleUnresolvedVocabulary,
+			},
+			expPaths: []string{"/properties/tags/vocabulary", "/properties/topic/vocabulary"},
+		},
+		{
+			name: "Examples outside of a vocabulary",
+			file: "schemas/test_schema-v1.0.0.json",
+			schema: `{
+				"type": "object",
+				"properties": {
+					"linked_schemas": {"type": "array", "items": {"type": "string"}},
+					"categories": {
+						"type": "array",
+						"items": {"type": "string", "vocabulary": "categories-v1"},
+						"examples": [["cooperative"], ["charity"]]
+					}
+				},
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+			}`,
+			expRules: []string{schemaparser.RuleInvalidExample},
+			expPaths: []string{"/properties/categories/examples/1"},
+		},
+		{
+			name: "Invalid examples",
+			file: "schemas/test_schema-v1.0.0.json",
+			schema: `{
+				"type": "object",
+				"properties": {
+					"linked_schemas": {"$ref": "../fields/linked_schemas.json"},
+					"name": {"$ref": "../fields/name.json", "examples": ["Jane Doe-Smith"]}
+				},
+				"required": ["linked_schemas", "name"],
+				"examples": [{"linked_schemas": ["test_schema-v1.0.0"]}],
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+			}`,
+			fields: lintFields,
+			expRules: []string{
+				schemaparser.RuleInvalidExample,
+				schemaparser.RuleInvalidExample,
+			},
+			expPaths: []string{"/examples/0", "/properties/name/examples/0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := schemaparser.LintSchema(
+				tt.file,
+				[]byte(tt.schema),
+				tt.fields,
+				lintVocabularies,
+			)
+
+			var rules, paths []string
+			for _, issue := range issues {
+				require.Equal(t, tt.file, issue.File)
+				require.NotEmpty(t, issue.Message)
+				rules = append(rules, issue.Rule)
+				paths = append(paths, issue.Pointer)
+			}
+			require.Equal(t, tt.expRules, rules)
+			require.Equal(t, tt.expPaths, paths)
+		})
+	}
+}
+
+func TestLintField(t *testing.T) {
+	fields := map[string][]byte{
+		"address.json": []byte(`{
+			"type": "object",
+			"properties": {
+				"street": {"type": "string"},
+				"geolocation": {"$ref": "./geolocation.json"}
+			},
+			"required": ["street", "city"],
+			"examples": [{"street": 1}]
+		}`),
+		"contact.json": []byte(`{
+			"type": "object",
+			"properties": {"email": {"$ref": "./email.json"}}
+		}`),
+		"geolocation.json": lintFields["geolocation.json"],
+		"name.json":        lintFields["name.json"],
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		expRules []string
+		expPaths []string
+	}{
+		{
+			name: "Valid field",
+			file: "fields/name.json",
+		},
+		{
+			name:     "Unresolved ref",
+			file:     "fields/contact.json",
+			expRules: []string{schemaparser.RuleUnresolvedRef},
+			expPaths: []string{"/properties/email/$ref"},
+		},
+		{
+			name:     "Undefined required property",
+			file:     "fields/address.json",
+			expRules: []string{schemaparser.RuleUndefinedRequired},
+			expPaths: []string{"/required/1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := tt.file[len("fields/"):]
+			issues := schemaparser.LintField(tt.file, fields[fileName], fields, nil)
+
+			var rules, paths []string
+			for _, issue := range issues {
+				rules = append(rules, issue.Rule)
+				paths = append(paths, issue.Pointer)
+			}
+			require.Equal(t, tt.expRules, rules)
+			require.Equal(t, tt.expPaths, paths)
+		})
+	}
+}
+
+func TestLintFieldExamples(t *testing.T) {
+	fields := map[string][]byte{
+		"address.json": []byte(`{
+			"type": "object",
+			"properties": {
+				"street": {"type": "string"},
+				"geolocation": {"$ref": "./geolocation.json"}
+			},
+			"examples": [
+				{"street": "Main Street", "geolocation": {"lat": 1, "lon": 2}},
+				{"street": 1, "geolocation": {"lat": 1}}
+			]
+		}`),
+		"geolocation.json": lintFields["geolocation.json"],
+	}
+
+	issues := schemaparser.LintField(
+		"fields/address.json",
+		fields["address.json"],
+		fields,
+		nil,
+	)
+	require.Len(t, issues, 1)
+	require.Equal(t, schemaparser.RuleInvalidExample, issues[0].Rule)
+	require.Equal(t, "/examples/1", issues[0].Pointer)
+	require.Contains(t, issues[0].Message, "/street")
+	require.Contains(t, issues[0].Message, "/geolocation")
+}
+
+func TestLintVocabulary(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		vocabulary string
+		expRules   []string
+		expPaths   []string
+	}{
+		{
+			name:       "Valid vocabulary",
+			file:       "vocabularies/categories-v1.0.0.json",
+			vocabulary: string(lintVocabularies["categories-v1.0.0.json"]),
+		},
+		{
+			name:       "Invalid JSON",
+			file:       "vocabularies/categories-v1.0.0.json",
+			vocabulary: `{"name": "categories-v1.0.0", "terms": {}}`,
+			expRules:   []string{schemaparser.RuleInvalidJSON},
+			expPaths:   []string{""},
+		},
+		{
+			name:       "Name mismatch",
+			file:       "vocabularies/categories-v1.0.0.json",
+			vocabulary: `{"name": "topics-v1.0.0", "terms": []}`,
+			expRules:   []string{schemaparser.RuleNameMismatch},
+			expPaths:   []string{"/name"},
+		},
+		{
+			name: "Invalid terms",
+			file: "vocabularies/categories-v1.0.0.json",
+			vocabulary: `{
+				"name": "categories-v1.0.0",
+				"terms": [
+					{"id": "non-profit", "narrower": ["charity"]},
+					{"id": "non-profit"},
+					{"labels": {"en": "Commercial"}}
+				]
+			}`,
+			expRules: []string{
+				schemaparser.RuleDuplicateTerm,
+				schemaparser.RuleInvalidTerm,
+				schemaparser.RuleUnknownTerm,
+			},
+			expPaths: []string{"/terms/1/id", "/terms/2/id", "/terms/0/narrower/0"},
+		},
+		{
+			name: "Cycle",
+			file: "vocabularies/categories-v1.0.0.json",
+			vocabulary: `{
+				"name": "categories-v1.0.0",
+				"terms": [
+					{"id": "non-profit", "broader": ["cooperative"]},
+					{"id": "cooperative", "broader": ["non-profit"]},
+					{"id": "commercial", "broader": ["non-profit"]}
+				]
+			}`,
+			expRules: []string{
+				schemaparser.RuleTermCycle,
+				schemaparser.RuleTermCycle,
+			},
+			expPaths: []string{"/terms/0", "/terms/1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := schemaparser.LintVocabulary(tt.file, []byte(tt.vocabulary))
+
+			var rules, paths []string
+			for _, issue := range issues {
+				rules = append(rules, issue.Rule)
+				paths = append(paths, issue.Pointer)
+			}
+			require.Equal(t, tt.expRules, rules)
+			require.Equal(t, tt.expPaths, paths)
+		})
+	}
+}
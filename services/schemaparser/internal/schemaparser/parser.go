@@ -1,18 +1,15 @@
 package schemaparser
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"path"
+	"sort"
+	"strings"
 
 	"github.com/iancoleman/orderedmap"
 	"go.mongodb.org/mongo-driver/bson"
 
-	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
-	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
 )
 
@@ -37,46 +34,33 @@ type SchemaResult struct {
 	Schema *model.SchemaJSON
 	// Full schema data as BSON.
 	FullJSON bson.D
+	// Names of the fields the schema references, e.g. "name", sorted.
+	Fields []string
 }
 
 // SchemaParser represents the schema parser.
 type SchemaParser struct {
-	// Field map list.
-	FieldListMap map[string]string
+	// fields holds the fields of the schema or field being parsed, keyed by
+	// file name, so that nested references resolve too.
+	fields map[string][]byte
+	// usedFields collects the fields referenced by the schema being parsed.
+	usedFields map[string]bool
 }
 
 // NewSchemaParser creates a new instance of SchemaParser.
-func NewSchemaParser(fieldListMap map[string]string) *SchemaParser {
-	return &SchemaParser{
-		FieldListMap: fieldListMap,
-	}
+func NewSchemaParser() *SchemaParser {
+	return &SchemaParser{}
 }
 
-// GetSchema fetches, parses and converts a schema to BSON from a given URL.
-func (s *SchemaParser) GetSchema(url string) (*SchemaResult, error) {
-	schemaData, err := s.fetchSchema(url)
-	if err != nil {
-		return nil, err
-	}
-
-	parsedSchema, err := s.parseSchema(schemaData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse schema: %w", err)
-	}
-
-	fullJSON, err := s.convertToBson(schemaData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert schema to BSON: %w", err)
-	}
-
-	return &SchemaResult{Schema: parsedSchema, FullJSON: fullJSON}, nil
-}
-
-// GetLocalSchema fetches, parses and converts a schema to BSON from local schema byte.
+// GetLocalSchema parses and converts a schema to BSON, resolving the fields
+// it references among the given fields, keyed by file name.
 func (s *SchemaParser) GetLocalSchema(
 	schema []byte,
 	fields map[string][]byte,
 ) (*SchemaResult, error) {
+	s.usedFields = make(map[string]bool)
+	s.fields = fields
+
 	parsedSchema, err := s.parseSchema(schema)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse schema: %w", err)
@@ -87,52 +71,48 @@ func (s *SchemaParser) GetLocalSchema(
 		return nil, fmt.Errorf("failed to convert schema to BSON: %w", err)
 	}
 
-	return &SchemaResult{Schema: parsedSchema, FullJSON: fullJSON}, nil
+	return &SchemaResult{
+		Schema:   parsedSchema,
+		FullJSON: fullJSON,
+		Fields:   s.fieldNames(),
+	}, nil
 }
 
-// fetchSchema fetches the schema data from the provided URL.
-func (s *SchemaParser) fetchSchema(url string) ([]byte, error) {
-	// Perform a GET request with bearer token authentication.
-	resp, err := httputil.GetWithBearerToken(url, config.Values.Github.TOKEN)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch file from %s: %w", url, err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("get file failed, url: %s, status code: %d",
-			url, resp.StatusCode)
+// GetField converts a field definition given its file name, e.g.
+// "name.json", to BSON, resolving the fields it references. The fields are
+// read from the optional local fields.
+func (s *SchemaParser) GetField(
+	fileName string,
+	optionalFields ...map[string][]byte,
+) (bson.D, error) {
+	s.usedFields = nil
+	if len(optionalFields) > 0 {
+		s.fields = optionalFields[0]
 	}
 
-	// Read the entire body of the response.
-	body, err := io.ReadAll(resp.Body)
+	field, err := s.fetchReferencedSchema(fileName, optionalFields...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Unmarshal the body into a map.
-	var data map[string]interface{}
-	if err := json.Unmarshal(body, &data); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+		return nil, err
 	}
+	return s.parseProperties(*field, optionalFields...)
+}
 
-	// Decode the base64-encoded content.
-	decodedContent, err := base64.StdEncoding.DecodeString(
-		data["content"].(string),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode base64 content: %w", err)
-	}
+// FieldName returns the name of a field given its file name or reference,
+// e.g. "name" for "../fields/name.json".
+func FieldName(ref string) string {
+	_, fileName := path.Split(ref)
+	return strings.TrimSuffix(fileName, ".json")
+}
 
-	// Check if the decoded content is empty or not.
-	if len(decodedContent) == 0 {
-		return nil, fmt.Errorf(
-			"get file failed, url: %s, content is empty",
-			url,
-		)
+// fieldNames returns the names of the fields referenced by the schema being
+// parsed, sorted.
+func (s *SchemaParser) fieldNames() []string {
+	names := make([]string, 0, len(s.usedFields))
+	for name := range s.usedFields {
+		names = append(names, name)
 	}
-
-	return decodedContent, nil
+	sort.Strings(names)
+	return names
 }
 
 // parseSchema parses the schema data into the SchemaJSON type.
@@ -305,38 +285,25 @@ func (s *SchemaParser) fetchReferencedSchema(
 	optionalFields ...map[string][]byte,
 ) (*orderedmap.OrderedMap, error) {
 	_, fieldName := path.Split(url)
+	if s.usedFields != nil {
+		s.usedFields[FieldName(fieldName)] = true
+	}
 
-	var (
-		fieldJSON []byte
-		ok        bool
-		err       error
-	)
+	fields := s.fields
 	if len(optionalFields) > 0 && optionalFields[0] != nil {
-		fieldJSON, ok = optionalFields[0][fieldName]
-		if !ok {
-			return nil, fmt.Errorf(
-				"get schema failed, url: %s, fieldListURL is empty",
-				url,
-			)
-		}
-	} else {
-		fieldListURL := s.FieldListMap[fieldName]
-
-		if fieldListURL == "" {
-			return nil, fmt.Errorf(
-				"get schema failed, url: %s, fieldListURL is empty",
-				url,
-			)
-		}
-
-		fieldJSON, err = s.fetchSchema(fieldListURL)
-		if err != nil {
-			return nil, err
-		}
+		fields = optionalFields[0]
+	}
+	fieldJSON, ok := fields[fieldName]
+	if !ok {
+		return nil, fmt.Errorf(
+			"get schema failed, url: %s, field %s not found",
+			url,
+			fieldName,
+		)
 	}
 
 	subSchema := orderedmap.New()
-	err = json.Unmarshal(fieldJSON, &subSchema)
+	err := json.Unmarshal(fieldJSON, &subSchema)
 	if err != nil {
 		return nil, err
 	}
@@ -1,10 +1,7 @@
 package schemaparser_test
 
 import (
-	"encoding/base64"
 	"encoding/json"
-	"net/http"
-	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -189,42 +186,45 @@ var (
 	}
 )
 
-func TestGetSchema(t *testing.T) {
-	// Create a test server with handler.
-	ts := httptest.NewServer(http.HandlerFunc(
-		func(w http.ResponseWriter, r *http.Request) {
-			var (
-				resp        map[string]interface{}
-				schemaBytes []byte
-				err         error
-			)
-
-			if r.URL.Path == "/schemas/people_schema-v0.1.0.json" {
-				schemaBytes, err = json.Marshal(peopleSchema)
-			} else if r.URL.Path == "/fields/name.json" {
-				schemaBytes, err = json.Marshal(nameSchema)
-			}
-			require.NoError(t, err)
-
-			resp = map[string]interface{}{
-				"content": base64.StdEncoding.EncodeToString(schemaBytes),
-			}
-			w.Header().Set("Content-Type", "application/json")
-			require.NoError(t, json.NewEncoder(w).Encode(resp))
-		}))
-	defer ts.Close()
+func TestGetLocalSchema(t *testing.T) {
+	schemaBytes, err := json.Marshal(peopleSchema)
+	require.NoError(t, err)
+	nameBytes, err := json.Marshal(nameSchema)
+	require.NoError(t, err)
+	fields := map[string][]byte{"name.json": nameBytes}
 
-	fieldListMap := map[string]string{
-		"name.json": ts.URL + "/fields/name.json",
-	}
-	schemaParser := schemaparser.NewSchemaParser(fieldListMap)
-	result, err := schemaParser.GetSchema(
-		ts.URL + "/schemas/people_schema-v0.1.0.json",
-	)
+	schemaParser := schemaparser.NewSchemaParser()
+	result, err := schemaParser.GetLocalSchema(schemaBytes, fields)
 
 	require.NoError(t, err)
 	require.Equal(t, expectedSchema, result.Schema)
 	require.Equal(t, toMap(expectedFullJSON), toMap(result.FullJSON))
+	require.Equal(t, []string{"name"}, result.Fields)
+
+	_, err = schemaParser.GetLocalSchema(schemaBytes, map[string][]byte{})
+	require.Error(t, err)
+}
+
+func TestGetField(t *testing.T) {
+	nameBytes, err := json.Marshal(nameSchema)
+	require.NoError(t, err)
+	fields := map[string][]byte{"name.json": nameBytes}
+
+	schemaParser := schemaparser.NewSchemaParser()
+	field, err := schemaParser.GetField("name.json", fields)
+	require.NoError(t, err)
+
+	fieldMap := toMap(field)
+	require.Equal(t, "Name", fieldMap["title"])
+	require.Equal(t, "string", fieldMap["type"])
+
+	_, err = schemaParser.GetField("unknown.json", fields)
+	require.Error(t, err)
+}
+
+func TestFieldName(t *testing.T) {
+	require.Equal(t, "name", schemaparser.FieldName("../fields/name.json"))
+	require.Equal(t, "name", schemaparser.FieldName("name.json"))
 }
 
 func toMap(d bson.D) map[string]interface{} {
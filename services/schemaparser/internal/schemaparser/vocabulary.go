@@ -0,0 +1,250 @@
+package schemaparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaname"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
+)
+
+// VocabularyKey is the keyword of the schemas whose values are the terms of
+// a vocabulary, e.g. "vocabulary": "kvm_categories-v1". The vocabulary is
+// either a version or a major version range, which resolves to its latest
+// version.
+const VocabularyKey = "vocabulary"
+
+// VocabularyName returns the name of a vocabulary given its file name, e.g.
+// "kvm_categories-v1.0.0" for "kvm_categories-v1.0.0.json".
+func VocabularyName(fileName string) string {
+	return strings.TrimSuffix(path.Base(fileName), ".json")
+}
+
+// ParseVocabulary decodes a vocabulary and completes the relations of its
+// terms: a term is among the narrower terms of its broader terms and among
+// the broader terms of its narrower terms. The related terms are listed in
+// the order of the vocabulary, and relations to unknown terms are dropped.
+func ParseVocabulary(data []byte) (*model.Vocabulary, error) {
+	var vocabulary model.Vocabulary
+	if err := json.Unmarshal(data, &vocabulary); err != nil {
+		return nil, err
+	}
+	linkTerms(&vocabulary)
+	return &vocabulary, nil
+}
+
+// linkTerms completes the relations of the terms of the vocabulary.
+func linkTerms(vocabulary *model.Vocabulary) {
+	index := make(map[string]int, len(vocabulary.Terms))
+	for i, term := range vocabulary.Terms {
+		index[term.ID] = i
+	}
+	broader := make([][]string, len(vocabulary.Terms))
+	narrower := make([][]string, len(vocabulary.Terms))
+	relate := func(narrowerID, broaderID string) {
+		n, ok := index[narrowerID]
+		if !ok {
+			return
+		}
+		b, ok := index[broaderID]
+		if !ok {
+			return
+		}
+		broader[n] = appendUnique(broader[n], broaderID)
+		narrower[b] = appendUnique(narrower[b], narrowerID)
+	}
+	for _, term := range vocabulary.Terms {
+		for _, id := range term.Broader {
+			relate(term.ID, id)
+		}
+		for _, id := range term.Narrower {
+			relate(id, term.ID)
+		}
+	}
+	byIndex := func(ids []string) []string {
+		sort.SliceStable(ids, func(i, j int) bool {
+			return index[ids[i]] < index[ids[j]]
+		})
+		return ids
+	}
+	for i := range vocabulary.Terms {
+		vocabulary.Terms[i].Broader = byIndex(broader[i])
+		vocabulary.Terms[i].Narrower = byIndex(narrower[i])
+	}
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// Vocabularies holds the vocabularies the schemas of a library can
+// reference, keyed by the names they are referenced by. Their Name is the
+// name they are published under, prefixed with the namespace of the
+// library.
+type Vocabularies map[string]*model.Vocabulary
+
+// ParseVocabularies parses the vocabularies of a library, keyed by file
+// name. The name function returns the name a vocabulary is published under.
+func ParseVocabularies(
+	files map[string][]byte,
+	name func(string) string,
+) (Vocabularies, error) {
+	vocabularies := make(Vocabularies, len(files))
+	for fileName, data := range files {
+		vocabulary, err := ParseVocabulary(data)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to parse vocabulary %s: %w",
+				fileName,
+				err,
+			)
+		}
+		ref := VocabularyName(fileName)
+		vocabulary.Name = name(ref)
+		vocabularies[ref] = vocabulary
+	}
+	return vocabularies, nil
+}
+
+// Resolve returns the vocabulary a schema references, given its name or a
+// major version range.
+func (v Vocabularies) Resolve(ref string) (*model.Vocabulary, bool) {
+	if vocabulary, ok := v[ref]; ok {
+		return vocabulary, true
+	}
+	if !schemaname.IsRange(ref) {
+		return nil, false
+	}
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	latest, ok := schemaname.Latest(names, ref)
+	if !ok {
+		return nil, false
+	}
+	return v[latest.String()], true
+}
+
+// ApplyVocabularies restricts the values of the schemas referencing a
+// vocabulary, at any depth of the full schema, to the IDs of its terms: it
+// sets their enum to the IDs and their vocabulary keyword to the name the
+// vocabulary is published under. It returns the schema along with the names
+// of the vocabularies it references, sorted.
+func ApplyVocabularies(
+	fullJSON bson.D,
+	vocabularies Vocabularies,
+) (bson.D, []string, error) {
+	a := &vocabularyApplier{vocabularies: vocabularies, used: map[string]bool{}}
+	value, err := a.apply(fullJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(a.used) == 0 {
+		return fullJSON, nil, nil
+	}
+
+	names := make([]string, 0, len(a.used))
+	for name := range a.used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return value.(bson.D), names, nil
+}
+
+type vocabularyApplier struct {
+	vocabularies Vocabularies
+	used         map[string]bool
+}
+
+// apply returns a copy of the value with the vocabularies applied. The
+// values of the instance keywords, e.g. examples, are left as they are.
+func (a *vocabularyApplier) apply(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case bson.D:
+		doc := make(bson.D, 0, len(v)+1)
+		for _, elem := range v {
+			if !instanceKeys[elem.Key] {
+				applied, err := a.apply(elem.Value)
+				if err != nil {
+					return nil, err
+				}
+				elem.Value = applied
+			}
+			doc = append(doc, elem)
+		}
+		return a.restrict(doc)
+	case orderedmap.OrderedMap:
+		doc := make(bson.D, 0, len(v.Keys()))
+		for _, key := range v.Keys() {
+			value, _ := v.Get(key)
+			doc = append(doc, bson.E{Key: key, Value: value})
+		}
+		return a.apply(doc)
+	case *orderedmap.OrderedMap:
+		return a.apply(*v)
+	case bson.A:
+		return a.apply([]interface{}(v))
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			applied, err := a.apply(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = applied
+		}
+		return items, nil
+	}
+	return value, nil
+}
+
+// restrict sets the enum of the schema if it references a vocabulary.
+func (a *vocabularyApplier) restrict(schema bson.D) (bson.D, error) {
+	vocabularyIndex, enumIndex := -1, -1
+	for i, elem := range schema {
+		switch elem.Key {
+		case VocabularyKey:
+			vocabularyIndex = i
+		case "enum":
+			enumIndex = i
+		}
+	}
+	if vocabularyIndex < 0 {
+		return schema, nil
+	}
+	ref, ok := schema[vocabularyIndex].Value.(string)
+	if !ok {
+		// A property named vocabulary rather than the keyword.
+		return schema, nil
+	}
+
+	vocabulary, ok := a.vocabularies.Resolve(ref)
+	if !ok {
+		return nil, fmt.Errorf("vocabulary %s not found", ref)
+	}
+	a.used[vocabulary.Name] = true
+
+	ids := make(bson.A, len(vocabulary.Terms))
+	for i, term := range vocabulary.Terms {
+		ids[i] = term.ID
+	}
+	schema[vocabularyIndex].Value = vocabulary.Name
+	if enumIndex >= 0 {
+		schema[enumIndex].Value = ids
+	} else {
+		schema = append(schema, bson.E{Key: "enum", Value: ids})
+	}
+	return schema, nil
+}
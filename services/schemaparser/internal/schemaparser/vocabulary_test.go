@@ -0,0 +1,103 @@
+package schemaparser_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/schemaparser"
+)
+
+func TestParseVocabulary(t *testing.T) {
+	vocabulary, err := schemaparser.ParseVocabulary([]byte(`{
+		"name": "categories-v1.0.0",
+		"title": "Categories",
+		"terms": [
+			{"id": "non-profit", "labels": {"en": "Non-profit", "de": "Gemeinnützig"}, "narrower": ["charity"]},
+			{"id": "cooperative", "broader": ["non-profit", "commercial"]},
+			{"id": "charity", "broader": ["non-profit", "unknown"]},
+			{"id": "commercial"}
+		]
+	}`))
+	require.NoError(t, err)
+
+	require.Equal(t, &model.Vocabulary{
+		Name:  "categories-v1.0.0",
+		Title: "Categories",
+		Terms: []model.Term{
+			{
+				ID:       "non-profit",
+				Labels:   map[string]string{"en": "Non-profit", "de": "Gemeinnützig"},
+				Narrower: []string{"cooperative", "charity"},
+			},
+			{ID: "cooperative", Broader: []string{"non-profit", "commercial"}},
+			{ID: "charity", Broader: []string{"non-profit"}},
+			{ID: "commercial", Narrower: []string{"cooperative"}},
+		},
+	}, vocabulary)
+
+	_, err = schemaparser.ParseVocabulary([]byte(`{"terms": {}}`))
+	require.Error(t, err)
+}
+
+func TestApplyVocabularies(t *testing.T) {
+	vocabularies, err := schemaparser.ParseVocabularies(
+		map[string][]byte{
+			"categories-v1.0.0.json": []byte(`{"terms": [{"id": "old"}]}`),
+			"categories-v1.1.0.json": []byte(`{"terms": [{"id": "a"}, {"id": "b"}]}`),
+		},
+		func(name string) string { return "fork:" + name },
+	)
+	require.NoError(t, err)
+
+	schema := bson.D{
+		{Key: "type", Value: "object"},
+		{Key: "properties", Value: bson.D{
+			{Key: "category", Value: bson.D{
+				{Key: "type", Value: "string"},
+				{Key: "vocabulary", Value: "categories-v1"},
+			}},
+			{Key: "tags", Value: bson.D{
+				{Key: "type", Value: "array"},
+				{Key: "items", Value: bson.D{
+					{Key: "vocabulary", Value: "categories-v1.0.0"},
+					{Key: "enum", Value: bson.A{"x"}},
+				}},
+				{Key: "examples", Value: bson.A{bson.D{{Key: "vocabulary", Value: "x"}}}},
+			}},
+		}},
+	}
+
+	applied, names, err := schemaparser.ApplyVocabularies(schema, vocabularies)
+	require.NoError(t, err)
+	require.Equal(t, []string{"fork:categories-v1.0.0", "fork:categories-v1.1.0"}, names)
+
+	properties := toMap(applied)["properties"].(map[string]interface{})
+	require.Equal(t, map[string]interface{}{
+		"type":       "string",
+		"vocabulary": "fork:categories-v1.1.0",
+		"enum":       bson.A{"a", "b"},
+	}, properties["category"])
+	tags := properties["tags"].(map[string]interface{})
+	require.Equal(t, map[string]interface{}{
+		"vocabulary": "fork:categories-v1.0.0",
+		"enum":       bson.A{"old"},
+	}, tags["items"])
+	require.Equal(t, bson.A{bson.D{{Key: "vocabulary", Value: "x"}}}, tags["examples"])
+
+	// The schema is left as it is.
+	require.Equal(t, "categories-v1", schema[1].Value.(bson.D)[0].Value.(bson.D)[1].Value)
+
+	unchanged, names, err := schemaparser.ApplyVocabularies(bson.D{{Key: "type", Value: "string"}}, vocabularies)
+	require.NoError(t, err)
+	require.Empty(t, names)
+	require.Equal(t, bson.D{{Key: "type", Value: "string"}}, unchanged)
+
+	_, _, err = schemaparser.ApplyVocabularies(
+		bson.D{{Key: "vocabulary", Value: "topics-v1"}},
+		vocabularies,
+	)
+	require.Error(t, err)
+}
@@ -1,366 +1,741 @@
 package service
 
 import (
-	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"time"
+	"path"
+	"sort"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"golang.org/x/sync/errgroup"
 
-	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/dateutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
-	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemadiff"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemaname"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/repository/mongo"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/schemaparser"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/source"
 )
 
 const (
-	LastCommitKey = "schemas:lastCommit"
-	maxGoroutines = 10
+	// LastVersionKey stores the version of the sources the schemas were last
+	// updated from.
+	LastVersionKey = "schemas:lastVersion"
+	// PreviousVersionKey stores the version of the sources the schemas were
+	// updated from before the last version, which they can be rolled back to.
+	PreviousVersionKey = "schemas:previousVersion"
+	// UpdateErrorKey stores the error that stopped the last update, which
+	// has to be resolved manually, as a JSON model.UpdateError.
+	UpdateErrorKey = "schemas:update:error"
 )
 
 type SchemaService interface {
-	GetBranchInfo(url string) (*model.BranchInfo, error)
-	HasNewCommit(lastCommit string) (bool, error)
-	UpdateSchemas(branchSha string) error
-	SetLastCommit(lastCommit string) error
-	UpdateLocalSchemas(
-		schemas map[string][]byte,
-		fields map[string][]byte,
-	) error
-	GetUpdateError() (string, error)
+	HasNewVersion(version string) (bool, error)
+	UpdateSchemas(libraries []*source.Library, version string) ([]string, error)
+	GetLastVersion() (string, error)
+	GetPreviousVersion() (string, error)
+	SetLastVersion(version string) error
+	RestoreLastVersion(version string) error
+	GetUpdateError() (*model.UpdateError, error)
+	SetUpdateError(updateError *model.UpdateError) error
+	ClearUpdateError() error
+	PublishSchemasVersion(version string) error
+	PublishSchemasUpdated(schemas []string, version string) error
 }
 
 type schemaService struct {
-	mongoRepo mongo.SchemaRepository
-	redis     redis.Redis
+	mongoRepo      mongo.SchemaRepository
+	fieldRepo      mongo.FieldRepository
+	vocabularyRepo mongo.VocabularyRepository
+	// revisionRepo keeps every revision of the schemas.
+	revisionRepo mongo.SchemaRevisionRepository
+	redis        redis.Redis
+	// validationRedis is the Redis of the validation service, which drops
+	// its cached schemas when the schemas version changes.
+	validationRedis redis.Redis
 }
 
 func NewSchemaService(
 	mongoRepo mongo.SchemaRepository,
+	fieldRepo mongo.FieldRepository,
+	vocabularyRepo mongo.VocabularyRepository,
+	revisionRepo mongo.SchemaRevisionRepository,
 	redis redis.Redis,
+	validationRedis redis.Redis,
 ) SchemaService {
 	return &schemaService{
-		mongoRepo: mongoRepo,
-		redis:     redis,
+		mongoRepo:       mongoRepo,
+		fieldRepo:       fieldRepo,
+		vocabularyRepo:  vocabularyRepo,
+		revisionRepo:    revisionRepo,
+		redis:           redis,
+		validationRedis: validationRedis,
 	}
 }
 
-// GetBranchInfo retrieves information about a specific GitHub branch given its URL.
-func (s *schemaService) GetBranchInfo(url string) (*model.BranchInfo, error) {
-	// GetByteWithBearerToken sends a GET request to the provided URL, using
-	// the provided bearer token for authentication.
-	bytes, err := httputil.GetByteWithBearerToken(
-		url,
-		config.Values.Github.TOKEN,
-	)
+// HasNewVersion checks if the sources changed since the schemas were last
+// updated.
+func (s *schemaService) HasNewVersion(version string) (bool, error) {
+	val, err := s.redis.Get(LastVersionKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get data from %s: %w", url, err)
+		return false, fmt.Errorf(
+			"failed to retrieve last version from Redis: %w",
+			err,
+		)
 	}
 
-	var data model.BranchInfo
+	return val != version, nil
+}
 
-	err = json.Unmarshal(bytes, &data)
+// GetLastVersion returns the version of the sources the schemas were last
+// updated from, or an empty string if they were never updated.
+func (s *schemaService) GetLastVersion() (string, error) {
+	val, err := s.redis.Get(LastVersionKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+		return "", fmt.Errorf(
+			"failed to retrieve last version from Redis: %w",
+			err,
+		)
 	}
-
-	return &data, nil
+	return val, nil
 }
 
-// HasNewCommit checks if there's a new commit in the schema repository.
-func (s *schemaService) HasNewCommit(lastCommit string) (bool, error) {
-	val, err := s.redis.Get(LastCommitKey)
+// GetPreviousVersion returns the version of the sources the schemas were
+// updated from before the last version, or an empty string if there is none.
+func (s *schemaService) GetPreviousVersion() (string, error) {
+	val, err := s.redis.Get(PreviousVersionKey)
 	if err != nil {
-		return false, fmt.Errorf(
-			"failed to retrieve last commit from Redis: %w",
+		return "", fmt.Errorf(
+			"failed to retrieve previous version from Redis: %w",
 			err,
 		)
 	}
-
-	return val != lastCommit, nil
+	return val, nil
 }
 
-func (s *schemaService) UpdateSchemas(branchSha string) error {
-	// Get schema folder list and field folder list.
-	schemaListURL, fieldListURL, err := getSchemaAndFieldFolderURLs(branchSha)
+// SetLastVersion records the version of the sources the schemas were
+// updated from, and keeps the version they replace as the previous version.
+func (s *schemaService) SetLastVersion(version string) error {
+	last, err := s.GetLastVersion()
 	if err != nil {
-		setErr := s.redis.Set("schemas:update:error", fmt.Sprintf("Error getting schema and field URLs: %v", err), 0)
-		if setErr != nil {
-			fmt.Printf("Failed to set Redis error key: %v\n", setErr)
-		}
 		return err
 	}
+	if last != "" && last != version {
+		err := s.redis.Set(PreviousVersionKey, last, 0)
+		if err != nil {
+			return fmt.Errorf("failed to set previous version in Redis: %w", err)
+		}
+	}
 
-	// Read field folder list and create a map of field name and field URL.
-	fieldListMap, err := getFieldsURLMap(fieldListURL)
+	err = s.redis.Set(LastVersionKey, version, 0)
 	if err != nil {
-		setErr := s.redis.Set("schemas:update:error", fmt.Sprintf("Error getting fields URL map: %v", err), 0)
-		if setErr != nil {
-			fmt.Printf("Failed to set Redis error key: %v\n", setErr)
-		}
-		return err
+		return fmt.Errorf("failed to set last version in Redis: %w", err)
 	}
+	return nil
+}
 
-	// Read schema folder list.
-	schemaList, err := getGithubTree(schemaListURL)
+// RestoreLastVersion records the version of the sources the schemas were
+// rolled back to. Unlike SetLastVersion, the previous version is left as it
+// is, so the version rolled back from isn't kept as one to return to.
+func (s *schemaService) RestoreLastVersion(version string) error {
+	err := s.redis.Set(LastVersionKey, version, 0)
 	if err != nil {
-		setErr := s.redis.Set("schemas:update:error", fmt.Sprintf("Error getting GitHub tree: %v", err), 0)
-		if setErr != nil {
-			fmt.Printf("Failed to set Redis error key: %v\n", setErr)
+		return fmt.Errorf("failed to set last version in Redis: %w", err)
+	}
+	return nil
+}
+
+// UpdateSchemas updates the schemas, fields and vocabularies from the
+// libraries read from the sources at the version and returns the names of
+// the schemas that were added or changed. The schemas, fields and
+// vocabularies of a library are named under its namespace, and the
+// references of its schemas resolve to its own fields and vocabularies,
+// whose terms become the enums of the properties referencing them. Nothing
+// is updated if linting finds issues in any of them.
+func (s *schemaService) UpdateSchemas(
+	libraries []*source.Library,
+	version string,
+) ([]string, error) {
+	if issues := lint(libraries); len(issues) > 0 {
+		err := fmt.Errorf("linting found %d issue(s) in the schemas", len(issues))
+		s.setUpdateError(&model.UpdateError{
+			Message: err.Error(),
+			Schema:  issues[0].File,
+			Version: version,
+			Issues:  issues,
+		})
+		return nil, err
+	}
+
+	published, err := s.publishedRevisions()
+	if err != nil {
+		s.setUpdateError(&model.UpdateError{
+			Message: fmt.Sprintf("Error finding the published schemas: %v", err),
+			Version: version,
+		})
+		return nil, err
+	}
+
+	parser := schemaparser.NewSchemaParser()
+	loadedAt := dateutil.GetNowUnix()
+
+	vocabularies := make([]schemaparser.Vocabularies, len(libraries))
+	for i, library := range libraries {
+		var err error
+		vocabularies[i], err = schemaparser.ParseVocabularies(
+			library.Vocabularies,
+			library.Name,
+		)
+		if err != nil {
+			s.setUpdateError(&model.UpdateError{
+				Message: fmt.Sprintf("Error parsing vocabularies: %v", err),
+				Version: version,
+			})
+			return nil, err
 		}
-		return err
 	}
 
-	// Create a semaphore channel to limit goroutines.
-	semaphore := make(chan struct{}, maxGoroutines)
-
-	g, ctx := errgroup.WithContext(context.Background())
-
-	for _, schemaName := range schemaList {
-		schemaNameMap := schemaName.(map[string]interface{})
-		url := schemaNameMap["url"].(string)
-
-		g.Go(func() error {
-			select {
-			case <-ctx.Done():
-				return nil
-			default:
-				semaphore <- struct{}{}
-				defer func() { <-semaphore }()
-
-				parser := schemaparser.NewSchemaParser(fieldListMap)
-				result, err := parser.GetSchema(url)
-				if err != nil {
-					setErr := s.redis.Set("schemas:update:error", fmt.Sprintf("Error parsing schema from URL %s: %v", url, err), 0)
-					if setErr != nil {
-						fmt.Printf("Failed to set Redis error key: %v\n", setErr)
-					}
-					return err
-				}
-				return s.updateSchema(result.Schema, result.FullJSON)
+	// usedBy maps the field names to the schemas using them.
+	usedBy := make(map[string][]string)
+	// vocabularyUsedBy maps the vocabulary names to the schemas using them.
+	vocabularyUsedBy := make(map[string][]string)
+	// sources maps the schema names to the namespace of their library, to
+	// detect schemas defined by more than one source.
+	sources := make(map[string]string)
+	var changed []string
+
+	for i, library := range libraries {
+		for _, fileName := range sortedKeys(library.Schemas) {
+			result, err := parser.GetLocalSchema(
+				library.Schemas[fileName],
+				library.Fields,
+			)
+			if err != nil {
+				s.setUpdateError(&model.UpdateError{
+					Message: fmt.Sprintf("Error parsing schema: %v", err),
+					Schema:  library.Name(fileName),
+					Version: version,
+				})
+				return nil, err
 			}
+
+			name := library.Name(result.Schema.Metadata.Schema.Name)
+			if namespace, ok := sources[name]; ok {
+				err := fmt.Errorf(
+					"schema %s is defined by more than one source (namespaces %q and %q)",
+					name,
+					namespace,
+					library.Namespace,
+				)
+				s.setUpdateError(&model.UpdateError{
+					Message: err.Error(),
+					Schema:  name,
+					Version: version,
+				})
+				return nil, err
+			}
+			sources[name] = library.Namespace
+			result.Schema.Metadata.Schema.Name = name
+
+			fullJSON, vocabularyNames, err := schemaparser.ApplyVocabularies(
+				result.FullJSON,
+				vocabularies[i],
+			)
+			if err != nil {
+				s.setUpdateError(&model.UpdateError{
+					Message: fmt.Sprintf("Error applying vocabularies: %v", err),
+					Schema:  name,
+					Version: version,
+				})
+				return nil, err
+			}
+			result.FullJSON = fullJSON
+			for _, vocabularyName := range vocabularyNames {
+				vocabularyUsedBy[vocabularyName] = append(
+					vocabularyUsedBy[vocabularyName],
+					name,
+				)
+			}
+
+			revision, schemaChanged, err := s.updateSchema(
+				result.Schema,
+				result.FullJSON,
+				model.SchemaLoad{
+					Commit:   library.Version,
+					Version:  version,
+					LoadedAt: loadedAt,
+				},
+			)
+			if err != nil {
+				s.setUpdateError(&model.UpdateError{
+					Message: fmt.Sprintf("Error updating schema: %v", err),
+					Schema:  name,
+					Version: version,
+				})
+				return nil, err
+			}
+			addUsedBy(usedBy, library, result)
+			// An update that failed after storing the schema doesn't
+			// record its version, so the schemas it changed are compared
+			// with the published revisions again when it is retried.
+			if published != nil {
+				schemaChanged = published[name] != revision
+			}
+			if schemaChanged {
+				changed = append(changed, name)
+			}
+		}
+	}
+
+	var fieldNames []string
+	for i, library := range libraries {
+		names, err := s.updateFields(parser, library, vocabularies[i], usedBy)
+		if err != nil {
+			s.setUpdateError(&model.UpdateError{
+				Message: fmt.Sprintf("Error updating fields: %v", err),
+				Version: version,
+			})
+			return nil, err
+		}
+		fieldNames = append(fieldNames, names...)
+	}
+	if err := s.fieldRepo.DeleteOthers(fieldNames); err != nil {
+		err = fmt.Errorf("failed to delete removed fields: %w", err)
+		s.setUpdateError(&model.UpdateError{
+			Message: fmt.Sprintf("Error updating fields: %v", err),
+			Version: version,
 		})
+		return nil, err
 	}
 
-	err = g.Wait()
-	if err != nil {
-		setErr := s.redis.Set("schemas:update:error", fmt.Sprintf("Error waiting for goroutines: %v", err), 0)
-		if setErr != nil {
-			fmt.Printf("Failed to set Redis error key: %v\n", setErr)
+	var vocabularyNames []string
+	for i := range libraries {
+		names, err := s.updateVocabularies(vocabularies[i], vocabularyUsedBy)
+		if err != nil {
+			s.setUpdateError(&model.UpdateError{
+				Message: fmt.Sprintf("Error updating vocabularies: %v", err),
+				Version: version,
+			})
+			return nil, err
 		}
+		vocabularyNames = append(vocabularyNames, names...)
+	}
+	if err := s.vocabularyRepo.DeleteOthers(vocabularyNames); err != nil {
+		err = fmt.Errorf("failed to delete removed vocabularies: %w", err)
+		s.setUpdateError(&model.UpdateError{
+			Message: fmt.Sprintf("Error updating vocabularies: %v", err),
+			Version: version,
+		})
+		return nil, err
 	}
-	return err
-}
 
-// SetLastCommit updates the last commit time in the Redis store.
-func (s *schemaService) SetLastCommit(newLastCommitTime string) error {
-	oldLastCommitTime, err := s.redis.Get("schemas:lastCommit")
-	if err != nil {
-		return fmt.Errorf(
-			"failed to retrieve old last commit time from Redis: %w",
-			err,
-		)
+	if err := s.updateCompatibility(); err != nil {
+		s.setUpdateError(&model.UpdateError{
+			Message: fmt.Sprintf("Error updating compatibility reports: %v", err),
+			Version: version,
+		})
+		return nil, err
 	}
 
-	ok, err := shouldSetLastCommitTime(oldLastCommitTime, newLastCommitTime)
+	commits := make([]string, len(libraries))
+	for i, library := range libraries {
+		commits[i] = library.Version
+	}
+	err = s.revisionRepo.RecordLoad(&model.SourcesLoad{
+		Version:  version,
+		Commits:  commits,
+		LoadedAt: loadedAt,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to compare commit times: %w", err)
+		err = fmt.Errorf("failed to record the load: %w", err)
+		s.setUpdateError(&model.UpdateError{
+			Message: fmt.Sprintf("Error updating schemas: %v", err),
+			Version: version,
+		})
+		return nil, err
 	}
 
-	if !ok {
-		return nil
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// setUpdateError records the error that stopped the update, so that the next
+// updates wait for it to be resolved.
+func (s *schemaService) setUpdateError(updateError *model.UpdateError) {
+	if err := s.SetUpdateError(updateError); err != nil {
+		fmt.Printf("Failed to set Redis error key: %v\n", err)
 	}
+}
 
-	err = s.redis.Set("schemas:lastCommit", newLastCommitTime, 0)
+// SetUpdateError records an error that stops the next updates until it is
+// cleared.
+func (s *schemaService) SetUpdateError(updateError *model.UpdateError) error {
+	updateError.CreatedAt = dateutil.GetNowUnix()
+	data, err := json.Marshal(updateError)
 	if err != nil {
-		return fmt.Errorf(
-			"failed to set new last commit time in Redis: %w",
-			err,
-		)
+		return fmt.Errorf("failed to encode the update error: %w", err)
 	}
+	if err := s.redis.Set(UpdateErrorKey, string(data), 0); err != nil {
+		return fmt.Errorf("failed to set update error in Redis: %w", err)
+	}
+	return nil
+}
 
+// ClearUpdateError removes the error that stopped the updates, so that they
+// resume.
+func (s *schemaService) ClearUpdateError() error {
+	if err := s.redis.Del(UpdateErrorKey); err != nil {
+		return fmt.Errorf("failed to clear update error in Redis: %w", err)
+	}
 	return nil
 }
 
-func (s *schemaService) UpdateLocalSchemas(
-	schemas map[string][]byte,
-	fields map[string][]byte,
-) error {
-	parser := schemaparser.NewSchemaParser(nil)
-	for _, schema := range schemas {
-		result, err := parser.GetLocalSchema(schema, fields)
-		if err != nil {
-			setErr := s.redis.Set("schemas:update:error", fmt.Sprintf("Error waiting for goroutines: %v", err), 0)
-			if setErr != nil {
-				fmt.Printf("Failed to set Redis error key: %v\n", setErr)
-			}
-			return err
+// lint checks the schemas, fields and vocabularies of the libraries and
+// returns the issues found, with the files named under the namespaces of
+// their libraries.
+func lint(libraries []*source.Library) []model.LintIssue {
+	var issues []model.LintIssue
+	for _, library := range libraries {
+		for _, fileName := range sortedKeys(library.Schemas) {
+			issues = append(issues, schemaparser.LintSchema(
+				library.Name(path.Join(source.SchemasDir, fileName)),
+				library.Schemas[fileName],
+				library.Fields,
+				library.Vocabularies,
+			)...)
 		}
-		err = s.updateSchema(result.Schema, result.FullJSON)
-		if err != nil {
-			setErr := s.redis.Set("schemas:update:error", fmt.Sprintf("Error waiting for goroutines: %v", err), 0)
-			if setErr != nil {
-				fmt.Printf("Failed to set Redis error key: %v\n", setErr)
-			}
-			return err
+		for _, fileName := range sortedKeys(library.Fields) {
+			issues = append(issues, schemaparser.LintField(
+				library.Name(path.Join(source.FieldsDir, fileName)),
+				library.Fields[fileName],
+				library.Fields,
+				library.Vocabularies,
+			)...)
+		}
+		for _, fileName := range sortedKeys(library.Vocabularies) {
+			issues = append(issues, schemaparser.LintVocabulary(
+				library.Name(path.Join(source.VocabulariesDir, fileName)),
+				library.Vocabularies[fileName],
+			)...)
 		}
 	}
+	return issues
+}
 
+// PublishSchemasVersion tells the services caching schemas that the schemas
+// changed, so that they fetch them again.
+func (s *schemaService) PublishSchemasVersion(version string) error {
+	err := s.validationRedis.Set(redis.SchemasVersionKey, version, 0)
+	if err != nil {
+		return fmt.Errorf("failed to set schemas version in Redis: %w", err)
+	}
 	return nil
 }
 
-func shouldSetLastCommitTime(oldTime, newTime string) (bool, error) {
-	if oldTime == "" {
-		return true, nil
+// PublishSchemasUpdated tells the index which schemas were added or changed,
+// so that it revalidates the nodes linked to them.
+func (s *schemaService) PublishSchemasUpdated(
+	schemas []string,
+	version string,
+) error {
+	if len(schemas) == 0 {
+		return nil
 	}
-	if newTime == "" {
-		return false, nil
+	err := messaging.PublishSync(
+		messaging.SchemasUpdated,
+		messaging.SchemasUpdatedData{Schemas: schemas, Version: version},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish the updated schemas: %w", err)
 	}
+	return nil
+}
 
-	t1, err := time.Parse(time.RFC3339, oldTime)
+// publishedRevisions maps the names of the schemas loaded at the last
+// recorded version of the sources to their revisions. It returns nil if no
+// version was recorded, or its revisions weren't.
+func (s *schemaService) publishedRevisions() (map[string]string, error) {
+	lastVersion, err := s.GetLastVersion()
+	if err != nil || lastVersion == "" {
+		return nil, err
+	}
+	load, err := s.revisionRepo.FindLoad(lastVersion)
 	if err != nil {
-		return false, err
+		return nil, fmt.Errorf("failed to find the load of %s: %w", lastVersion, err)
 	}
-	t2, err := time.Parse(time.RFC3339, newTime)
+	revisions, err := s.revisionRepo.FindAll()
 	if err != nil {
-		return false, err
+		return nil, fmt.Errorf("failed to find the revisions: %w", err)
 	}
-
-	// To make sure DNS updates the content of schemas.
-	// The system won't update the last commit time until it passes a certain period.
-	if int(t2.Sub(t1).Minutes()) < 10 {
-		return false, nil
+	byName := revisionsAt(revisions, lastVersion, load)
+	if len(byName) == 0 {
+		return nil, nil
 	}
+	return byName, nil
+}
 
-	return true, nil
+// revisionsAt maps the names of the schemas to the revisions they had when
+// the sources were loaded at the version: the revisions they were last
+// changed to at or before the load. Versions loaded before the loads were
+// logged, for which load is nil, are found in the loads of the revisions.
+func revisionsAt(
+	revisions []*model.SchemaRevision,
+	version string,
+	load *model.SourcesLoad,
+) map[string]string {
+	byName := make(map[string]string)
+	changedAt := make(map[string]int64)
+	for _, revision := range revisions {
+		for _, l := range revision.Loads {
+			if load == nil && l.Version != version ||
+				load != nil && l.LoadedAt > load.LoadedAt {
+				continue
+			}
+			if at, ok := changedAt[revision.Name]; !ok || l.LoadedAt > at {
+				byName[revision.Name] = revision.Revision
+				changedAt[revision.Name] = l.LoadedAt
+			}
+		}
+	}
+	return byName
 }
 
+// updateSchema stores the schema, records its revision, with the load if the
+// schema changed, and returns the revision. It reports whether the schema was added or changed
+// since it was last stored.
 func (s *schemaService) updateSchema(
 	schema *model.SchemaJSON,
 	fullJSON bson.D,
-) error {
+	load model.SchemaLoad,
+) (string, bool, error) {
+	revision, err := schemaRevision(fullJSON)
+	if err != nil {
+		return "", false, err
+	}
+
 	doc := &model.Schema{
 		Title:       schema.Title,
 		Description: schema.Description,
 		Name:        schema.Metadata.Schema.Name,
 		URL:         schema.Metadata.Schema.URL,
 		FullSchema:  fullJSON,
+		Revision:    revision,
 	}
-	err := s.mongoRepo.Update(doc)
+	changed, err := s.mongoRepo.Update(doc)
 	if err != nil {
-		return err
+		return "", false, err
 	}
-	return nil
-}
 
-// getBranchFolders retrieves URLs for 'schemas' and 'fields' directories
-// given the branch's SHA.
-func getSchemaAndFieldFolderURLs(branchSha string) (string, string, error) {
-	// Construct the URL to the GitHub tree API endpoint for the branch.
-	rootURL := config.Values.Github.TreeURL + "/" + branchSha
+	err = s.revisionRepo.Record(&model.SchemaRevision{
+		Name:        doc.Name,
+		Revision:    revision,
+		Title:       doc.Title,
+		Description: doc.Description,
+		URL:         doc.URL,
+		FullSchema:  fullJSON,
+	}, load, changed)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to record the revision: %w", err)
+	}
+	return revision, changed, nil
+}
 
-	// Fetch the tree (list of files and directories) from the GitHub API.
-	rootList, err := getGithubTree(rootURL)
+// schemaRevision identifies the content of a full schema by hashing it.
+func schemaRevision(fullJSON bson.D) (string, error) {
+	data, err := bson.Marshal(fullJSON)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to fetch tree from %s: %w",
-			rootURL, err)
+		return "", fmt.Errorf("failed to encode the full schema: %w", err)
 	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
 
-	var (
-		schemaListURL string
-		fieldListURL  string
-	)
+// addUsedBy records that the parsed schema of the library uses its fields.
+func addUsedBy(
+	usedBy map[string][]string,
+	library *source.Library,
+	result *schemaparser.SchemaResult,
+) {
+	for _, field := range result.Fields {
+		name := library.Name(field)
+		usedBy[name] = append(usedBy[name], result.Schema.Metadata.Schema.Name)
+	}
+}
 
-	// Iterate over each item in the root directory.
-	for _, item := range rootList {
-		itemMap := item.(map[string]interface{})
-		// If the item is the 'schemas' directory, save its URL.
-		if itemMap["path"].(string) == "schemas" {
-			schemaListURL = itemMap["url"].(string)
+// updateFields stores the field definitions of the library, with its
+// vocabularies applied, along with the schemas using them, and returns their
+// names.
+func (s *schemaService) updateFields(
+	parser *schemaparser.SchemaParser,
+	library *source.Library,
+	vocabularies schemaparser.Vocabularies,
+	usedBy map[string][]string,
+) ([]string, error) {
+	names := make([]string, 0, len(library.Fields))
+	for _, fileName := range sortedKeys(library.Fields) {
+		fullField, err := parser.GetField(fileName, library.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse field %s: %w", fileName, err)
 		}
-		// If the item is the 'fields' directory, save its URL.
-		if itemMap["path"].(string) == "fields" {
-			fieldListURL = itemMap["url"].(string)
+		fullField, _, err = schemaparser.ApplyVocabularies(fullField, vocabularies)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse field %s: %w", fileName, err)
 		}
-	}
 
-	if schemaListURL == "" {
-		return "", "", fmt.Errorf("'schemas' directory not found")
-	}
-	if fieldListURL == "" {
-		return "", "", fmt.Errorf("'fields' directory not found")
+		name := library.Name(schemaparser.FieldName(fileName))
+		schemas := append([]string{}, usedBy[name]...)
+		sort.Strings(schemas)
+
+		doc := &model.Field{
+			Name:        name,
+			Title:       lookupString(fullField, "title"),
+			Description: lookupString(fullField, "description"),
+			Type:        lookupString(fullField, "type"),
+			FullField:   fullField,
+			UsedBy:      schemas,
+		}
+		if err := s.fieldRepo.Update(doc); err != nil {
+			return nil, fmt.Errorf("failed to update field %s: %w", name, err)
+		}
+		names = append(names, name)
 	}
+	return names, nil
+}
 
-	return schemaListURL, fieldListURL, nil
+// updateVocabularies stores the vocabularies of a library along with the
+// schemas using them, and returns their names.
+func (s *schemaService) updateVocabularies(
+	vocabularies schemaparser.Vocabularies,
+	usedBy map[string][]string,
+) ([]string, error) {
+	names := make([]string, 0, len(vocabularies))
+	for _, ref := range sortedVocabularyKeys(vocabularies) {
+		vocabulary := vocabularies[ref]
+		schemas := append([]string{}, usedBy[vocabulary.Name]...)
+		sort.Strings(schemas)
+		vocabulary.UsedBy = schemas
+
+		if err := s.vocabularyRepo.Update(vocabulary); err != nil {
+			return nil, fmt.Errorf(
+				"failed to update vocabulary %s: %w",
+				vocabulary.Name,
+				err,
+			)
+		}
+		names = append(names, vocabulary.Name)
+	}
+	return names, nil
 }
 
-// getFieldsURLMap fetches the GitHub tree (list of files and directories)
-// from the given URL, and then creates a map that maps field path to field URL.
-func getFieldsURLMap(fieldListURL string) (map[string]string, error) {
-	fieldList, err := getGithubTree(fieldListURL)
+// updateCompatibility stores, with each schema, the report of the changes
+// from its previous version.
+func (s *schemaService) updateCompatibility() error {
+	schemas, err := s.mongoRepo.FindAll()
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch tree from %s: %w",
-			fieldListURL, err)
+		return fmt.Errorf("failed to find schemas: %w", err)
 	}
 
-	// Initialize a map to hold the field path to URL mapping.
-	fieldListMap := make(map[string]string)
-
-	// Iterate over each field in the list.
-	for _, field := range fieldList {
-		fieldMap := field.(map[string]interface{})
-		fieldPath := fieldMap["path"].(string)
-		fieldURL := fieldMap["url"].(string)
-
-		// If the field has a non-empty path and URL, add it to the map.
-		if len(fieldPath) > 0 && len(fieldURL) > 0 {
-			fieldListMap[fieldPath] = fieldURL
-		} else {
-			return nil, fmt.Errorf("field path '%s' or url '%s' is empty",
-				fieldPath, fieldURL)
+	for name, report := range compatibilityReports(schemas) {
+		if err := s.mongoRepo.UpdateCompatibility(name, report); err != nil {
+			return fmt.Errorf(
+				"failed to update the compatibility report of %s: %w",
+				name, err,
+			)
 		}
 	}
-
-	// Return the map.
-	return fieldListMap, nil
+	return nil
 }
 
-// getGithubTree fetches and returns the GitHub repository's tree (list of
-// files and directories) given a URL to the tree API endpoint.
-//
-// https://docs.github.com/en/rest/git/trees?apiVersion=2022-11-28#get-a-tree.
-func getGithubTree(url string) ([]interface{}, error) {
-	resp, err := httputil.GetWithBearerToken(url, config.Values.Github.TOKEN)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch data from %s: %w", url, err)
+// compatibilityReports compares each versioned schema with its previous
+// version. Schemas without a previous version get a nil report.
+func compatibilityReports(
+	schemas []*model.Schema,
+) map[string]*schemadiff.Report {
+	byName := make(map[string]*model.Schema, len(schemas))
+	names := make([]string, 0, len(schemas))
+	for _, schema := range schemas {
+		byName[schema.Name] = schema
+		names = append(names, schema.Name)
 	}
 
-	defer resp.Body.Close()
+	reports := make(map[string]*schemadiff.Report)
+	for _, schema := range schemas {
+		// A name giving only a major version isn't a version of a schema.
+		name, ok := schemaname.Parse(schema.Name)
+		if !ok || name.IsRange {
+			continue
+		}
+		reports[schema.Name] = nil
 
-	var data map[string]interface{}
-	err = json.NewDecoder(resp.Body).Decode(&data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode JSON response: %w", err)
+		// Versions are sorted newest first, so the previous version follows.
+		versions := schemaname.Versions(names, name.Base)
+		if len(versions) < 2 {
+			continue
+		}
+		for i, version := range versions[:len(versions)-1] {
+			if version.String() == schema.Name {
+				previous := byName[versions[i+1].String()]
+				reports[schema.Name] = schemadiff.Compare(
+					previous.Name, previous.FullSchema,
+					schema.Name, schema.FullSchema,
+				)
+				break
+			}
+		}
 	}
+	return reports
+}
 
-	tree := data["tree"].([]interface{})
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedVocabularyKeys(m schemaparser.Vocabularies) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
 
-	return tree, nil
+// lookupString returns the string value of the key in the document, or an
+// empty string if there is none.
+func lookupString(doc bson.D, key string) string {
+	for _, elem := range doc {
+		if elem.Key == key {
+			value, _ := elem.Value.(string)
+			return value
+		}
+	}
+	return ""
 }
 
-func (s *schemaService) GetUpdateError() (string, error) {
-	val, err := s.redis.Get("schemas:update:error")
+// GetUpdateError returns the error that stopped the last update, or nil if
+// there is none.
+func (s *schemaService) GetUpdateError() (*model.UpdateError, error) {
+	val, err := s.redis.Get(UpdateErrorKey)
 	if err != nil {
-		return "", fmt.Errorf("failed to retrieve update error from Redis: %w", err)
+		return nil, fmt.Errorf("failed to retrieve update error from Redis: %w", err)
 	}
 
 	if val == "" {
-		return "", nil
+		return nil, nil
 	}
 
-	return val, nil
+	var updateError model.UpdateError
+	// Errors recorded before they were structured are plain messages.
+	if err := json.Unmarshal([]byte(val), &updateError); err != nil {
+		return &model.UpdateError{Message: val}, nil
+	}
+	return &updateError, nil
 }
@@ -1,40 +1,401 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/schemadiff"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/repository/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/schemaparser"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/source"
 )
 
-func TestShouldSetLastCommitTime(t *testing.T) {
-	t.Run("oldLastCommitTime is empty", func(t *testing.T) {
-		oldLastCommitTime := ""
-		newLastCommitTime := "2021-02-19T00:04:00Z"
-		ok, _ := shouldSetLastCommitTime(oldLastCommitTime, newLastCommitTime)
-		assert.Equal(t, true, ok)
-	})
-	t.Run("newLastCommitTime is empty", func(t *testing.T) {
-		oldLastCommitTime := "2021-02-19T00:00:00Z"
-		newLastCommitTime := ""
-		ok, _ := shouldSetLastCommitTime(oldLastCommitTime, newLastCommitTime)
-		assert.Equal(t, false, ok)
+func TestCompatibilityReports(t *testing.T) {
+	schema := func(name string, required ...interface{}) *model.Schema {
+		return &model.Schema{
+			Name: name,
+			FullSchema: bson.D{
+				{Key: "type", Value: "object"},
+				{Key: "required", Value: bson.A(required)},
+			},
+		}
+	}
+	reports := compatibilityReports([]*model.Schema{
+		schema("test_schema-v1.1.0", "name", "url"),
+		schema("test_schema-v1.0.0", "name"),
+		schema("test_schema-v2.0.0", "name"),
+		schema("other_schema-v1.0.0"),
+		schema("unversioned"),
+		schema("range_schema-v1"),
 	})
-	t.Run("no time difference", func(t *testing.T) {
-		oldLastCommitTime := "2021-02-19T00:00:00Z"
-		newLastCommitTime := "2021-02-19T00:00:00Z"
-		ok, _ := shouldSetLastCommitTime(oldLastCommitTime, newLastCommitTime)
-		assert.Equal(t, false, ok)
+
+	assert.Len(t, reports, 4)
+	assert.Nil(t, reports["test_schema-v1.0.0"])
+	assert.Nil(t, reports["other_schema-v1.0.0"])
+	assert.NotContains(t, reports, "range_schema-v1")
+
+	assert.Equal(t, "test_schema-v1.0.0", reports["test_schema-v1.1.0"].From)
+	assert.True(t, reports["test_schema-v1.1.0"].Breaking)
+
+	assert.Equal(t, "test_schema-v1.1.0", reports["test_schema-v2.0.0"].From)
+	assert.False(t, reports["test_schema-v2.0.0"].Breaking)
+}
+
+func TestLint(t *testing.T) {
+	fields := map[string][]byte{
+		"linked_schemas.json": []byte(`{"type": "array", "items": {"type": "string"}}`),
+	}
+	valid := []byte(`{
+		"type": "object",
+		"properties": {"linked_schemas": {"$ref": "../fields/linked_schemas.json"}},
+		"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+	}`)
+	missingRef := []byte(`{
+		"type": "object",
+		"properties": {"linked_schemas": {"$ref": "../fields/tags.json"}},
+		"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+	}`)
+
+	issues := lint([]*source.Library{
+		{
+			Schemas: map[string][]byte{"test_schema-v1.0.0.json": valid},
+			Fields:  fields,
+			Vocabularies: map[string][]byte{
+				"categories-v1.0.0.json": []byte(`{
+					"name": "categories-v1.0.0",
+					"terms": [{"id": "commercial"}, {"id": "commercial"}]
+				}`),
+			},
+		},
+		{
+			Namespace: "fork",
+			Schemas:   map[string][]byte{"test_schema-v1.0.0.json": missingRef},
+			Fields:    fields,
+		},
 	})
-	t.Run("should not set last commit time", func(t *testing.T) {
-		oldLastCommitTime := "2021-02-19T00:00:00Z"
-		newLastCommitTime := "2021-02-19T00:05:00Z"
-		ok, _ := shouldSetLastCommitTime(oldLastCommitTime, newLastCommitTime)
-		assert.Equal(t, false, ok)
+
+	assert.Len(t, issues, 2)
+	assert.Equal(t, "vocabularies/categories-v1.0.0.json", issues[0].File)
+	assert.Equal(t, schemaparser.RuleDuplicateTerm, issues[0].Rule)
+	assert.Equal(t, "/terms/1/id", issues[0].Pointer)
+	assert.Equal(t, "fork:schemas/test_schema-v1.0.0.json", issues[1].File)
+	assert.Equal(t, schemaparser.RuleUnresolvedRef, issues[1].Rule)
+	assert.Equal(t, "/properties/linked_schemas/$ref", issues[1].Pointer)
+}
+
+func TestSchemaRevision(t *testing.T) {
+	schema := bson.D{
+		{Key: "type", Value: "object"},
+		{Key: "required", Value: bson.A{"name"}},
+	}
+	revision, err := schemaRevision(schema)
+	assert.NoError(t, err)
+	assert.Len(t, revision, 64)
+
+	same, err := schemaRevision(bson.D{
+		{Key: "type", Value: "object"},
+		{Key: "required", Value: bson.A{"name"}},
 	})
-	t.Run("should set last commit time", func(t *testing.T) {
-		oldLastCommitTime := "2021-02-19T00:00:00Z"
-		newLastCommitTime := "2021-02-19T00:11:00Z"
-		ok, _ := shouldSetLastCommitTime(oldLastCommitTime, newLastCommitTime)
-		assert.Equal(t, true, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, revision, same)
+
+	changed, err := schemaRevision(bson.D{
+		{Key: "type", Value: "object"},
+		{Key: "required", Value: bson.A{"name", "url"}},
 	})
+	assert.NoError(t, err)
+	assert.NotEqual(t, revision, changed)
+}
+
+// fakeRedis keeps the values in memory.
+type fakeRedis map[string]string
+
+func (r fakeRedis) Ping() error { return nil }
+
+func (r fakeRedis) Set(key string, value interface{}, _ time.Duration) error {
+	r[key] = value.(string)
+	return nil
+}
+
+func (r fakeRedis) Get(key string) (string, error) { return r[key], nil }
+
+func (r fakeRedis) Del(keys ...string) error {
+	for _, key := range keys {
+		delete(r, key)
+	}
+	return nil
+}
+
+func TestRestoreLastVersion(t *testing.T) {
+	svc := &schemaService{redis: fakeRedis{}}
+	versions := func() (string, string) {
+		last, err := svc.GetLastVersion()
+		assert.NoError(t, err)
+		previous, err := svc.GetPreviousVersion()
+		assert.NoError(t, err)
+		return last, previous
+	}
+
+	assert.NoError(t, svc.SetLastVersion("good"))
+	assert.NoError(t, svc.SetLastVersion("bad"))
+	last, previous := versions()
+	assert.Equal(t, "bad", last)
+	assert.Equal(t, "good", previous)
+
+	// Rolling back twice in a row never returns to the bad version.
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, svc.RestoreLastVersion(previous))
+		last, previous = versions()
+		assert.Equal(t, "good", last)
+		assert.Equal(t, "good", previous)
+	}
+}
+
+// fakeSchemaRepo keeps the schemas in memory.
+type fakeSchemaRepo struct {
+	mongo.SchemaRepository
+	schemas map[string]*model.Schema
+}
+
+func (r *fakeSchemaRepo) Update(schema *model.Schema) (bool, error) {
+	stored, ok := r.schemas[schema.Name]
+	r.schemas[schema.Name] = schema
+	return !ok || stored.Revision != schema.Revision, nil
+}
+
+func (r *fakeSchemaRepo) FindAll() ([]*model.Schema, error) {
+	schemas := make([]*model.Schema, 0, len(r.schemas))
+	for _, schema := range r.schemas {
+		schemas = append(schemas, schema)
+	}
+	return schemas, nil
+}
+
+func (r *fakeSchemaRepo) UpdateCompatibility(string, *schemadiff.Report) error {
+	return nil
+}
+
+// fakeFieldRepo fails to delete the removed fields if err is set.
+type fakeFieldRepo struct {
+	err error
+}
+
+func (r *fakeFieldRepo) Update(*model.Field) error { return nil }
+
+func (r *fakeFieldRepo) DeleteOthers([]string) error { return r.err }
+
+type fakeVocabularyRepo struct{}
+
+func (r fakeVocabularyRepo) Update(*model.Vocabulary) error { return nil }
+
+func (r fakeVocabularyRepo) DeleteOthers([]string) error { return nil }
+
+// fakeRevisionRepo keeps the revisions and loads in memory. As the updates
+// of a test run within the same second, the number of loads logged before an
+// update stands in for its time.
+type fakeRevisionRepo struct {
+	revisions []*model.SchemaRevision
+	loads     []*model.SourcesLoad
+}
+
+func (r *fakeRevisionRepo) Record(
+	revision *model.SchemaRevision,
+	load model.SchemaLoad,
+	changed bool,
+) error {
+	load.LoadedAt = int64(len(r.loads))
+	for _, stored := range r.revisions {
+		if stored.Name == revision.Name && stored.Revision == revision.Revision {
+			if changed {
+				stored.Loads = append(stored.Loads, load)
+			}
+			return nil
+		}
+	}
+	revision.Loads = []model.SchemaLoad{load}
+	r.revisions = append(r.revisions, revision)
+	return nil
+}
+
+func (r *fakeRevisionRepo) RecordLoad(load *model.SourcesLoad) error {
+	load.LoadedAt = int64(len(r.loads))
+	r.loads = append(r.loads, load)
+	return nil
+}
+
+func (r *fakeRevisionRepo) FindLoad(version string) (*model.SourcesLoad, error) {
+	var found *model.SourcesLoad
+	for _, load := range r.loads {
+		if load.Version == version {
+			found = load
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeRevisionRepo) FindAll() ([]*model.SchemaRevision, error) {
+	return r.revisions, nil
+}
+
+func TestUpdateSchemasRetry(t *testing.T) {
+	fieldRepo := &fakeFieldRepo{}
+	svc := &schemaService{
+		mongoRepo:      &fakeSchemaRepo{schemas: map[string]*model.Schema{}},
+		fieldRepo:      fieldRepo,
+		vocabularyRepo: fakeVocabularyRepo{},
+		revisionRepo:   &fakeRevisionRepo{},
+		redis:          fakeRedis{},
+	}
+	library := func(version, title string) []*source.Library {
+		return []*source.Library{{
+			Version: version,
+			Schemas: map[string][]byte{"test_schema-v1.0.0.json": []byte(`{
+				"title": "` + title + `",
+				"type": "object",
+				"properties": {"linked_schemas": {"$ref": "../fields/linked_schemas.json"}},
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+			}`)},
+			Fields: map[string][]byte{
+				"linked_schemas.json": []byte(`{"type": "array", "items": {"type": "string"}}`),
+			},
+		}}
+	}
+
+	changed, err := svc.UpdateSchemas(library("v1", "Test"), "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test_schema-v1.0.0"}, changed)
+	assert.NoError(t, svc.SetLastVersion("v1"))
+
+	changed, err = svc.UpdateSchemas(library("v1", "Test"), "v1")
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+
+	// The update fails after storing the changed schema.
+	fieldRepo.err = errors.New("failed")
+	_, err = svc.UpdateSchemas(library("v2", "Changed"), "v2")
+	assert.Error(t, err)
+
+	// The schema is still reported as changed when the update is retried.
+	fieldRepo.err = nil
+	changed, err = svc.UpdateSchemas(library("v2", "Changed"), "v2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test_schema-v1.0.0"}, changed)
+}
+
+func TestRevisionsAt(t *testing.T) {
+	revision := func(name, id string, loads ...model.SchemaLoad) *model.SchemaRevision {
+		return &model.SchemaRevision{Name: name, Revision: id, Loads: loads}
+	}
+	load := func(version string, loadedAt int64) model.SchemaLoad {
+		return model.SchemaLoad{Commit: version, Version: version, LoadedAt: loadedAt}
+	}
+	// test_schema changed at v2 and changed back at v4, other_schema was
+	// added at v3. The revisions recorded before the loads were logged have
+	// a load for every version.
+	revisions := []*model.SchemaRevision{
+		revision("test_schema-v1.0.0", "r1", load("v1", 100), load("v4", 400)),
+		revision("test_schema-v1.0.0", "r2", load("v2", 200)),
+		revision("other_schema-v1.0.0", "o1", load("v3", 300)),
+		revision("legacy_schema-v1.0.0", "l1", load("v0", 50), load("v1", 100)),
+	}
+
+	tests := []struct {
+		name     string
+		version  string
+		load     *model.SourcesLoad
+		expected map[string]string
+	}{
+		{
+			name:    "Unchanged",
+			version: "v3",
+			load:    &model.SourcesLoad{Version: "v3", LoadedAt: 300},
+			expected: map[string]string{
+				"test_schema-v1.0.0":   "r2",
+				"other_schema-v1.0.0":  "o1",
+				"legacy_schema-v1.0.0": "l1",
+			},
+		},
+		{
+			name:    "Changed back",
+			version: "v5",
+			load:    &model.SourcesLoad{Version: "v5", LoadedAt: 500},
+			expected: map[string]string{
+				"test_schema-v1.0.0":   "r1",
+				"other_schema-v1.0.0":  "o1",
+				"legacy_schema-v1.0.0": "l1",
+			},
+		},
+		{
+			name:    "Not logged",
+			version: "v1",
+			expected: map[string]string{
+				"test_schema-v1.0.0":   "r1",
+				"legacy_schema-v1.0.0": "l1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, revisionsAt(revisions, tt.version, tt.load))
+		})
+	}
+}
+
+func TestUpdateSchemasRevisions(t *testing.T) {
+	revisionRepo := &fakeRevisionRepo{}
+	svc := &schemaService{
+		mongoRepo:      &fakeSchemaRepo{schemas: map[string]*model.Schema{}},
+		fieldRepo:      &fakeFieldRepo{},
+		vocabularyRepo: fakeVocabularyRepo{},
+		revisionRepo:   revisionRepo,
+		redis:          fakeRedis{},
+	}
+	library := func(version, title string) []*source.Library {
+		return []*source.Library{{
+			Version: version,
+			Schemas: map[string][]byte{"test_schema-v1.0.0.json": []byte(`{
+				"title": "` + title + `",
+				"type": "object",
+				"properties": {"linked_schemas": {"$ref": "../fields/linked_schemas.json"}},
+				"metadata": {"schema": {"name": "test_schema-v1.0.0"}}
+			}`)},
+			Fields: map[string][]byte{
+				"linked_schemas.json": []byte(`{"type": "array", "items": {"type": "string"}}`),
+			},
+		}}
+	}
+
+	// The schema changes once in 150 loads, which only adds a load to the
+	// revisions when it changes.
+	for i := 0; i < 150; i++ {
+		title := "Test"
+		if i >= 120 {
+			title = "Changed"
+		}
+		version := fmt.Sprintf("v%d", i)
+		_, err := svc.UpdateSchemas(library(version, title), version)
+		assert.NoError(t, err)
+		assert.NoError(t, svc.SetLastVersion(version))
+	}
+
+	assert.Len(t, revisionRepo.loads, 150)
+	assert.Len(t, revisionRepo.revisions, 2)
+	for _, revision := range revisionRepo.revisions {
+		assert.Len(t, revision.Loads, 1)
+	}
+	assert.Equal(t, "v0", revisionRepo.revisions[0].Loads[0].Version)
+	assert.Equal(t, "v120", revisionRepo.revisions[1].Loads[0].Version)
+
+	published, err := svc.publishedRevisions()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"test_schema-v1.0.0": revisionRepo.revisions[1].Revision,
+	}, published)
 }
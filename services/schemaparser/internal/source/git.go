@@ -0,0 +1,173 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// Git clones the schemas and fields from a git repository. It requires the
+// git command.
+type Git struct {
+	url string
+	// ref is the branch or tag to read, or empty for the default branch.
+	ref string
+}
+
+// NewGit creates a source cloning the git repository at url and reading the
+// ref, a branch or tag, or the default branch if ref is empty.
+func NewGit(url string, ref string) *Git {
+	return &Git{url: url, ref: ref}
+}
+
+func (g *Git) Name() string {
+	if g.ref == "" {
+		return KindGit + ":" + g.url
+	}
+	return KindGit + ":" + g.url + "#" + g.ref
+}
+
+// Version returns the commit the ref points to, without cloning the
+// repository.
+func (g *Git) Version() (string, error) {
+	ref := g.ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	out, err := runGit("", "ls-remote", g.url, ref)
+	if err != nil {
+		return "", err
+	}
+
+	// Annotated tags are listed along with the commit they point to, which
+	// ends with ^{}.
+	var version string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if version == "" || strings.HasSuffix(fields[1], "^{}") {
+			version = fields[0]
+		}
+	}
+	if version == "" {
+		return "", fmt.Errorf("ref %s not found in %s", ref, g.url)
+	}
+	return version, nil
+}
+
+// Fetch clones the ref. If it moved since version was read, the library is
+// read at the commit it points to now.
+func (g *Git) Fetch(version string) (*Library, error) {
+	dir, err := os.MkdirTemp("", "schemaparser-git-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a directory to clone into: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if g.ref != "" {
+		args = append(args, "--branch", g.ref)
+	}
+	if _, err := runGit("", append(args, g.url, dir)...); err != nil {
+		return nil, err
+	}
+	return g.read(dir)
+}
+
+// FetchRevision clones the repository and reads the commit, branch or tag
+// given as version.
+func (g *Git) FetchRevision(version string) (*Library, error) {
+	dir, err := os.MkdirTemp("", "schemaparser-git-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a directory to clone into: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := runGit("", "clone", "--quiet", "--no-checkout", g.url, dir); err != nil {
+		return nil, err
+	}
+	commit, err := resolveRevision(dir, version)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := runGit(dir, "checkout", "--quiet", "--detach", commit); err != nil {
+		return nil, err
+	}
+	return g.read(dir)
+}
+
+// commitHash matches full and abbreviated commit hashes.
+var commitHash = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// refName matches the names of branches and tags, leaving out the revision
+// syntax of git, e.g. main~1.
+var refName = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9._/-]*$`)
+
+// resolveRevision returns the commit of the repository cloned in dir that
+// the version names: a commit hash, or a branch or tag of the repository.
+// Anything else is rejected before it reaches git.
+func resolveRevision(dir string, version string) (string, error) {
+	var candidates []string
+	if commitHash.MatchString(version) {
+		candidates = append(candidates, version)
+	}
+	if refName.MatchString(version) && !strings.Contains(version, "..") {
+		candidates = append(
+			candidates,
+			"refs/tags/"+version,
+			"refs/remotes/origin/"+version,
+		)
+	}
+	for _, candidate := range candidates {
+		commit, err := runGit(
+			dir,
+			"rev-parse", "--verify", "--quiet", "--end-of-options",
+			candidate+"^{commit}",
+		)
+		if err == nil {
+			return strings.TrimSpace(commit), nil
+		}
+	}
+	return "", fmt.Errorf("unknown revision %q, expected a commit, branch or tag", version)
+}
+
+// read reads the library from the checked out repository in dir.
+func (g *Git) read(dir string) (*Library, error) {
+	commit, err := runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	library, err := readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	library.Version = strings.TrimSpace(commit)
+	return library, nil
+}
+
+// runGit runs git with the arguments in dir, or in the current directory if
+// dir is empty, and returns its output.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	// Fail instead of waiting for credentials.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf(
+			"git %s failed: %w: %s",
+			args[0],
+			err,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,203 @@
+package source
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// maxGoroutines is the number of files a GitHub source downloads at a time.
+const maxGoroutines = 10
+
+// GitHub reads the schemas and fields of a branch of a GitHub repository
+// with the GitHub REST API.
+type GitHub struct {
+	branchURL string
+	// repoURL is the API URL of the repository, e.g.
+	// https://api.github.com/repos/MurmurationsNetwork/MurmurationsLibrary.
+	repoURL string
+	token   string
+	client  *http.Client
+}
+
+// NewGitHub creates a source reading the branch at branchURL, the API URL of
+// the branch, e.g.
+// https://api.github.com/repos/MurmurationsNetwork/MurmurationsLibrary/branches/main.
+// The requests are authenticated with the token if it isn't empty.
+func NewGitHub(branchURL string, token string) (*GitHub, error) {
+	i := strings.LastIndex(branchURL, "/branches/")
+	if i < 0 {
+		return nil, fmt.Errorf(
+			"invalid GitHub branch URL %s, expected .../repos/<owner>/<repo>/branches/<branch>",
+			branchURL,
+		)
+	}
+	return &GitHub{
+		branchURL: branchURL,
+		repoURL:   branchURL[:i],
+		token:     token,
+		client:    &http.Client{Timeout: time.Minute},
+	}, nil
+}
+
+func (g *GitHub) Name() string {
+	return KindGitHub + ":" + g.branchURL
+}
+
+// branchInfo is the part of the branch the source uses.
+//
+// https://docs.github.com/en/rest/branches/branches?apiVersion=2022-11-28#get-a-branch.
+type branchInfo struct {
+	Commit struct {
+		Sha string `json:"sha"`
+	} `json:"commit"`
+}
+
+// tree lists the files of a commit.
+//
+// https://docs.github.com/en/rest/git/trees?apiVersion=2022-11-28#get-a-tree.
+type tree struct {
+	Tree []struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"tree"`
+	Truncated bool `json:"truncated"`
+}
+
+// blob is the content of a file.
+//
+// https://docs.github.com/en/rest/git/blobs?apiVersion=2022-11-28#get-a-blob.
+type blob struct {
+	Content string `json:"content"`
+}
+
+// Version returns the last commit of the branch.
+func (g *GitHub) Version() (string, error) {
+	var branch branchInfo
+	if err := g.get(g.branchURL, &branch); err != nil {
+		return "", err
+	}
+	if branch.Commit.Sha == "" {
+		return "", fmt.Errorf("no commit found in %s", g.branchURL)
+	}
+	return branch.Commit.Sha, nil
+}
+
+// commitInfo is the part of a commit the source uses.
+//
+// https://docs.github.com/en/rest/commits/commits?apiVersion=2022-11-28#get-a-commit.
+type commitInfo struct {
+	Sha string `json:"sha"`
+}
+
+// FetchRevision downloads the schemas and fields of a commit, branch or tag
+// of the repository. The version is resolved to its commit first, and
+// anything else is rejected before it reaches the GitHub API.
+func (g *GitHub) FetchRevision(version string) (*Library, error) {
+	if !commitHash.MatchString(version) &&
+		(!refName.MatchString(version) || strings.Contains(version, "..")) {
+		return nil, fmt.Errorf("invalid revision %q", version)
+	}
+
+	var commit commitInfo
+	if err := g.get(g.repoURL+"/commits/"+version, &commit); err != nil {
+		return nil, err
+	}
+	if commit.Sha == "" {
+		return nil, fmt.Errorf("no commit found for %s", version)
+	}
+	return g.Fetch(commit.Sha)
+}
+
+// Fetch downloads the schemas and fields of the commit.
+func (g *GitHub) Fetch(version string) (*Library, error) {
+	var commitTree tree
+	treeURL := g.repoURL + "/git/trees/" + version + "?recursive=1"
+	if err := g.get(treeURL, &commitTree); err != nil {
+		return nil, err
+	}
+	if commitTree.Truncated {
+		return nil, fmt.Errorf("the tree of %s is too large to be listed", treeURL)
+	}
+
+	var (
+		mu    sync.Mutex
+		files = make(map[string][]byte)
+		group errgroup.Group
+	)
+	group.SetLimit(maxGoroutines)
+	for _, entry := range commitTree.Tree {
+		entry := entry
+		dir, _, _ := strings.Cut(entry.Path, "/")
+		if entry.Type != "blob" || !isLibraryDir(dir) ||
+			!strings.HasSuffix(entry.Path, ".json") {
+			continue
+		}
+		group.Go(func() error {
+			var content blob
+			if err := g.get(entry.URL, &content); err != nil {
+				return err
+			}
+			data, err := base64.StdEncoding.DecodeString(content.Content)
+			if err != nil {
+				return fmt.Errorf("failed to decode %s: %w", entry.Path, err)
+			}
+			if len(data) == 0 {
+				return fmt.Errorf("%s is empty", entry.Path)
+			}
+			mu.Lock()
+			files[entry.Path] = data
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no schemas or fields found in %s", treeURL)
+	}
+	return newLibrary(version, files), nil
+}
+
+// get decodes the JSON response of the GitHub API to a GET request into v.
+func (g *GitHub) get(url string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create a request to %s: %w", url, err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch data from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf(
+			"failed to fetch data from %s, status code: %d: %s",
+			url,
+			resp.StatusCode,
+			strings.TrimSpace(string(body)),
+		)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode the response of %s: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package source
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Local reads the schemas and fields from a directory.
+type Local struct {
+	dir string
+	// library caches the content read by Version for Fetch.
+	library *Library
+}
+
+// NewLocal creates a source reading the schemas, fields and vocabularies
+// from the schemas, fields and vocabularies subdirectories of dir.
+func NewLocal(dir string) *Local {
+	return &Local{dir: dir}
+}
+
+func (l *Local) Name() string {
+	return KindLocal + ":" + l.dir
+}
+
+// Version hashes the content of the schemas and fields.
+func (l *Local) Version() (string, error) {
+	library, err := readDir(l.dir)
+	if err != nil {
+		return "", err
+	}
+	library.Version = contentVersion(library)
+	l.library = library
+	return library.Version, nil
+}
+
+func (l *Local) Fetch(version string) (*Library, error) {
+	if l.library != nil && l.library.Version == version {
+		return l.library, nil
+	}
+	if _, err := l.Version(); err != nil {
+		return nil, err
+	}
+	return l.library, nil
+}
+
+// readDir reads the schemas, fields and vocabularies of the repository in
+// dir. Missing directories are read as empty.
+func readDir(dir string) (*Library, error) {
+	files := make(map[string][]byte)
+	for _, sub := range libraryDirs {
+		root := filepath.Join(dir, sub)
+		err := filepath.WalkDir(
+			root,
+			func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if d.IsDir() || filepath.Ext(path) != ".json" {
+					return nil
+				}
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				relPath, err := filepath.Rel(dir, path)
+				if err != nil {
+					return err
+				}
+				files[filepath.ToSlash(relPath)] = data
+				return nil
+			},
+		)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read %s: %w", root, err)
+		}
+	}
+	return newLibrary("", files), nil
+}
@@ -0,0 +1,287 @@
+// Package source reads the schemas and the fields and vocabularies they
+// reference from the schema repositories a deployment is configured with.
+// Each repository is a SchemaSource, e.g. a GitHub repository, a local
+// directory, a git repository or an archive served over HTTP, laid out like
+// the Murmurations Library: JSON schemas in a schemas directory, JSON fields
+// in a fields directory and JSON vocabularies in a vocabularies directory.
+package source
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Kinds of sources.
+const (
+	KindGitHub  = "github"
+	KindLocal   = "local"
+	KindGit     = "git"
+	KindTarball = "tarball"
+)
+
+// NamespaceSeparator separates the namespace of a source from the names of
+// its schemas and fields, e.g. "fork:organizations_schema-v1.0.0".
+const NamespaceSeparator = ":"
+
+// Directories of the schemas, fields and vocabularies in a source.
+const (
+	SchemasDir      = "schemas"
+	FieldsDir       = "fields"
+	VocabulariesDir = "vocabularies"
+)
+
+// libraryDirs lists the directories read from a source.
+var libraryDirs = []string{SchemasDir, FieldsDir, VocabulariesDir}
+
+// isLibraryDir reports whether the top-level directory is read from a
+// source.
+func isLibraryDir(dir string) bool {
+	for _, d := range libraryDirs {
+		if d == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// SchemaSource is a repository of schemas and fields.
+type SchemaSource interface {
+	// Name describes the source, e.g. in logs.
+	Name() string
+	// Version identifies the current content of the source, e.g. a commit,
+	// and changes whenever the content does.
+	Version() (string, error)
+	// Fetch reads the schemas and fields of the source at the version.
+	Fetch(version string) (*Library, error)
+}
+
+// Revisioned is implemented by the sources that can read their content at
+// past versions, e.g. at the commits of a repository.
+type Revisioned interface {
+	// FetchRevision reads the schemas and fields at the version, which
+	// doesn't have to be the current one.
+	FetchRevision(version string) (*Library, error)
+}
+
+// Library is the content of a source.
+type Library struct {
+	// Namespace is prefixed to the names of the schemas and fields, or
+	// empty.
+	Namespace string
+	// Version is the version of the source the content was read at.
+	Version string
+	// Schemas maps the paths of the schema files, relative to the schemas
+	// directory, to their content.
+	Schemas map[string][]byte
+	// Fields maps the paths of the field files, relative to the fields
+	// directory, to their content.
+	Fields map[string][]byte
+	// Vocabularies maps the paths of the vocabulary files, relative to the
+	// vocabularies directory, to their content.
+	Vocabularies map[string][]byte
+}
+
+// Name returns the name of a schema or field of the library, prefixed with
+// the namespace of the library.
+func (l *Library) Name(name string) string {
+	if l.Namespace == "" {
+		return name
+	}
+	return l.Namespace + NamespaceSeparator + name
+}
+
+// Namespaced is a source whose schemas and fields are named under a
+// namespace.
+type Namespaced struct {
+	SchemaSource
+	// Namespace is empty for the sources whose names are used as is.
+	Namespace string
+}
+
+// Fetch reads the schemas and fields of the source at the version and puts
+// them under the namespace of the source.
+func (n Namespaced) Fetch(version string) (*Library, error) {
+	library, err := n.SchemaSource.Fetch(version)
+	if err != nil {
+		return nil, err
+	}
+	library.Namespace = n.Namespace
+	return library, nil
+}
+
+// FetchRevision reads the schemas and fields of the source at the version,
+// which may be a past version if the source is Revisioned, and puts them
+// under the namespace of the source.
+func (n Namespaced) FetchRevision(version string) (*Library, error) {
+	revisioned, ok := n.SchemaSource.(Revisioned)
+	if !ok {
+		current, err := n.Version()
+		if err != nil {
+			return nil, err
+		}
+		if current != version {
+			return nil, fmt.Errorf(
+				"%s can only be read at its current version %s, not %s",
+				n.Name(),
+				current,
+				version,
+			)
+		}
+		return n.Fetch(version)
+	}
+
+	library, err := revisioned.FetchRevision(version)
+	if err != nil {
+		return nil, err
+	}
+	library.Namespace = n.Namespace
+	return library, nil
+}
+
+// Options holds the settings shared by the sources.
+type Options struct {
+	// GitHubToken authenticates the requests to the GitHub API, if set.
+	GitHubToken string
+}
+
+// Parse creates the sources described by the specs. A spec has the form
+// [<namespace>=]<kind>:<location>, e.g.
+//
+//	github:https://api.github.com/repos/MurmurationsNetwork/MurmurationsLibrary/branches/main
+//	local:library
+//	fork=git:https://git.example.org/schemas.git#main
+//	fork=tarball:https://example.org/library.tar.gz
+//
+// The sources must have distinct namespaces, except for those without one.
+func Parse(specs []string, opts Options) ([]Namespaced, error) {
+	var sources []Namespaced
+	namespaces := make(map[string]bool)
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		var namespace string
+		if i := strings.Index(spec, "="); i >= 0 && i < strings.Index(spec, ":") {
+			namespace, spec = spec[:i], spec[i+1:]
+			if !validNamespace(namespace) {
+				return nil, fmt.Errorf("invalid namespace %q", namespace)
+			}
+			if namespaces[namespace] {
+				return nil, fmt.Errorf("duplicate namespace %q", namespace)
+			}
+			namespaces[namespace] = true
+		}
+
+		kind, location, ok := strings.Cut(spec, ":")
+		if !ok || location == "" {
+			return nil, fmt.Errorf(
+				"invalid schema source %q, expected <kind>:<location>",
+				spec,
+			)
+		}
+
+		var src SchemaSource
+		switch kind {
+		case KindGitHub:
+			github, err := NewGitHub(location, opts.GitHubToken)
+			if err != nil {
+				return nil, err
+			}
+			src = github
+		case KindLocal:
+			src = NewLocal(location)
+		case KindGit:
+			url, ref, _ := strings.Cut(location, "#")
+			src = NewGit(url, ref)
+		case KindTarball:
+			src = NewTarball(location)
+		default:
+			return nil, fmt.Errorf("unknown kind of schema source %q", kind)
+		}
+		sources = append(sources, Namespaced{SchemaSource: src, Namespace: namespace})
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no schema source configured")
+	}
+	return sources, nil
+}
+
+// validNamespace reports whether the namespace only contains lowercase
+// letters, digits, underscores and hyphens.
+func validNamespace(namespace string) bool {
+	if namespace == "" {
+		return false
+	}
+	for _, r := range namespace {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// newLibrary picks the JSON schemas, fields and vocabularies among the files
+// of a repository, given by their slash-separated paths relative to its root.
+func newLibrary(version string, files map[string][]byte) *Library {
+	library := &Library{
+		Version:      version,
+		Schemas:      make(map[string][]byte),
+		Fields:       make(map[string][]byte),
+		Vocabularies: make(map[string][]byte),
+	}
+	for name, content := range files {
+		if path.Ext(name) != ".json" {
+			continue
+		}
+		dir, rel, _ := strings.Cut(name, "/")
+		switch dir {
+		case SchemasDir:
+			library.Schemas[rel] = content
+		case FieldsDir:
+			library.Fields[rel] = content
+		case VocabulariesDir:
+			library.Vocabularies[rel] = content
+		}
+	}
+	return library
+}
+
+// contentVersion identifies the content of the schemas, fields and
+// vocabularies of a repository by hashing it. The vocabularies are only
+// hashed when there are some, so that the versions of the libraries without
+// vocabularies stay the same.
+func contentVersion(library *Library) string {
+	hash := sha256.New()
+	dirs := []struct {
+		name  string
+		files map[string][]byte
+	}{
+		{SchemasDir, library.Schemas},
+		{FieldsDir, library.Fields},
+		{VocabulariesDir, library.Vocabularies},
+	}
+	for _, dir := range dirs {
+		files := dir.files
+		if dir.name == VocabulariesDir && len(files) == 0 {
+			continue
+		}
+		fmt.Fprintf(hash, "%s\x00", dir.name)
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(hash, "%s\x00%d\x00", name, len(files[name]))
+			hash.Write(files[name])
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
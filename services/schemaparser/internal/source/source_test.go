@@ -0,0 +1,396 @@
+package source_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/source"
+)
+
+var files = map[string]string{
+	"schemas/test_schema-v1.0.0.json": `{"title": "Test Schema"}`,
+	"fields/name.json":                `{"title": "Name"}`,
+	"vocabularies/colors-v1.0.0.json": `{"name": "colors-v1.0.0"}`,
+	"README.md":                       "# Library",
+}
+
+var expectedSchemas = map[string][]byte{
+	"test_schema-v1.0.0.json": []byte(`{"title": "Test Schema"}`),
+}
+
+var expectedFields = map[string][]byte{
+	"name.json": []byte(`{"title": "Name"}`),
+}
+
+var expectedVocabularies = map[string][]byte{
+	"colors-v1.0.0.json": []byte(`{"name": "colors-v1.0.0"}`),
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		specs         []string
+		expNames      []string
+		expNamespaces []string
+		expErr        bool
+	}{
+		{
+			name: "Sources",
+			specs: []string{
+				"github:https://api.github.com/repos/org/library/branches/main",
+				" local:library ",
+				"fork=git:https://git.example.org/schemas.git#main",
+				"archive=tarball:https://example.org/library.tar.gz?token=abc",
+			},
+			expNames: []string{
+				"github:https://api.github.com/repos/org/library/branches/main",
+				"local:library",
+				"git:https://git.example.org/schemas.git#main",
+				"tarball:https://example.org/library.tar.gz?token=abc",
+			},
+			expNamespaces: []string{"", "", "fork", "archive"},
+		},
+		{
+			name:   "No source",
+			specs:  []string{""},
+			expErr: true,
+		},
+		{
+			name:   "Unknown kind",
+			specs:  []string{"svn:https://svn.example.org/schemas"},
+			expErr: true,
+		},
+		{
+			name:   "Missing location",
+			specs:  []string{"local:"},
+			expErr: true,
+		},
+		{
+			name:   "Invalid GitHub branch URL",
+			specs:  []string{"github:https://api.github.com/repos/org/library"},
+			expErr: true,
+		},
+		{
+			name:   "Invalid namespace",
+			specs:  []string{"Fork=local:library"},
+			expErr: true,
+		},
+		{
+			name:   "Duplicate namespace",
+			specs:  []string{"fork=local:library", "fork=local:other"},
+			expErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources, err := source.Parse(tt.specs, source.Options{})
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var names, namespaces []string
+			for _, src := range sources {
+				names = append(names, src.Name())
+				namespaces = append(namespaces, src.Namespace)
+			}
+			require.Equal(t, tt.expNames, names)
+			require.Equal(t, tt.expNamespaces, namespaces)
+		})
+	}
+}
+
+func TestLibraryName(t *testing.T) {
+	library := &source.Library{}
+	require.Equal(t, "test_schema-v1.0.0", library.Name("test_schema-v1.0.0"))
+
+	library.Namespace = "fork"
+	require.Equal(t, "fork:test_schema-v1.0.0", library.Name("test_schema-v1.0.0"))
+}
+
+func TestLocal(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+
+	local := source.NewLocal(dir)
+	version, err := local.Version()
+	require.NoError(t, err)
+
+	library, err := local.Fetch(version)
+	require.NoError(t, err)
+	require.Equal(t, version, library.Version)
+	require.Equal(t, expectedSchemas, library.Schemas)
+	require.Equal(t, expectedFields, library.Fields)
+	require.Equal(t, expectedVocabularies, library.Vocabularies)
+
+	// The version only changes with the schemas, fields and vocabularies.
+	writeFiles(t, dir, map[string]string{"README.md": "# Changed"})
+	unchanged, err := source.NewLocal(dir).Version()
+	require.NoError(t, err)
+	require.Equal(t, version, unchanged)
+
+	writeFiles(t, dir, map[string]string{"fields/name.json": `{"title": "Full Name"}`})
+	changed, err := source.NewLocal(dir).Version()
+	require.NoError(t, err)
+	require.NotEqual(t, version, changed)
+
+	empty, err := source.NewLocal(t.TempDir()).Fetch("")
+	require.NoError(t, err)
+	require.Empty(t, empty.Schemas)
+	require.Empty(t, empty.Fields)
+}
+
+func TestTarball(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive []byte
+	}{
+		{
+			name:    "Gzipped tarball with a top-level directory",
+			archive: tarGz(t, "library-main/", files),
+		},
+		{
+			name:    "Zip file",
+			archive: zipFile(t, files),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write(tt.archive)
+				},
+			))
+			defer ts.Close()
+
+			tarball := source.NewTarball(ts.URL + "/library")
+			version, err := tarball.Version()
+			require.NoError(t, err)
+
+			library, err := tarball.Fetch(version)
+			require.NoError(t, err)
+			require.Equal(t, version, library.Version)
+			require.Equal(t, expectedSchemas, library.Schemas)
+			require.Equal(t, expectedFields, library.Fields)
+			require.Equal(t, expectedVocabularies, library.Vocabularies)
+		})
+	}
+}
+
+func TestTarballNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	_, err := source.NewTarball(ts.URL + "/library.tar.gz").Version()
+	require.Error(t, err)
+}
+
+func TestGitHub(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+			var resp interface{}
+			switch r.URL.Path {
+			case "/repos/org/library/branches/main":
+				resp = map[string]interface{}{
+					"commit": map[string]interface{}{"sha": "abc123"},
+				}
+			case "/repos/org/library/commits/v1.0":
+				resp = map[string]interface{}{"sha": "abc123"}
+			case "/repos/org/library/git/trees/abc123":
+				require.Equal(t, "1", r.URL.Query().Get("recursive"))
+				tree := []map[string]interface{}{
+					{"path": "schemas", "type": "tree", "url": ts.URL + "/tree"},
+				}
+				for name := range files {
+					tree = append(tree, map[string]interface{}{
+						"path": name,
+						"type": "blob",
+						"url":  ts.URL + "/blobs/" + name,
+					})
+				}
+				resp = map[string]interface{}{"tree": tree}
+			default:
+				content, ok := files[r.URL.Path[len("/blobs/"):]]
+				require.True(t, ok, r.URL.Path)
+				resp = map[string]interface{}{
+					"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+					"encoding": "base64",
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			require.NoError(t, json.NewEncoder(w).Encode(resp))
+		},
+	))
+	defer ts.Close()
+
+	github, err := source.NewGitHub(ts.URL+"/repos/org/library/branches/main", "token")
+	require.NoError(t, err)
+
+	version, err := github.Version()
+	require.NoError(t, err)
+	require.Equal(t, "abc123", version)
+
+	library, err := github.Fetch(version)
+	require.NoError(t, err)
+	require.Equal(t, "abc123", library.Version)
+	require.Equal(t, expectedSchemas, library.Schemas)
+	require.Equal(t, expectedFields, library.Fields)
+	require.Equal(t, expectedVocabularies, library.Vocabularies)
+
+	// Past revisions are resolved to their commit by FetchRevision.
+	library, err = github.FetchRevision("v1.0")
+	require.NoError(t, err)
+	require.Equal(t, "abc123", library.Version)
+
+	// Only commits, branches and tags are requested.
+	for _, revision := range []string{"../../user", "main~1", "main?ref=x"} {
+		_, err = github.FetchRevision(revision)
+		require.ErrorContains(t, err, "invalid revision", revision)
+	}
+}
+
+func TestGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch", "main"},
+		{"add", "."},
+		{
+			"-c", "user.name=test", "-c", "user.email=test@example.org",
+			"commit", "--quiet", "-m", "Add schemas",
+		},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	git := source.NewGit(dir, "main")
+	version, err := git.Version()
+	require.NoError(t, err)
+	require.Len(t, version, 40)
+
+	library, err := git.Fetch(version)
+	require.NoError(t, err)
+	require.Equal(t, version, library.Version)
+	require.Equal(t, expectedSchemas, library.Schemas)
+	require.Equal(t, expectedFields, library.Fields)
+	require.Equal(t, expectedVocabularies, library.Vocabularies)
+
+	_, err = source.NewGit(dir, "unknown").Version()
+	require.Error(t, err)
+
+	// Past commits are read by FetchRevision.
+	writeFiles(t, dir, map[string]string{"fields/name.json": `{"title": "Full Name"}`})
+	cmd := exec.Command(
+		"git", "-c", "user.name=test", "-c", "user.email=test@example.org",
+		"commit", "--quiet", "-am", "Rename name",
+	)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	latest, err := git.Version()
+	require.NoError(t, err)
+	require.NotEqual(t, version, latest)
+
+	library, err = source.Namespaced{SchemaSource: git, Namespace: "fork"}.FetchRevision(version)
+	require.NoError(t, err)
+	require.Equal(t, version, library.Version)
+	require.Equal(t, "fork", library.Namespace)
+	require.Equal(t, expectedFields, library.Fields)
+
+	library, err = git.FetchRevision("main")
+	require.NoError(t, err)
+	require.Equal(t, latest, library.Version)
+
+	// Only commits, branches and tags are checked out.
+	for _, revision := range []string{"unknown", "--orphan=main", "main~1"} {
+		_, err = git.FetchRevision(revision)
+		require.Error(t, err, revision)
+	}
+}
+
+func TestFetchRevisionCurrentVersionOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+	local := source.Namespaced{SchemaSource: source.NewLocal(dir), Namespace: "fork"}
+
+	version, err := local.Version()
+	require.NoError(t, err)
+	library, err := local.FetchRevision(version)
+	require.NoError(t, err)
+	require.Equal(t, "fork", library.Namespace)
+	require.Equal(t, expectedSchemas, library.Schemas)
+
+	_, err = local.FetchRevision("0123456789abcdef")
+	require.Error(t, err)
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func tarGz(t *testing.T, prefix string, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name:     prefix,
+		Typeflag: tar.TypeDir,
+		Mode:     0o755,
+	}))
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     prefix + name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func zipFile(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,197 @@
+package source
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// maxArchiveSize is the largest archive a Tarball source downloads.
+const maxArchiveSize = 64 << 20
+
+// Tarball downloads the schemas and fields from an archive served over HTTP,
+// a tarball, optionally gzipped, or a zip file. The schemas, fields and
+// vocabularies directories are either at the root of the archive or in a
+// single top-level directory, as in the archives GitHub and GitLab serve.
+type Tarball struct {
+	url    string
+	client *http.Client
+	// archive caches the archive downloaded by Version for Fetch.
+	archive []byte
+	version string
+}
+
+// NewTarball creates a source downloading the archive at url.
+func NewTarball(url string) *Tarball {
+	return &Tarball{
+		url:    url,
+		client: &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+func (t *Tarball) Name() string {
+	return KindTarball + ":" + t.url
+}
+
+// Version downloads the archive and hashes it.
+func (t *Tarball) Version() (string, error) {
+	archive, err := t.download()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(archive)
+	t.archive = archive
+	t.version = hex.EncodeToString(sum[:])
+	return t.version, nil
+}
+
+func (t *Tarball) Fetch(version string) (*Library, error) {
+	if t.archive == nil || t.version != version {
+		if _, err := t.Version(); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := extract(t.archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s: %w", t.url, err)
+	}
+	return newLibrary(t.version, stripTopLevelDir(files)), nil
+}
+
+func (t *Tarball) download() ([]byte, error) {
+	resp, err := t.client.Get(t.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", t.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"failed to download %s, status code: %d",
+			t.url,
+			resp.StatusCode,
+		)
+	}
+
+	archive, err := io.ReadAll(io.LimitReader(resp.Body, maxArchiveSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", t.url, err)
+	}
+	if len(archive) > maxArchiveSize {
+		return nil, fmt.Errorf(
+			"archive %s is larger than %d bytes",
+			t.url,
+			maxArchiveSize,
+		)
+	}
+	return archive, nil
+}
+
+// extract returns the regular files of the archive, by path.
+func extract(archive []byte) (map[string][]byte, error) {
+	if bytes.HasPrefix(archive, []byte("PK\x03\x04")) {
+		return extractZip(archive)
+	}
+
+	var r io.Reader = bytes.NewReader(archive)
+	if bytes.HasPrefix(archive, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return extractTar(r)
+}
+
+func extractTar(r io.Reader) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	size := 0
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		size += int(header.Size)
+		if size > maxArchiveSize {
+			return nil, fmt.Errorf("content larger than %d bytes", maxArchiveSize)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[cleanPath(header.Name)] = data
+	}
+}
+
+func extractZip(archive []byte) (map[string][]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	size := 0
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		size += int(f.UncompressedSize64)
+		if size > maxArchiveSize {
+			return nil, fmt.Errorf("content larger than %d bytes", maxArchiveSize)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[cleanPath(f.Name)] = data
+	}
+	return files, nil
+}
+
+func cleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// stripTopLevelDir removes the directory all the files are in, unless the
+// schemas, fields or vocabularies directory is at the root.
+func stripTopLevelDir(files map[string][]byte) map[string][]byte {
+	var top string
+	for name := range files {
+		dir, _, ok := strings.Cut(name, "/")
+		if !ok || isLibraryDir(dir) || (top != "" && dir != top) {
+			return files
+		}
+		top = dir
+	}
+
+	stripped := make(map[string][]byte, len(files))
+	for name, data := range files {
+		stripped[strings.TrimPrefix(name, top+"/")] = data
+	}
+	return stripped
+}
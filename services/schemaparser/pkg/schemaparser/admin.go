@@ -0,0 +1,146 @@
+package schemaparser
+
+import (
+	"fmt"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/source"
+)
+
+// Status is the state of the schema updates.
+type Status struct {
+	// LastVersion is the version of the sources the schemas were last
+	// updated from.
+	LastVersion string `json:"last_version"`
+	// PreviousVersion is the version of the sources the schemas were updated
+	// from before, which Rollback returns to.
+	PreviousVersion string `json:"previous_version,omitempty"`
+	// UpdateError is the error stopping the updates, if any.
+	UpdateError *model.UpdateError `json:"update_error,omitempty"`
+}
+
+// Status returns the state of the schema updates.
+func (sc *SchemaCron) Status() (*Status, error) {
+	lastVersion, err := sc.svc.GetLastVersion()
+	if err != nil {
+		return nil, err
+	}
+	previousVersion, err := sc.svc.GetPreviousVersion()
+	if err != nil {
+		return nil, err
+	}
+	updateError, err := sc.svc.GetUpdateError()
+	if err != nil {
+		return nil, err
+	}
+	return &Status{
+		LastVersion:     lastVersion,
+		PreviousVersion: previousVersion,
+		UpdateError:     updateError,
+	}, nil
+}
+
+// ClearError clears the error stopping the updates, so that the next run
+// updates the schemas if the sources changed.
+func (sc *SchemaCron) ClearError() error {
+	return sc.svc.ClearUpdateError()
+}
+
+// Resync updates the schemas from the sources at the version, as given by
+// Status, or at their current version if it is empty, even if the schemas
+// were already updated from it or an error is stopping the updates. The
+// error is cleared once the schemas are updated. Only the github and git
+// sources can be read at past versions.
+func (sc *SchemaCron) Resync(version string) error {
+	return sc.resync(version, sc.svc.SetLastVersion)
+}
+
+// resync updates the schemas from the sources at the version, or at their
+// current version if it is empty, and records the version with setVersion.
+func (sc *SchemaCron) resync(
+	version string,
+	setVersion func(version string) error,
+) error {
+	sources, err := parseSources()
+	if err != nil {
+		return err
+	}
+
+	var versions []string
+	if version != "" {
+		versions, err = splitVersion(sources, version)
+		if err != nil {
+			return err
+		}
+	}
+
+	disconnect, err := sc.connect()
+	if err != nil {
+		return err
+	}
+	defer disconnect()
+
+	libraries := make([]*source.Library, len(sources))
+	for i, src := range sources {
+		if versions == nil {
+			var current string
+			current, err = src.Version()
+			if err != nil {
+				return fmt.Errorf("failed to get the version of %s: %w", src.Name(), err)
+			}
+			libraries[i], err = src.Fetch(current)
+		} else {
+			libraries[i], err = src.FetchRevision(versions[i])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch schemas from %s: %w", src.Name(), err)
+		}
+	}
+
+	version, err = sc.update(sources, libraries, setVersion)
+	if err != nil {
+		return err
+	}
+	if err := sc.svc.ClearUpdateError(); err != nil {
+		return err
+	}
+
+	logger.Info("Resynced the schemas from version " + version)
+	return nil
+}
+
+// Rollback updates the schemas from the sources at the previous version,
+// and holds the updates until the error recording the rollback is cleared,
+// so that the next run doesn't update them from the sources again.
+func (sc *SchemaCron) Rollback() error {
+	lastVersion, err := sc.svc.GetLastVersion()
+	if err != nil {
+		return err
+	}
+	previousVersion, err := sc.svc.GetPreviousVersion()
+	if err != nil {
+		return err
+	}
+	if previousVersion == "" {
+		return fmt.Errorf("no previous version to roll back to")
+	}
+	if previousVersion == lastVersion {
+		return fmt.Errorf("already rolled back to %s", previousVersion)
+	}
+
+	// The previous version stays the one to return to, so rolling back again
+	// doesn't restore the version rolled back from.
+	if err := sc.resync(previousVersion, sc.svc.RestoreLastVersion); err != nil {
+		return fmt.Errorf("failed to roll back to %s: %w", previousVersion, err)
+	}
+
+	return sc.svc.SetUpdateError(&model.UpdateError{
+		Message: fmt.Sprintf(
+			"Rolled back from version %s to %s, clear the error to resume the updates",
+			lastVersion,
+			previousVersion,
+		),
+		Version: lastVersion,
+	})
+}
@@ -2,16 +2,18 @@ package schemaparser
 
 import (
 	"fmt"
-	"io/fs"
 	"os"
-	"path/filepath"
+	"strings"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	mongodb "github.com/MurmurationsNetwork/MurmurationsServices/pkg/mongo"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/natsclient"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/config"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/model"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/repository/mongo"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/service"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/schemaparser/internal/source"
 )
 
 // SchemaCron represents a cron job for managing schema updates.
@@ -30,10 +32,21 @@ func NewCronJob() *SchemaCron {
 		os.Exit(1)
 	}
 
+	validationRedisClient := redis.NewClient(config.Values.ValidationRedis.URL)
+	err = validationRedisClient.Ping()
+	if err != nil {
+		logger.Error("error when trying to ping the validation Redis", err)
+		os.Exit(1)
+	}
+
 	return &SchemaCron{
 		svc: service.NewSchemaService(
 			mongo.NewSchemaRepository(),
+			mongo.NewFieldRepository(),
+			mongo.NewVocabularyRepository(),
+			mongo.NewSchemaRevisionRepository(),
 			redisClient,
+			validationRedisClient,
 		),
 	}
 }
@@ -41,80 +54,198 @@ func NewCronJob() *SchemaCron {
 // Run start loading the schema.
 func (sc *SchemaCron) Run() error {
 	// Check for update schema error
-	errorMessage, err := sc.svc.GetUpdateError()
+	updateError, err := sc.svc.GetUpdateError()
 	if err != nil {
 		logger.Error("Failed to retrieve update error from Redis", err)
 		return err
 	}
-	if errorMessage != "" {
-		return fmt.Errorf("Existing error detected, waiting for manual resolution: " + errorMessage)
+	if updateError != nil {
+		return fmt.Errorf(
+			"Existing error detected, waiting for manual resolution: %s",
+			describeUpdateError(updateError),
+		)
 	}
 
-	if err := sc.connectToMongoDB(); err != nil {
-		return fmt.Errorf("failed to connect to MongoDB: %w", err)
+	disconnect, err := sc.connect()
+	if err != nil {
+		return err
 	}
+	defer disconnect()
 
-	if config.Values.IsLocal {
-		schemaData, err := readSchemaFilesFromDir("library/schemas")
+	sources, err := parseSources()
+	if err != nil {
+		return err
+	}
+
+	versions := make([]string, len(sources))
+	for i, src := range sources {
+		versions[i], err = src.Version()
 		if err != nil {
-			return fmt.Errorf("failed to read schema files from dir: %w", err)
+			return fmt.Errorf("failed to get the version of %s: %w", src.Name(), err)
 		}
-		fieldData, err := readSchemaFilesFromDir("library/fields")
+	}
+	version := combinedVersion(sources, versions)
+
+	hasNewVersion, err := sc.svc.HasNewVersion(version)
+	if err != nil {
+		return fmt.Errorf("failed to get schemas:lastVersion: %w", err)
+	}
+	if !hasNewVersion {
+		logger.Info("No new version found. Latest version of the sources: " + version)
+		return nil
+	}
+
+	libraries := make([]*source.Library, len(sources))
+	for i, src := range sources {
+		libraries[i], err = src.Fetch(versions[i])
 		if err != nil {
-			return fmt.Errorf("failed to read field files from dir: %w", err)
+			return fmt.Errorf("failed to fetch schemas from %s: %w", src.Name(), err)
 		}
+	}
 
-		// if schema data is not empty, update the schemas
-		if len(schemaData) > 0 {
-			err = sc.svc.UpdateLocalSchemas(schemaData, fieldData)
-			if err != nil {
-				return fmt.Errorf("failed to update local schemas: %w", err)
-			}
-		}
+	_, err = sc.update(sources, libraries, sc.svc.SetLastVersion)
+	return err
+}
+
+// update updates the schemas from the libraries read from the sources and
+// returns the version of the sources they were read at, which it records
+// with setVersion.
+func (sc *SchemaCron) update(
+	sources []source.Namespaced,
+	libraries []*source.Library,
+	setVersion func(version string) error,
+) (string, error) {
+	// A source may have moved on since its version was read.
+	versions := make([]string, len(libraries))
+	for i, library := range libraries {
+		versions[i] = library.Version
 	}
+	version := combinedVersion(sources, versions)
 
-	branchInfo, err := sc.svc.GetBranchInfo(config.Values.Github.BranchURL)
+	changed, err := sc.svc.UpdateSchemas(libraries, version)
 	if err != nil {
-		return fmt.Errorf(
-			"failed to get last_commit and schema_list from %s: %w",
-			config.Values.Github.BranchURL,
-			err,
-		)
+		if updateError, _ := sc.svc.GetUpdateError(); updateError != nil {
+			logger.Info("Schema update failed: " + describeUpdateError(updateError))
+		}
+		return "", fmt.Errorf("failed to update schemas: %w", err)
 	}
 
-	hasNewCommit, err := sc.svc.HasNewCommit(
-		branchInfo.Commit.InnerCommit.Author.Date,
-	)
+	err = sc.svc.PublishSchemasVersion(version)
 	if err != nil {
-		return fmt.Errorf(
-			"failed to get schemas:lastCommit: %w",
-			err,
-		)
+		return "", fmt.Errorf("failed to publish schemas version: %w", err)
 	}
 
-	if !hasNewCommit {
-		logger.Info(
-			"No new commit found. Latest commit on GitHub: " +
-				branchInfo.Commit.InnerCommit.Author.Date,
-		)
-		return nil
+	// The nodes linked to the changed schemas are revalidated against them,
+	// which is why the validation service is told about them first.
+	err = sc.svc.PublishSchemasUpdated(changed, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to publish updated schemas: %w", err)
 	}
 
-	err = sc.svc.UpdateSchemas(branchInfo.Commit.Sha)
+	// After successfully updating the schemas, record the version.
+	err = setVersion(version)
 	if err != nil {
-		return fmt.Errorf("failed to update schemas: %w", err)
+		return "", fmt.Errorf("failed to set schemas:lastVersion: %w", err)
 	}
 
-	// After successfully updating the schemas, update the last commit date.
-	err = sc.svc.SetLastCommit(branchInfo.Commit.InnerCommit.Author.Date)
+	return version, nil
+}
+
+// parseSources creates the sources the deployment is configured with.
+func parseSources() ([]source.Namespaced, error) {
+	sources, err := source.Parse(
+		config.Values.Sources,
+		source.Options{GitHubToken: config.Values.Github.TOKEN},
+	)
 	if err != nil {
-		return fmt.Errorf(
-			"failed to set schemas:lastCommit: %w",
-			err,
+		return nil, fmt.Errorf("failed to configure schema sources: %w", err)
+	}
+	return sources, nil
+}
+
+// connect connects to MongoDB and NATS, and returns a function disconnecting
+// from NATS.
+func (sc *SchemaCron) connect() (func(), error) {
+	if err := sc.connectToMongoDB(); err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	if err := natsclient.Initialize(config.Values.Nats.URL); err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	return func() {
+		if err := natsclient.GetInstance().Disconnect(); err != nil {
+			logger.Error("Error disconnecting from NATS", err)
+		}
+	}, nil
+}
+
+// combinedVersion identifies the versions of all the sources. A single
+// source without a namespace is identified by its own version, e.g. the
+// commit of the library.
+func combinedVersion(sources []source.Namespaced, versions []string) string {
+	if len(sources) == 1 && sources[0].Namespace == "" {
+		return versions[0]
+	}
+	parts := make([]string, len(sources))
+	for i, src := range sources {
+		parts[i] = src.Namespace + "=" + versions[i]
+	}
+	return strings.Join(parts, ",")
+}
+
+// splitVersion splits the version of all the sources, as given by
+// combinedVersion, into the versions of each source.
+func splitVersion(sources []source.Namespaced, version string) ([]string, error) {
+	if len(sources) == 1 && sources[0].Namespace == "" {
+		return []string{version}, nil
+	}
+
+	parts := strings.Split(version, ",")
+	if len(parts) != len(sources) {
+		return nil, fmt.Errorf(
+			"invalid version %q, expected the versions of the %d sources as <namespace>=<version>,...",
+			version,
+			len(sources),
 		)
 	}
+	versions := make([]string, len(sources))
+	for i, part := range parts {
+		namespace, v, ok := strings.Cut(part, "=")
+		if !ok || namespace != sources[i].Namespace || v == "" {
+			return nil, fmt.Errorf(
+				"invalid version %q, expected %s=<version> for %s",
+				part,
+				sources[i].Namespace,
+				sources[i].Name(),
+			)
+		}
+		versions[i] = v
+	}
+	return versions, nil
+}
 
-	return nil
+// describeUpdateError formats the update error along with its lint issues.
+func describeUpdateError(updateError *model.UpdateError) string {
+	var sb strings.Builder
+	sb.WriteString(updateError.Message)
+	if updateError.Schema != "" {
+		sb.WriteString(" (schema: " + updateError.Schema + ")")
+	}
+	if updateError.Version != "" {
+		sb.WriteString(" (version: " + updateError.Version + ")")
+	}
+	for _, issue := range updateError.Issues {
+		fmt.Fprintf(
+			&sb,
+			"\n- %s%s [%s]: %s",
+			issue.File,
+			issue.Pointer,
+			issue.Rule,
+			issue.Message,
+		)
+	}
+	return sb.String()
 }
 
 // connectToMongoDB establishes a connection to MongoDB.
@@ -134,37 +265,3 @@ func (sc *SchemaCron) connectToMongoDB() error {
 	}
 	return nil
 }
-
-// get Schemas from local folder.
-func readSchemaFilesFromDir(dirPath string) (map[string][]byte, error) {
-	filesData := make(
-		map[string][]byte,
-	) // Initialize a map to store filename and its content
-
-	err := filepath.WalkDir(
-		dirPath,
-		func(path string, d fs.DirEntry, err error) error {
-			if err != nil {
-				return err
-			}
-			if !d.IsDir() && filepath.Ext(path) == ".json" {
-				data, err := os.ReadFile(path)
-				if err != nil {
-					return err
-				}
-				relPath, err := filepath.Rel(dirPath, path)
-				if err != nil {
-					return err
-				}
-				filesData[relPath] = data
-			}
-			return nil
-		},
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return filesData, nil
-}
@@ -9,10 +9,13 @@ var Values = Config{}
 
 // Config represents the application configuration structure.
 type Config struct {
-	Server  ServerConfig
-	Library LibraryConfig
-	NATS    NATSConfig
-	Redis   redisConf
+	Server    ServerConfig
+	Library   LibraryConfig
+	NATS      NATSConfig
+	Redis     redisConf
+	Fetch     FetchConfig
+	Scheduler SchedulerConfig
+	Cache     CacheConfig
 }
 
 // ServerConfig holds the server related configuration.
@@ -43,6 +46,43 @@ type NATSConfig struct {
 	URL string `env:"NATS_URL,required"`
 }
 
+// FetchConfig holds the configuration for fetching profiles.
+type FetchConfig struct {
+	// Maximum size of a profile in bytes
+	MaxBodySize int64 `env:"FETCH_MAX_BODY_SIZE,required"`
+	// Maximum nesting depth of a profile
+	MaxJSONDepth int `env:"FETCH_MAX_JSON_DEPTH,required"`
+	// Hosts that may be fetched even though they resolve to private addresses
+	AllowedHosts []string `env:"FETCH_ALLOWED_HOSTS,required" envSeparator:","`
+	// How long the validators and result of an unchanged profile are kept
+	CacheTTL time.Duration `env:"FETCH_CACHE_TTL,required"`
+}
+
+// SchedulerConfig holds the configuration for scheduling validations across
+// profile hosts.
+type SchedulerConfig struct {
+	// Maximum number of profiles validated at once
+	Workers int `env:"SCHEDULER_WORKERS,required"`
+	// Maximum number of profiles of the same host validated at once
+	HostConcurrency int `env:"SCHEDULER_HOST_CONCURRENCY,required"`
+	// Minimum time between two requests to the same host
+	HostInterval time.Duration `env:"SCHEDULER_HOST_INTERVAL,required"`
+	// Maximum number of queued profiles per host
+	HostQueueSize int `env:"SCHEDULER_HOST_QUEUE_SIZE,required"`
+	// Delay before retrying when the host doesn't say, or its queue is full
+	RetryDelay time.Duration `env:"SCHEDULER_RETRY_DELAY,required"`
+	// Maximum delay before retrying a rate limited profile
+	MaxRetryDelay time.Duration `env:"SCHEDULER_MAX_RETRY_DELAY,required"`
+	// Number of rate limited attempts after which validation fails
+	MaxRetries int `env:"SCHEDULER_MAX_RETRIES,required"`
+}
+
+// CacheConfig holds the configuration for caching validation results.
+type CacheConfig struct {
+	// How long the result of validating a profile body is kept
+	ResultTTL time.Duration `env:"CACHE_RESULT_TTL,required"`
+}
+
 type redisConf struct {
 	URL string `env:"REDIS_URL,required"`
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/nats-io/nats.go"
@@ -11,7 +12,10 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/urlutil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/model"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/scheduler"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/service"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/validation"
 )
@@ -24,16 +28,20 @@ type NodeHandler interface {
 type nodeHandler struct {
 	redis             redis.Redis
 	validationService service.ValidationService
+	scheduler         *scheduler.Scheduler
 }
 
 // NewNodeHandler creates a new NodeHandler with the provided validation service.
+// Validations are run by the scheduler, which must be started separately.
 func NewNodeHandler(
 	redis redis.Redis,
 	validationService service.ValidationService,
+	scheduler *scheduler.Scheduler,
 ) NodeHandler {
 	return &nodeHandler{
 		redis:             redis,
 		validationService: validationService,
+		scheduler:         scheduler,
 	}
 }
 
@@ -46,8 +54,50 @@ func (handler *nodeHandler) NewNodeCreatedListener() error {
 	)
 }
 
-// newNodeCreatedHandler handles the logic for node-created messages.
+// newNodeCreatedHandler queues node-created messages for validation. The
+// message is acknowledged once the node is validated.
 func (handler *nodeHandler) newNodeCreatedHandler(msg *nats.Msg) {
+	var nodeCreatedData messaging.NodeCreatedData
+	if err := json.Unmarshal(msg.Data, &nodeCreatedData); err != nil {
+		logger.Error("Error when trying to parse nodeCreatedData", err)
+		acknowledge(msg)
+		return
+	}
+
+	node := &model.Node{
+		ProfileURL: nodeCreatedData.ProfileURL,
+		Version:    nodeCreatedData.Version,
+	}
+
+	job := &scheduler.Job{
+		Host: profileHost(node.ProfileURL),
+		Run: func() time.Duration {
+			return handler.validate(msg, node)
+		},
+		KeepAlive: func() {
+			// Keep the message from being redelivered while it waits.
+			if err := msg.InProgress(); err != nil {
+				logger.Error("Error when extending message ack deadline", err)
+			}
+		},
+	}
+	if !handler.scheduler.Submit(job) {
+		// The host has too many profiles waiting; let the message come back
+		// later so other hosts get their turn.
+		if err := msg.NakWithDelay(config.Values.Scheduler.RetryDelay); err != nil {
+			logger.Error("Error when requeueing message", err)
+		}
+	}
+}
+
+// validate validates the node and acknowledges the message, or requeues it if
+// the profile host is rate limiting requests. It returns how long the host
+// should be left alone.
+func (handler *nodeHandler) validate(
+	msg *nats.Msg,
+	node *model.Node,
+) (backoff time.Duration) {
+	requeued := false
 	defer func() {
 		if err := recover(); err != nil {
 			logger.Error(
@@ -55,43 +105,76 @@ func (handler *nodeHandler) newNodeCreatedHandler(msg *nats.Msg) {
 				errors.New("panic"),
 			)
 		}
-	}()
-
-	defer func() {
 		// Acknowledge the message regardless of error.
-		if err := msg.Ack(); err != nil {
-			logger.Error("Error when acknowledging message", err)
+		if !requeued {
+			acknowledge(msg)
 		}
 	}()
 
-	var nodeCreatedData messaging.NodeCreatedData
-	if err := json.Unmarshal(msg.Data, &nodeCreatedData); err != nil {
-		logger.Error("Error when trying to parse nodeCreatedData", err)
-		return
-	}
-
-	nodeKey := fmt.Sprintf(
-		"%s:%d",
-		nodeCreatedData.ProfileURL,
-		nodeCreatedData.Version,
-	)
+	nodeKey := fmt.Sprintf("%s:%d", node.ProfileURL, node.Version)
 	exists, err := handler.redis.Get(nodeKey)
 	if err != nil {
 		logger.Error("Error getting key from Redis", err)
-		return
+		return 0
+	}
+	if exists != "" {
+		logger.Info(fmt.Sprintf("Duplicate node created event: %s", nodeKey))
+		return 0
 	}
 
-	if exists == "" {
-		handler.validationService.ValidateNode(&model.Node{
-			ProfileURL: nodeCreatedData.ProfileURL,
-			Version:    nodeCreatedData.Version,
-		})
-		err := handler.redis.Set(nodeKey, "processed", 10*time.Second)
-		if err != nil {
-			logger.Error("Error setting key in Redis", err)
+	err = handler.validationService.ValidateNode(node)
+
+	var retryErr service.RetryLaterError
+	if errors.As(err, &retryErr) {
+		if attempts(msg) <= config.Values.Scheduler.MaxRetries {
+			logger.Info(fmt.Sprintf(
+				"Profile host is rate limiting, retrying %s in %s",
+				node.ProfileURL,
+				retryErr.After,
+			))
+			if err := msg.NakWithDelay(retryErr.After); err != nil {
+				logger.Error("Error when requeueing message", err)
+			} else {
+				requeued = true
+			}
+			return retryErr.After
 		}
-		logger.Info(fmt.Sprintf("Successfully processed profile with URL: %s", nodeCreatedData.ProfileURL))
-	} else {
-		logger.Info(fmt.Sprintf("Duplicate node created event: %s", nodeKey))
+		handler.validationService.SetRateLimited(node)
+		backoff = retryErr.After
+	}
+
+	err = handler.redis.Set(nodeKey, "processed", 10*time.Second)
+	if err != nil {
+		logger.Error("Error setting key in Redis", err)
+	}
+	logger.Info(fmt.Sprintf("Successfully processed profile with URL: %s", node.ProfileURL))
+	return backoff
+}
+
+// attempts returns how many times the message has been delivered.
+func attempts(msg *nats.Msg) int {
+	meta, err := msg.Metadata()
+	if err != nil {
+		return 1
+	}
+	return int(meta.NumDelivered)
+}
+
+// profileHost returns the host the profile URL is fetched from.
+func profileHost(profileURL string) string {
+	canonicalURL, err := urlutil.Canonicalize(profileURL)
+	if err != nil {
+		return ""
+	}
+	u, err := url.Parse(canonicalURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+func acknowledge(msg *nats.Msg) {
+	if err := msg.Ack(); err != nil {
+		logger.Error("Error when acknowledging message", err)
 	}
 }
@@ -0,0 +1,290 @@
+// Package scheduler runs jobs that contact remote hosts, limiting how many
+// jobs of the same host run at once and how often they start, and taking
+// turns between hosts so that a host with many jobs doesn't starve others.
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// Options configures a Scheduler.
+type Options struct {
+	// Workers is the maximum number of jobs running at once.
+	Workers int
+	// HostConcurrency is the maximum number of jobs of the same host running
+	// at once.
+	HostConcurrency int
+	// HostInterval is the minimum time between the starts of two jobs of the
+	// same host.
+	HostInterval time.Duration
+	// HostQueueSize is the maximum number of queued jobs per host.
+	HostQueueSize int
+	// KeepAliveInterval is how often the KeepAlive function of queued jobs is
+	// called.
+	KeepAliveInterval time.Duration
+}
+
+// Job is a unit of work that contacts a host.
+type Job struct {
+	// Host is the host the job contacts.
+	Host string
+	// Run does the work. It returns how long the host asked to be left alone,
+	// or 0.
+	Run func() time.Duration
+	// KeepAlive, if set, is called periodically while the job is queued and
+	// right before it runs.
+	KeepAlive func()
+}
+
+// hostQueue holds the state of a host.
+type hostQueue struct {
+	jobs      []*Job
+	running   int
+	nextStart time.Time
+}
+
+// Scheduler runs jobs fairly across hosts.
+type Scheduler struct {
+	opts Options
+
+	mu    sync.Mutex
+	hosts map[string]*hostQueue
+	// order lists the hosts in the order they take turns.
+	order []string
+	// next is the position in order of the host whose turn it is.
+	next int
+
+	idle    chan struct{}
+	wake    chan struct{}
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	running sync.WaitGroup
+	stopped sync.Once
+	now     func() time.Time
+}
+
+// New creates a new Scheduler. Call Start to start running jobs.
+func New(opts Options) *Scheduler {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.HostConcurrency <= 0 {
+		opts.HostConcurrency = 1
+	}
+	if opts.KeepAliveInterval <= 0 {
+		opts.KeepAliveInterval = 10 * time.Second
+	}
+
+	s := &Scheduler{
+		opts:  opts,
+		hosts: make(map[string]*hostQueue),
+		idle:  make(chan struct{}, opts.Workers),
+		wake:  make(chan struct{}, 1),
+		stop:  make(chan struct{}),
+		now:   time.Now,
+	}
+	for i := 0; i < opts.Workers; i++ {
+		s.idle <- struct{}{}
+	}
+	return s
+}
+
+// Start starts running submitted jobs.
+func (s *Scheduler) Start() {
+	s.wg.Add(2)
+	go s.dispatch()
+	go s.keepAlive()
+}
+
+// Stop stops running jobs and waits for the running ones to finish. Queued
+// jobs are dropped.
+func (s *Scheduler) Stop() {
+	s.stopped.Do(func() {
+		close(s.stop)
+		s.wg.Wait()
+		s.running.Wait()
+	})
+}
+
+// Submit queues a job. It returns false if the queue of the job's host is
+// full.
+func (s *Scheduler) Submit(job *Job) bool {
+	s.mu.Lock()
+	h, ok := s.hosts[job.Host]
+	if !ok {
+		h = &hostQueue{}
+		s.hosts[job.Host] = h
+		s.order = append(s.order, job.Host)
+	}
+	if s.opts.HostQueueSize > 0 && len(h.jobs) >= s.opts.HostQueueSize {
+		s.mu.Unlock()
+		return false
+	}
+	h.jobs = append(h.jobs, job)
+	s.mu.Unlock()
+
+	s.signal()
+	return true
+}
+
+// dispatch starts jobs whenever a worker is idle.
+func (s *Scheduler) dispatch() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.idle:
+		case <-s.stop:
+			return
+		}
+
+		for {
+			job, host, wait := s.nextJob()
+			if job != nil {
+				s.running.Add(1)
+				go s.run(job, host)
+				break
+			}
+			if !s.sleep(wait) {
+				return
+			}
+		}
+	}
+}
+
+// nextJob takes the next job that may start, going through the hosts in
+// turn. If no job may start, it returns how long to wait until one may, or 0
+// if there are no jobs to wait for.
+func (s *Scheduler) nextJob() (*Job, *hostQueue, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var wait time.Duration
+
+	for i := 0; i < len(s.order); i++ {
+		pos := (s.next + i) % len(s.order)
+		h := s.hosts[s.order[pos]]
+		if len(h.jobs) == 0 || h.running >= s.opts.HostConcurrency {
+			continue
+		}
+		if h.nextStart.After(now) {
+			if d := h.nextStart.Sub(now); wait == 0 || d < wait {
+				wait = d
+			}
+			continue
+		}
+
+		job := h.jobs[0]
+		h.jobs[0] = nil
+		h.jobs = h.jobs[1:]
+		h.running++
+		h.nextStart = now.Add(s.opts.HostInterval)
+		s.next = (pos + 1) % len(s.order)
+		return job, h, 0
+	}
+
+	return nil, nil, wait
+}
+
+// run runs the job and releases its worker.
+func (s *Scheduler) run(job *Job, h *hostQueue) {
+	defer s.running.Done()
+
+	if job.KeepAlive != nil {
+		job.KeepAlive()
+	}
+	backoff := job.Run()
+
+	s.mu.Lock()
+	h.running--
+	if backoff > 0 {
+		if until := s.now().Add(backoff); until.After(h.nextStart) {
+			h.nextStart = until
+		}
+	}
+	s.removeIdleHosts()
+	s.mu.Unlock()
+
+	s.idle <- struct{}{}
+	s.signal()
+}
+
+// removeIdleHosts forgets hosts that have no jobs and no backoff. It must be
+// called with s.mu held.
+func (s *Scheduler) removeIdleHosts() {
+	now := s.now()
+	order := s.order[:0]
+	for pos, host := range s.order {
+		h := s.hosts[host]
+		if len(h.jobs) == 0 && h.running == 0 && !h.nextStart.After(now) {
+			delete(s.hosts, host)
+			if pos < s.next {
+				s.next--
+			}
+			continue
+		}
+		order = append(order, host)
+	}
+	s.order = order
+	if s.next >= len(s.order) {
+		s.next = 0
+	}
+}
+
+// sleep waits until a job is submitted or finishes, or for d if it isn't 0.
+// It returns false if the scheduler is stopped.
+func (s *Scheduler) sleep(d time.Duration) bool {
+	var timeout <-chan time.Time
+	if d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-s.wake:
+	case <-timeout:
+	case <-s.stop:
+		return false
+	}
+	return true
+}
+
+// signal wakes up the dispatcher.
+func (s *Scheduler) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// keepAlive periodically calls the KeepAlive function of queued jobs.
+func (s *Scheduler) keepAlive() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.opts.KeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.stop:
+			return
+		}
+
+		s.mu.Lock()
+		var queued []*Job
+		for _, h := range s.hosts {
+			queued = append(queued, h.jobs...)
+		}
+		s.mu.Unlock()
+
+		for _, job := range queued {
+			if job.KeepAlive != nil {
+				job.KeepAlive()
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerTakesTurnsBetweenHosts(t *testing.T) {
+	s := New(Options{Workers: 1, HostConcurrency: 1})
+
+	var (
+		mu    sync.Mutex
+		order []string
+		done  sync.WaitGroup
+	)
+	submit := func(host string) {
+		done.Add(1)
+		require.True(t, s.Submit(&Job{
+			Host: host,
+			Run: func() time.Duration {
+				mu.Lock()
+				order = append(order, host)
+				mu.Unlock()
+				done.Done()
+				return 0
+			},
+		}))
+	}
+
+	// Queue the jobs before starting, so that they are all pending.
+	for i := 0; i < 3; i++ {
+		submit("a.org")
+	}
+	submit("b.org")
+	submit("c.org")
+
+	s.Start()
+	defer s.Stop()
+	done.Wait()
+
+	require.Equal(t, []string{"a.org", "b.org", "c.org", "a.org", "a.org"}, order)
+}
+
+func TestSchedulerHostConcurrency(t *testing.T) {
+	s := New(Options{Workers: 4, HostConcurrency: 2})
+	s.Start()
+	defer s.Stop()
+
+	var (
+		mu      sync.Mutex
+		running int
+		maxSeen int
+		done    sync.WaitGroup
+	)
+	for i := 0; i < 6; i++ {
+		done.Add(1)
+		s.Submit(&Job{
+			Host: "a.org",
+			Run: func() time.Duration {
+				mu.Lock()
+				running++
+				if running > maxSeen {
+					maxSeen = running
+				}
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+				done.Done()
+				return 0
+			},
+		})
+	}
+	done.Wait()
+
+	require.Equal(t, 2, maxSeen)
+}
+
+func TestSchedulerHostIntervalAndBackoff(t *testing.T) {
+	s := New(Options{
+		Workers:         2,
+		HostConcurrency: 2,
+		HostInterval:    20 * time.Millisecond,
+	})
+	s.Start()
+	defer s.Stop()
+
+	var (
+		mu     sync.Mutex
+		starts []time.Time
+		done   sync.WaitGroup
+	)
+	for i := 0; i < 3; i++ {
+		backoff := time.Duration(0)
+		if i == 0 {
+			backoff = 50 * time.Millisecond
+		}
+		done.Add(1)
+		s.Submit(&Job{
+			Host: "a.org",
+			Run: func() time.Duration {
+				mu.Lock()
+				starts = append(starts, time.Now())
+				mu.Unlock()
+				done.Done()
+				return backoff
+			},
+		})
+	}
+	done.Wait()
+
+	require.Len(t, starts, 3)
+	require.GreaterOrEqual(t, starts[1].Sub(starts[0]), 20*time.Millisecond)
+	// The first job asked the host to be left alone for longer.
+	require.GreaterOrEqual(t, starts[2].Sub(starts[0]), 50*time.Millisecond)
+}
+
+func TestSchedulerHostQueueSize(t *testing.T) {
+	s := New(Options{Workers: 1, HostQueueSize: 2})
+
+	job := &Job{Host: "a.org", Run: func() time.Duration { return 0 }}
+	require.True(t, s.Submit(job))
+	require.True(t, s.Submit(job))
+	require.False(t, s.Submit(job))
+	require.True(t, s.Submit(&Job{Host: "b.org", Run: job.Run}))
+}
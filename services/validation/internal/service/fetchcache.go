@@ -0,0 +1,92 @@
+package service
+
+import (
+	"encoding/json"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/config"
+)
+
+// fetchCacheKeyPrefix prefixes the Redis keys of cached profile fetches.
+const fetchCacheKeyPrefix = "fetch:"
+
+// fetchCacheEntry holds the cache validators of the last successfully
+// validated response of a profile URL, along with the result of validating
+// it, so that an unchanged profile doesn't need to be validated again.
+type fetchCacheEntry struct {
+	ETag           string                      `json:"etag,omitempty"`
+	LastModified   string                      `json:"last_modified,omitempty"`
+	SchemasVersion string                      `json:"schemas_version,omitempty"`
+	Validated      messaging.NodeValidatedData `json:"validated"`
+}
+
+// hasResult reports whether the entry holds a validation result that can be
+// published again when the profile is unchanged.
+func (e *fetchCacheEntry) hasResult() bool {
+	return e.Validated.ProfileStr != "" && e.Validated.Version != 0
+}
+
+// getFetchCache returns the cached fetch of the profile URL, or an empty
+// entry if there is none.
+func (svc *validationService) getFetchCache(profileURL string) *fetchCacheEntry {
+	entry := &fetchCacheEntry{}
+
+	value, err := svc.redis.Get(fetchCacheKeyPrefix + profileURL)
+	if err != nil {
+		logger.Error("Error getting cached fetch from Redis", err)
+		return entry
+	}
+	if value == "" {
+		return entry
+	}
+
+	if err := json.Unmarshal([]byte(value), entry); err != nil {
+		logger.Error("Error decoding cached fetch", err)
+		return &fetchCacheEntry{}
+	}
+	return entry
+}
+
+// setFetchCache caches the validation result of a profile if its response
+// had validators to make conditional requests with.
+func (svc *validationService) setFetchCache(
+	profileURL string,
+	schemasVersion string,
+	info *httputil.FetchInfo,
+	validated messaging.NodeValidatedData,
+) {
+	if info.ETag == "" && info.LastModified == "" {
+		svc.clearFetchCache(profileURL)
+		return
+	}
+
+	value, err := json.Marshal(fetchCacheEntry{
+		ETag:           info.ETag,
+		LastModified:   info.LastModified,
+		SchemasVersion: schemasVersion,
+		Validated:      validated,
+	})
+	if err != nil {
+		logger.Error("Error encoding fetch to cache", err)
+		return
+	}
+
+	err = svc.redis.Set(
+		fetchCacheKeyPrefix+profileURL,
+		string(value),
+		config.Values.Fetch.CacheTTL,
+	)
+	if err != nil {
+		logger.Error("Error setting cached fetch in Redis", err)
+	}
+}
+
+// clearFetchCache removes the cached fetch of the profile URL, so that it is
+// fetched and validated in full next time.
+func (svc *validationService) clearFetchCache(profileURL string) {
+	if err := svc.redis.Del(fetchCacheKeyPrefix + profileURL); err != nil {
+		logger.Error("Error deleting cached fetch from Redis", err)
+	}
+}
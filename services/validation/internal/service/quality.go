@@ -0,0 +1,54 @@
+package service
+
+import (
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/quality"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/urlutil"
+)
+
+// qualityFactors returns the quality criteria of a valid profile. The index
+// adds when the profile last changed before scoring it.
+func (svc *validationService) qualityFactors(
+	profileJSON map[string]interface{},
+	result *profileResult,
+) quality.Factors {
+	factors := quality.Factors{
+		Recommended:        result.Recommended,
+		MissingRecommended: result.MissingRecommended,
+		Geolocation:        hasGeolocation(profileJSON),
+		Tags:               countTags(profileJSON),
+	}
+
+	if primaryURL, ok := profileJSON["primary_url"].(string); ok {
+		_, err := svc.fetcher.CheckURL(urlutil.LinkURL(primaryURL))
+		if err != nil {
+			logger.Info("Primary URL is not reachable: " + err.Error())
+		}
+		factors.PrimaryURLReachable = err == nil
+	}
+
+	return factors
+}
+
+// hasGeolocation reports whether the profile has a geolocation, either as
+// "geolocation" or as "latitude" and "longitude".
+func hasGeolocation(profileJSON map[string]interface{}) bool {
+	if profileJSON["geolocation"] != nil {
+		return true
+	}
+	_, hasLat := profileJSON["latitude"].(float64)
+	_, hasLon := profileJSON["longitude"].(float64)
+	return hasLat && hasLon
+}
+
+// countTags returns the number of string tags of the profile.
+func countTags(profileJSON map[string]interface{}) int {
+	tags, _ := profileJSON["tags"].([]interface{})
+	count := 0
+	for _, tag := range tags {
+		if _, ok := tag.(string); ok {
+			count++
+		}
+	}
+	return count
+}
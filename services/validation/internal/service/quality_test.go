@@ -0,0 +1,42 @@
+package service
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
+)
+
+func TestQualityFactorsPrimaryURLWithoutScheme(t *testing.T) {
+	// The test server's certificate isn't trusted, so the check fails, but
+	// the connection shows the primary URL was requested over HTTPS.
+	var connected atomic.Bool
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {},
+	))
+	ts.Config.ConnState = func(net.Conn, http.ConnState) {
+		connected.Store(true)
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	svc := &validationService{
+		fetcher: httputil.NewFetcher(httputil.FetcherOptions{
+			AllowedHosts: httputil.Hostnames(ts.URL),
+		}),
+	}
+	primaryURL := strings.TrimPrefix(ts.URL, "https://") + "/about"
+
+	factors := svc.qualityFactors(
+		map[string]interface{}{"primary_url": primaryURL},
+		&profileResult{},
+	)
+	require.True(t, connected.Load())
+	require.False(t, factors.PrimaryURLReachable)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/jsonapi"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/logger"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/messaging"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilevalidator"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/config"
+)
+
+// resultCacheKeyPrefix prefixes the Redis keys of cached validation results.
+const resultCacheKeyPrefix = "result:"
+
+// profileResult is the outcome of validating a profile body against its
+// schemas. It only depends on the body and the schemas, so it can be reused
+// for identical bodies until the schemas change.
+type profileResult struct {
+	// Errors lists why the profile is invalid, if it is.
+	Errors []jsonapi.Error `json:"errors,omitempty"`
+	// ProfileHash is the hash of a valid profile.
+	ProfileHash string `json:"profile_hash,omitempty"`
+	// Warnings lists the issues that don't make the profile invalid.
+	Warnings []jsonapi.Error `json:"warnings,omitempty"`
+	// Recommended is the number of properties the schemas recommend.
+	Recommended int `json:"recommended,omitempty"`
+	// MissingRecommended is the number of recommended properties missing.
+	MissingRecommended int `json:"missing_recommended,omitempty"`
+	// SchemaRevisions lists the revisions of the schemas the profile was
+	// validated against.
+	SchemaRevisions []messaging.SchemaRevision `json:"schema_revisions,omitempty"`
+}
+
+// addWarnings adds the warnings, recommended properties and schema revisions
+// of a validation result.
+func (r *profileResult) addWarnings(vr *profilevalidator.ValidationResult) {
+	r.Warnings = append(r.Warnings, vr.Warnings()...)
+	r.Recommended += vr.RecommendedProperties
+	r.MissingRecommended += vr.MissingRecommended()
+	for _, revision := range vr.SchemaRevisions {
+		r.SchemaRevisions = append(r.SchemaRevisions, messaging.SchemaRevision{
+			Name:     revision.Name,
+			Revision: revision.Revision,
+		})
+	}
+}
+
+// schemasVersion returns the version of the schemas last published by the
+// schemaparser. When it changes, the compiled schemas are dropped so that
+// the new ones are fetched.
+func (svc *validationService) schemasVersion() string {
+	version, err := svc.redis.Get(redis.SchemasVersionKey)
+	if err != nil {
+		logger.Error("Error getting schemas version from Redis", err)
+	}
+
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if err != nil {
+		return svc.lastSchemasVersion
+	}
+	if version != svc.lastSchemasVersion {
+		logger.Info("Schemas changed, dropping compiled schemas: " + version)
+		svc.schemas.Reset()
+		svc.lastSchemasVersion = version
+	}
+	return version
+}
+
+// resultCacheKey returns the Redis key of the result of validating the
+// profile body against the given version of the schemas.
+func resultCacheKey(schemasVersion, profileStr string) string {
+	sum := sha256.Sum256([]byte(profileStr))
+	return resultCacheKeyPrefix + schemasVersion + ":" + hex.EncodeToString(sum[:])
+}
+
+// getResultCache returns the cached result of validating the profile body,
+// or nil if there is none.
+func (svc *validationService) getResultCache(
+	schemasVersion, profileStr string,
+) *profileResult {
+	value, err := svc.redis.Get(resultCacheKey(schemasVersion, profileStr))
+	if err != nil {
+		logger.Error("Error getting cached result from Redis", err)
+		return nil
+	}
+	if value == "" {
+		return nil
+	}
+
+	result := &profileResult{}
+	if err := json.Unmarshal([]byte(value), result); err != nil {
+		logger.Error("Error decoding cached result", err)
+		return nil
+	}
+	return result
+}
+
+// setResultCache caches the result of validating the profile body. Results
+// with errors other than the profile's own, e.g. a schema that couldn't be
+// loaded, aren't cached.
+func (svc *validationService) setResultCache(
+	schemasVersion, profileStr string,
+	result *profileResult,
+) {
+	for _, e := range result.Errors {
+		if e.Status != http.StatusBadRequest {
+			return
+		}
+	}
+
+	value, err := json.Marshal(result)
+	if err != nil {
+		logger.Error("Error encoding result to cache", err)
+		return
+	}
+
+	err = svc.redis.Set(
+		resultCacheKey(schemasVersion, profileStr),
+		string(value),
+		config.Values.Cache.ResultTTL,
+	)
+	if err != nil {
+		logger.Error("Error setting cached result in Redis", err)
+	}
+}
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/dateutil"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/httputil"
@@ -13,6 +16,7 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilehasher"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/profile/profilevalidator"
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
+	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/urlutil"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/model"
 )
@@ -20,11 +24,34 @@ import (
 const DefaultSchema = "default-v2.1.0"
 
 type ValidationService interface {
-	ValidateNode(node *model.Node)
+	ValidateNode(node *model.Node) error
+	SetRateLimited(node *model.Node)
+}
+
+// RetryLaterError is returned by ValidateNode when the profile host asked to
+// be contacted again later, e.g. with 429 Too Many Requests.
+type RetryLaterError struct {
+	ProfileURL string
+	After      time.Duration
+}
+
+// Error conforms to go conventions.
+func (e RetryLaterError) Error() string {
+	return fmt.Sprintf(
+		"the host of %s asked to retry after %s",
+		e.ProfileURL,
+		e.After,
+	)
 }
 
 type validationService struct {
-	redis redis.Redis
+	redis   redis.Redis
+	fetcher *httputil.Fetcher
+	// schemas keeps the compiled schemas of the library.
+	schemas *profilevalidator.SchemaCache
+
+	mu                 sync.Mutex
+	lastSchemasVersion string
 }
 
 func NewValidationService(
@@ -32,57 +59,100 @@ func NewValidationService(
 ) ValidationService {
 	return &validationService{
 		redis: redis,
+		fetcher: httputil.NewFetcher(httputil.FetcherOptions{
+			MaxBodySize:  config.Values.Fetch.MaxBodySize,
+			MaxJSONDepth: config.Values.Fetch.MaxJSONDepth,
+			AllowedHosts: config.Values.Fetch.AllowedHosts,
+		}),
+		schemas: profilevalidator.NewSchemaCache(
+			&profilevalidator.URLSchemaLoader{
+				BaseURL: config.Values.Library.InternalURL,
+			},
+		),
 	}
 }
 
-func (svc *validationService) ValidateNode(node *model.Node) {
-	profileStr, err := httputil.GetJSONStr(node.ProfileURL)
+// ValidateNode validates the profile of the node and publishes the result.
+// It returns a RetryLaterError, without publishing anything, if the profile
+// host is rate limiting requests.
+func (svc *validationService) ValidateNode(node *model.Node) error {
+	// The node keeps the profile URL it is stored under; the canonical form
+	// is only used to fetch the profile.
+	profileURL, err := urlutil.Canonicalize(node.ProfileURL)
 	if err != nil {
 		errors := jsonapi.NewError(
-			[]string{"Profile Not Found"},
+			[]string{"Invalid Profile URL"},
 			[]string{
 				fmt.Sprintf(
-					"Could not find or read from (invalid JSON) the profile_url: %s",
+					"The profile_url is not a valid http(s) URL: %s",
 					node.ProfileURL,
 				),
 			},
 			nil,
-			[]int{http.StatusNotFound},
-		)
-		logger.Info(
-			"Failed to read from profile URL: " + fmt.Sprintf("%v", errors),
+			[]int{http.StatusBadRequest},
 		)
 		svc.sendNodeValidationFailedEvent(node, &errors)
-		return
+		return nil
 	}
 
-	if err := svc.validateAgainstDefaultSchema(profileStr, node); err != nil {
-		return
+	schemasVersion := svc.schemasVersion()
+	cached := svc.getFetchCache(node.ProfileURL)
+	if cached.SchemasVersion != schemasVersion || !cached.hasResult() {
+		// The schemas changed since the profile was last validated, or there
+		// is no result to reuse: fetch and validate the profile in full.
+		cached = &fetchCacheEntry{}
 	}
-	if err := svc.validateAgainstLinkedSchemas(profileStr, node); err != nil {
-		return
+	profileStr, fetchInfo, err := svc.fetcher.FetchJSONStrIfModified(
+		profileURL,
+		cached.ETag,
+		cached.LastModified,
+	)
+	var statusErr httputil.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusGone {
+		// 410 Gone means the owner removed the profile on purpose.
+		logger.Info("Profile URL is gone: " + node.ProfileURL)
+		svc.sendNodeGoneEvent(node)
+		return nil
+	}
+	if errors.As(err, &statusErr) && isRateLimited(statusErr) {
+		return RetryLaterError{
+			ProfileURL: node.ProfileURL,
+			After:      retryDelay(statusErr.RetryAfter),
+		}
 	}
-
-	profileHash, err := profilehasher.NewFromString(profileStr, config.Values.Library.InternalURL).
-		Hash()
 	if err != nil {
-		logger.Error("Failed to generate a hash for the profile_url: ", err)
-		errors := jsonapi.NewError(
-			[]string{"Profile Hashing Failed"},
-			[]string{
-				fmt.Sprintf(
-					"Failed to generate a hash for the profile_url: %s. Please try again later.",
-					node.ProfileURL,
-				),
-			},
-			nil,
-			[]int{http.StatusInternalServerError},
+		errors := httputil.FetchErrorToJSONAPI(err, node.ProfileURL)
+		logger.Info(
+			"Failed to read from profile URL: " + fmt.Sprintf("%v", errors),
 		)
 		svc.sendNodeValidationFailedEvent(node, &errors)
-		return
+		return nil
+	}
+
+	if fetchInfo.NotModified {
+		// The profile hasn't changed since it was last validated.
+		logger.Info("Profile is unchanged: " + node.ProfileURL)
+		svc.sendUnchangedNodeValidatedEvent(node, cached, fetchInfo)
+		return nil
+	}
+
+	result := svc.getResultCache(schemasVersion, profileStr)
+	if result == nil {
+		result, err = svc.validateProfile(profileStr, node)
+		if err != nil {
+			return nil
+		}
+		svc.setResultCache(schemasVersion, profileStr, result)
+	}
+	if len(result.Errors) > 0 {
+		svc.sendNodeValidationFailedEvent(node, &result.Errors)
+		return nil
 	}
 
 	updatedProfileJSON := jsonutil.ToJSON(profileStr)
+	// The quality is scored on the primary URL as published.
+	factors := svc.qualityFactors(updatedProfileJSON, result)
+	primaryURL, _ := updatedProfileJSON["primary_url"].(string)
 	if updatedProfileJSON["primary_url"] != nil {
 		normalizedURL, err := NormalizeURL(
 			updatedProfileJSON["primary_url"].(string),
@@ -100,7 +170,7 @@ func (svc *validationService) ValidateNode(node *model.Node) {
 				[]int{http.StatusBadRequest},
 			)
 			svc.sendNodeValidationFailedEvent(node, &errors)
-			return
+			return nil
 		}
 		updatedProfileJSON["primary_url"] = normalizedURL
 	}
@@ -118,7 +188,7 @@ func (svc *validationService) ValidateNode(node *model.Node) {
 				[]int{http.StatusBadRequest},
 			)
 			svc.sendNodeValidationFailedEvent(node, &errors)
-			return
+			return nil
 		}
 	} else if expAt, ok := updatedProfileJSON["expires_at"]; ok {
 		var err error
@@ -131,31 +201,126 @@ func (svc *validationService) ValidateNode(node *model.Node) {
 				[]int{http.StatusBadRequest},
 			)
 			svc.sendNodeValidationFailedEvent(node, &errors)
-			return
+			return nil
 		}
 	}
 
-	err = messaging.Publish(
-		messaging.NodeValidated,
-		messaging.NodeValidatedData{
-			ProfileURL:  node.ProfileURL,
-			ProfileHash: profileHash,
-			// Provides the updated version of the profile for later use.
-			ProfileStr:  jsonutil.ToString(updatedProfileJSON),
-			LastUpdated: dateutil.GetNowUnix(),
-			Version:     node.Version,
-			Expires:     expires,
+	validated := messaging.NodeValidatedData{
+		ProfileURL:  node.ProfileURL,
+		ProfileHash: result.ProfileHash,
+		// Provides the updated version of the profile for later use.
+		ProfileStr:  jsonutil.ToString(updatedProfileJSON),
+		LastUpdated: dateutil.GetNowUnix(),
+		Version:     node.Version,
+		Expires:     expires,
+		FinalURL:    finalURL(fetchInfo),
+		Redirects:   fetchInfo.Redirects,
+		Moved:       fetchInfo.IsPermanentlyMoved(),
+		Warnings:    result.Warnings,
+		Quality:     &factors,
+		PrimaryURL:  primaryURL,
+
+		SchemaRevisions: result.SchemaRevisions,
+	}
+	err = messaging.Publish(messaging.NodeValidated, validated)
+	if err != nil {
+		logger.Error("Failed to publish: ", err)
+		return nil
+	}
+	svc.setFetchCache(node.ProfileURL, schemasVersion, fetchInfo, validated)
+	return nil
+}
+
+// SetRateLimited publishes the validation failure of a node whose profile
+// host kept rate limiting requests.
+func (svc *validationService) SetRateLimited(node *model.Node) {
+	errors := jsonapi.NewError(
+		[]string{"Profile Rate Limited"},
+		[]string{
+			fmt.Sprintf(
+				"The host of the profile_url kept rejecting requests with too many requests: %s",
+				node.ProfileURL,
+			),
 		},
+		nil,
+		[]int{http.StatusTooManyRequests},
 	)
-	if err != nil {
+	svc.sendNodeValidationFailedEvent(node, &errors)
+}
+
+// isRateLimited reports whether the response asks the client to slow down:
+// 429 Too Many Requests, or 503 Service Unavailable with a Retry-After header.
+func isRateLimited(err httputil.StatusError) bool {
+	return err.StatusCode == http.StatusTooManyRequests ||
+		err.StatusCode == http.StatusServiceUnavailable && err.RetryAfter > 0
+}
+
+// retryDelay returns the delay before retrying a rate limited request,
+// bounded by the configured maximum.
+func retryDelay(retryAfter time.Duration) time.Duration {
+	if retryAfter <= 0 {
+		retryAfter = config.Values.Scheduler.RetryDelay
+	}
+	if retryAfter > config.Values.Scheduler.MaxRetryDelay {
+		retryAfter = config.Values.Scheduler.MaxRetryDelay
+	}
+	return retryAfter
+}
+
+// sendUnchangedNodeValidatedEvent publishes the cached validation result of
+// a profile that responded with 304 Not Modified, without validating it again.
+func (svc *validationService) sendUnchangedNodeValidatedEvent(
+	node *model.Node,
+	cached *fetchCacheEntry,
+	fetchInfo *httputil.FetchInfo,
+) {
+	validated := cached.Validated
+	validated.ProfileURL = node.ProfileURL
+	validated.Version = node.Version
+	validated.FinalURL = finalURL(fetchInfo)
+	validated.Redirects = fetchInfo.Redirects
+	validated.Moved = fetchInfo.IsPermanentlyMoved()
+
+	if err := messaging.Publish(messaging.NodeValidated, validated); err != nil {
 		logger.Error("Failed to publish: ", err)
 	}
 }
 
+// finalURL returns the URL the profile was fetched from if redirects were
+// followed, or an empty string otherwise.
+func finalURL(info *httputil.FetchInfo) string {
+	if len(info.Redirects) == 0 {
+		return ""
+	}
+	return info.FinalURL
+}
+
+func (svc *validationService) sendNodeGoneEvent(node *model.Node) {
+	svc.clearFetchCache(node.ProfileURL)
+
+	eventData := messaging.NodeGoneData{
+		ProfileURL: node.ProfileURL,
+		Version:    node.Version,
+	}
+
+	if err := messaging.Publish(messaging.NodeGone, eventData); err != nil {
+		logger.Error(
+			fmt.Sprintf(
+				"failed to send NodeGone event for node %s (version: %d)",
+				node.ProfileURL,
+				node.Version,
+			),
+			err,
+		)
+	}
+}
+
 func (svc *validationService) sendNodeValidationFailedEvent(
 	node *model.Node,
 	failureReasons *[]jsonapi.Error,
 ) {
+	svc.clearFetchCache(node.ProfileURL)
+
 	eventData := messaging.NodeValidationFailedData{
 		ProfileURL:     node.ProfileURL,
 		FailureReasons: failureReasons,
@@ -181,65 +346,81 @@ func (svc *validationService) sendNodeValidationFailedEvent(
 	}
 }
 
-// validateAgainstDefaultSchema handles the validation of the node's profile
-// against the default schema.
-func (svc *validationService) validateAgainstDefaultSchema(
+// validateProfile validates the profile body against the default schema and
+// its linked schemas, collecting their warnings, and hashes it if it is valid.
+// It returns an error, after publishing the failure, only if the profile
+// couldn't be validated at all.
+func (svc *validationService) validateProfile(
 	profileStr string,
 	node *model.Node,
-) error {
-	validator, err := profilevalidator.NewBuilder().
-		WithStrProfile(profileStr).
-		WithURLSchemas(config.Values.Library.InternalURL, []string{DefaultSchema}).
-		Build()
+) (*profileResult, error) {
+	result := &profileResult{}
+
+	validation, err := svc.validateAgainstSchemas(profileStr, node, []string{DefaultSchema})
 	if err != nil {
-		logger.Error("Failed to build schema validator", err)
-		errors := jsonapi.NewError(
-			[]string{"Internal Server Error"},
-			[]string{
-				"An error occurred while validating the profile data. Please try again later.",
-			},
-			nil,
-			[]int{http.StatusInternalServerError},
-		)
-		svc.sendNodeValidationFailedEvent(node, &errors)
-		return err
+		return nil, err
 	}
-
-	result := validator.Validate()
-	if !result.Valid {
-		errors := jsonapi.NewError(
-			result.ErrorMessages,
-			result.Details,
-			result.Sources,
-			result.ErrorStatus,
-		)
-		svc.sendNodeValidationFailedEvent(node, &errors)
-		return fmt.Errorf("validation failed")
+	result.addWarnings(validation)
+	if !validation.Valid {
+		result.Errors = validation.Errors()
+		return result, nil
 	}
 
-	return nil
-}
-
-// validateAgainstLinkedSchemas handles the extraction and validation of the node's profile against linked schemas.
-func (svc *validationService) validateAgainstLinkedSchemas(
-	profileStr string,
-	node *model.Node,
-) error {
 	linkedSchemas, err := getLinkedSchemas(profileStr)
 	if err != nil {
-		errors := jsonapi.NewError(
+		result.Errors = jsonapi.NewError(
 			[]string{"Profile Validation Error"},
 			[]string{err.Error()},
 			nil,
 			[]int{http.StatusBadRequest},
 		)
+		return result, nil
+	}
+
+	validation, err = svc.validateAgainstSchemas(profileStr, node, linkedSchemas)
+	if err != nil {
+		return nil, err
+	}
+	result.addWarnings(validation)
+	if !validation.Valid {
+		result.Errors = validation.Errors()
+		return result, nil
+	}
+
+	profileHash, err := profilehasher.NewFromString(profileStr, config.Values.Library.InternalURL).
+		Hash()
+	if err != nil {
+		logger.Error("Failed to generate a hash for the profile_url: ", err)
+		errors := jsonapi.NewError(
+			[]string{"Profile Hashing Failed"},
+			[]string{
+				fmt.Sprintf(
+					"Failed to generate a hash for the profile_url: %s. Please try again later.",
+					node.ProfileURL,
+				),
+			},
+			nil,
+			[]int{http.StatusInternalServerError},
+		)
 		svc.sendNodeValidationFailedEvent(node, &errors)
-		return err
+		return nil, err
 	}
 
+	result.ProfileHash = profileHash
+	return result, nil
+}
+
+// validateAgainstSchemas validates the profile against the given schemas of
+// the library.
+func (svc *validationService) validateAgainstSchemas(
+	profileStr string,
+	node *model.Node,
+	schemas []string,
+) (*profilevalidator.ValidationResult, error) {
 	validator, err := profilevalidator.NewBuilder().
 		WithStrProfile(profileStr).
-		WithURLSchemas(config.Values.Library.InternalURL, linkedSchemas).
+		WithURLSchemas(config.Values.Library.InternalURL, schemas).
+		WithSchemaLoader(svc.schemas).
 		Build()
 	if err != nil {
 		logger.Error("Failed to build schema validator", err)
@@ -252,22 +433,10 @@ func (svc *validationService) validateAgainstLinkedSchemas(
 			[]int{http.StatusInternalServerError},
 		)
 		svc.sendNodeValidationFailedEvent(node, &errors)
-		return err
-	}
-
-	result := validator.Validate()
-	if !result.Valid {
-		errors := jsonapi.NewError(
-			result.ErrorMessages,
-			result.Details,
-			result.Sources,
-			result.ErrorStatus,
-		)
-		svc.sendNodeValidationFailedEvent(node, &errors)
-		return fmt.Errorf("validation against linked schemas failed")
+		return nil, err
 	}
 
-	return nil
+	return validator.Validate(), nil
 }
 
 func getLinkedSchemas(profileStr string) ([]string, error) {
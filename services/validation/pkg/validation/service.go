@@ -20,6 +20,7 @@ import (
 	"github.com/MurmurationsNetwork/MurmurationsServices/pkg/redis"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/config"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/controller/event"
+	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/scheduler"
 	"github.com/MurmurationsNetwork/MurmurationsServices/services/validation/internal/service"
 )
 
@@ -31,6 +32,8 @@ type Service struct {
 	isRunning *abool.AtomicBool
 	// Node event handler
 	nodeHandler event.NodeHandler
+	// Scheduler running validations fairly across profile hosts
+	scheduler *scheduler.Scheduler
 	// Ensures cleanup is only run once
 	runCleanup sync.Once
 	// Context for shutdown
@@ -53,9 +56,16 @@ func NewService() *Service {
 	}
 
 	svc.setupServer()
+	svc.scheduler = scheduler.New(scheduler.Options{
+		Workers:         config.Values.Scheduler.Workers,
+		HostConcurrency: config.Values.Scheduler.HostConcurrency,
+		HostInterval:    config.Values.Scheduler.HostInterval,
+		HostQueueSize:   config.Values.Scheduler.HostQueueSize,
+	})
 	svc.nodeHandler = event.NewNodeHandler(
 		redisClient,
 		service.NewValidationService(redisClient),
+		svc.scheduler,
 	)
 	core.InstallShutdownHandler(svc.Shutdown)
 
@@ -104,6 +114,7 @@ func (s *Service) panic(msg string, err error, logFields ...zapcore.Field) {
 // Run starts the validation service and will block until the service is shutdown.
 func (s *Service) Run() {
 	s.isRunning.Set()
+	s.scheduler.Start()
 	if err := s.nodeHandler.NewNodeCreatedListener(); err != nil &&
 		err != http.ErrServerClosed {
 		s.panic("Error when trying to listen events", err)
@@ -156,6 +167,9 @@ func (s *Service) cleanup() {
 		// Shutdown the context.
 		s.shutdownCancelCtx()
 
+		// Let running validations finish; queued ones are redelivered.
+		s.scheduler.Stop()
+
 		// Disconnect from NATS.
 		if err := natsclient.GetInstance().Disconnect(); err != nil {
 			logger.Error("Error disconnecting from NATS: %v", err)